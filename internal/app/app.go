@@ -25,6 +25,7 @@ import (
 	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
 	"github.com/sentiric/sentiric-dialplan-service/internal/repository/postgres"
 	platformServer "github.com/sentiric/sentiric-dialplan-service/internal/server"
+	"github.com/sentiric/sentiric-dialplan-service/internal/server/admin"
 	grpchandler "github.com/sentiric/sentiric-dialplan-service/internal/server/grpc"
 	"github.com/sentiric/sentiric-dialplan-service/internal/service/dialplan"
 )
@@ -65,7 +66,26 @@ func (a *App) Run() {
 	// 2. Bağımlılıkların Oluşturulması
 	repo := postgres.NewRepository(dbPool, a.Log)
 	userCache := cache.NewUserCache(redisClient)
-	dialplanSvc := dialplan.NewService(repo, userClient, userCache, a.Log)
+	numbering, err := dialplan.NewNumbering(a.Cfg.Numbering.DefaultCountry, a.Cfg.Numbering.TenantCountries)
+	if err != nil {
+		a.Log.Error().Err(err).Str("event", logger.EventNumberingConfigInvalid).Msg("Numaralandırma konfigürasyonu geçersiz, varsayılan (TR) kurallar kullanılıyor.")
+	}
+	dialplanSvc := dialplan.NewService(repo, userClient, userCache, a.Log,
+		dialplan.WithNumbering(numbering),
+		dialplan.WithTraceStore(cache.NewTraceCache(redisClient)),
+		dialplan.WithTraceSampleRate(a.Cfg.TraceSampleRate),
+		dialplan.WithScheduleCacheTTL(a.Cfg.Schedule.CacheTTL),
+		dialplan.WithRateLimiter(cache.NewRateLimiter(redisClient), dialplan.RateLimits{
+			PerCaller:          dialplan.RateLimit(a.Cfg.RateLimit.PerCaller),
+			PerDestination:     dialplan.RateLimit(a.Cfg.RateLimit.PerDestination),
+			PerTenant:          dialplan.RateLimit(a.Cfg.RateLimit.PerTenant),
+			ThrottleDialplanID: a.Cfg.RateLimit.ThrottleDialplanID,
+			CheckTimeout:       a.Cfg.RateLimit.CheckTimeout,
+		}),
+		dialplan.WithCallTracker(cache.NewCallTracker(redisClient), a.Cfg.CallTTL),
+		dialplan.WithAgentStateStore(cache.NewAgentStateStore(redisClient)),
+		dialplan.WithQueueTracker(cache.NewQueueTracker(redisClient), a.Cfg.QueueServiceLevel),
+	)
 	handler := grpchandler.NewHandler(dialplanSvc, a.Log)
 
 	// 3. gRPC Sunucusu
@@ -78,10 +98,16 @@ func (a *App) Run() {
 
 	// 4. Sunucuları Başlat (Anında Port Açılır)
 	httpServer := a.startHttpServer()
+	adminServer := a.startAdminServer(dialplanSvc)
 	a.startGRPCServer(grpcServer)
 
+	// Arka plan bakım görevleri kapanışta durdurulur.
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	a.startOverridePurger(bgCtx, dialplanSvc)
+
 	// 5. Graceful Shutdown
-	a.waitForShutdown(grpcServer, httpServer)
+	a.waitForShutdown(grpcServer, httpServer, adminServer)
 }
 
 func (a *App) setupRedis() *redis.Client {
@@ -121,6 +147,30 @@ func (a *App) startHttpServer() *http.Server {
 	return srv
 }
 
+// startAdminServer: /admin/* uç noktalarını (karar izi, takvim aboneliği) token korumalı, ayrı bir adreste açar.
+// Bu uç noktalar arayan numaraları ve takvim detayları döndüğü için health/metrics portunda yayınlanmaz.
+// Devre dışıysa veya token tanımlı değilse nil döner.
+func (a *App) startAdminServer(svc *dialplan.Service) *http.Server {
+	if !a.Cfg.Server.AdminHTTPEnabled {
+		return nil
+	}
+	if a.Cfg.Server.AdminToken == "" {
+		a.Log.Error().Str("event", logger.EventAdminHTTPDisabled).Msg("DIALPLAN_ADMIN_TOKEN tanımlı değil, admin uç noktaları açılmadı.")
+		return nil
+	}
+	mux := http.NewServeMux()
+	admin.RegisterRoutes(mux, svc, a.Log)
+	srv := &http.Server{Addr: a.Cfg.Server.AdminHTTPAddr, Handler: admin.RequireToken(a.Cfg.Server.AdminToken, mux)}
+
+	go func() {
+		a.Log.Info().Str("event", logger.EventAdminHTTPServerStart).Str("addr", a.Cfg.Server.AdminHTTPAddr).Msg("Admin HTTP sunucusu dinleniyor...")
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			a.Log.Fatal().Err(err).Str("event", logger.EventHTTPServerFail).Msg("Admin HTTP sunucusu başlatılamadı")
+		}
+	}()
+	return srv
+}
+
 func (a *App) startGRPCServer(srv *grpc.Server) {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", a.Cfg.Server.GRPCPort))
 	if err != nil {
@@ -135,7 +185,34 @@ func (a *App) startGRPCServer(srv *grpc.Server) {
 	}()
 }
 
-func (a *App) waitForShutdown(grpcSrv *grpc.Server, httpSrv *http.Server) {
+// startOverridePurger: Süresi dolmuş takvim istisnalarını periyodik olarak temizler.
+func (a *App) startOverridePurger(ctx context.Context, svc *dialplan.Service) {
+	interval := a.Cfg.Schedule.OverridePurgeInterval
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				purged, err := svc.PurgeExpiredOverrides(ctx)
+				if err != nil {
+					a.Log.Warn().Err(err).Str("event", logger.EventScheduleOverridePurgeFail).Msg("Süresi dolmuş takvim istisnaları temizlenemedi.")
+					continue
+				}
+				if purged > 0 {
+					a.Log.Info().Str("event", logger.EventScheduleOverridesPurged).Int64("count", purged).Msg("Süresi dolmuş takvim istisnaları temizlendi.")
+				}
+			}
+		}
+	}()
+}
+
+func (a *App) waitForShutdown(grpcSrv *grpc.Server, httpSrvs ...*http.Server) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
@@ -148,9 +225,14 @@ func (a *App) waitForShutdown(grpcSrv *grpc.Server, httpSrv *http.Server) {
 	a.Log.Info().Str("event", logger.EventGRPCServerStop).Msg("gRPC sunucusu durduruluyor...")
 	grpcSrv.GracefulStop()
 
-	a.Log.Info().Str("event", logger.EventHTTPServerStop).Msg("HTTP sunucusu durduruluyor...")
-	if err := httpSrv.Shutdown(ctx); err != nil {
-		a.Log.Error().Err(err).Str("event", logger.EventHTTPServerFail).Msg("HTTP sunucusu düzgün kapatılamadı.")
+	a.Log.Info().Str("event", logger.EventHTTPServerStop).Msg("HTTP sunucuları durduruluyor...")
+	for _, srv := range httpSrvs {
+		if srv == nil {
+			continue
+		}
+		if err := srv.Shutdown(ctx); err != nil {
+			a.Log.Error().Err(err).Str("event", logger.EventHTTPServerFail).Str("addr", srv.Addr).Msg("HTTP sunucusu düzgün kapatılamadı.")
+		}
 	}
 
 	a.Log.Info().Str("event", logger.EventSystemShutdown).Msg("Servis başarıyla durduruldu.")
@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AgentStateTTL: Durum bildirmeyen ajanın kaydı bu süre sonunda düşer (çevrimdışı sayılır).
+// Her durum bildirimi süreyi yeniler.
+const AgentStateTTL = 12 * time.Hour
+
+// AgentReservationTTL: Seçilen ajanın rezervasyonu bu süre içinde "busy" (bağlandı) veya "available"
+// (bağlanamadı) bildirimiyle kapanmazsa kendiliğinden düşer ve ajan yeniden seçilebilir.
+const AgentReservationTTL = 30 * time.Second
+
+// setAgentStatusScript: Ajan durumunu günceller ve geçişe göre sayaçları işler (durumlar dialplan.AgentStatus*):
+//   - Çevrimdışından (veya kayıt yokken) çıkış yeni bir oturumdur; çağrı sayacı sıfırlanır.
+//   - "busy" durumuna her geçiş bir çağrı sayılır.
+//   - "available" durumuna her geçiş boşta kalma süresini yeniden başlatır; rezervasyonun bırakılması
+//     (reserved -> available) ajan çağrı almadığı için boşta kalma süresini korur.
+//   - Her bildirim açık rezervasyonu kapatır.
+//
+// Zaman Redis'ten (TIME) alınır, böylece replikalar arası saat kayması sıralamayı bozmaz.
+// Dönüş: {status, idle_since (ms), calls}
+var setAgentStatusScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local status = ARGV[1]
+local old = redis.call('HGET', KEYS[1], 'status')
+if (not old or old == 'offline') and status ~= 'offline' then
+	redis.call('HSET', KEYS[1], 'calls', 0)
+end
+if status == 'busy' and old ~= 'busy' then
+	redis.call('HINCRBY', KEYS[1], 'calls', 1)
+end
+if status == 'available' and old ~= 'available' and old ~= 'reserved' then
+	redis.call('HSET', KEYS[1], 'idle_since', now)
+end
+redis.call('HDEL', KEYS[1], 'reserved_until')
+redis.call('HSET', KEYS[1], 'status', status)
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return redis.call('HMGET', KEYS[1], 'status', 'idle_since', 'calls')
+`)
+
+// claimAgentScript: Ajanı yalnızca müsaitse (veya rezervasyonu süresi dolmuşsa) atomik olarak "reserved"
+// durumuna alır; böylece aynı anda seçim yapan replikalar aynı ajanı alamaz. Boşta kalma süresi ve çağrı
+// sayacı değişmez. Dönüş: 1 = rezerve edildi, 0 = ajan müsait değil.
+var claimAgentScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local cur = redis.call('HMGET', KEYS[1], 'status', 'reserved_until')
+local status = cur[1]
+if status == 'reserved' and tonumber(cur[2] or '0') <= now then
+	status = 'available'
+end
+if status ~= 'available' then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'status', 'reserved', 'reserved_until', now + tonumber(ARGV[1]))
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return 1
+`)
+
+// AgentState: Ajanın anlık ACD durumu.
+type AgentState struct {
+	Status        string
+	IdleSince     time.Time // Son "available" geçişi; hiç müsait olmadıysa sıfır
+	Calls         int       // Oturum boyunca alınan çağrı sayısı
+	ReservedUntil time.Time // "reserved" durumunun düşeceği an; rezervasyon yoksa sıfır
+}
+
+// AgentStateStore: Ajan durumlarını ve kuyruk round-robin imleçlerini Redis'te tutar; böylece
+// tüm replikalar aynı durumu görür.
+type AgentStateStore struct {
+	redis *redis.Client
+}
+
+func NewAgentStateStore(redisClient *redis.Client) *AgentStateStore {
+	return &AgentStateStore{redis: redisClient}
+}
+
+// SetAgentStatus: Ajanın durumunu yazar ve güncel durumu döner.
+func (s *AgentStateStore) SetAgentStatus(ctx context.Context, agentID, status string) (AgentState, error) {
+	res, err := setAgentStatusScript.Run(ctx, s.redis, []string{agentKey(agentID)}, status, AgentStateTTL.Milliseconds()).Slice()
+	if err != nil {
+		return AgentState{}, err
+	}
+	return parseAgentState(res[0], res[1], res[2], nil), nil
+}
+
+// ClaimAgent: Ajanı müsaitse ttl süresince rezerve eder; ajan başka bir seçimce alınmışsa false döner.
+func (s *AgentStateStore) ClaimAgent(ctx context.Context, agentID string, ttl time.Duration) (bool, error) {
+	n, err := claimAgentScript.Run(ctx, s.redis, []string{agentKey(agentID)}, ttl.Milliseconds(), AgentStateTTL.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// AgentStates: Ajanların durumlarını tek seferde okur. Kaydı olmayan ajanlar sonuçta yer almaz.
+func (s *AgentStateStore) AgentStates(ctx context.Context, agentIDs []string) (map[string]AgentState, error) {
+	pipe := s.redis.Pipeline()
+	cmds := make([]*redis.SliceCmd, len(agentIDs))
+	for i, id := range agentIDs {
+		cmds[i] = pipe.HMGet(ctx, agentKey(id), "status", "idle_since", "calls", "reserved_until")
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+	states := make(map[string]AgentState, len(agentIDs))
+	for i, cmd := range cmds {
+		vals := cmd.Val()
+		if len(vals) != 4 || vals[0] == nil {
+			continue
+		}
+		states[agentIDs[i]] = parseAgentState(vals[0], vals[1], vals[2], vals[3])
+	}
+	return states, nil
+}
+
+// NextRoundRobin: Kuyruğun round-robin imlecini ilerletir ve yeni değeri döner (1'den başlar).
+func (s *AgentStateStore) NextRoundRobin(ctx context.Context, queueID string) (int64, error) {
+	return s.redis.Incr(ctx, fmt.Sprintf("dialplan:acd:rr:%s", queueID)).Result()
+}
+
+func parseAgentState(status, idleSince, calls, reservedUntil interface{}) AgentState {
+	state := AgentState{Status: redisString(status)}
+	if ms, err := strconv.ParseInt(redisString(idleSince), 10, 64); err == nil {
+		state.IdleSince = time.UnixMilli(ms)
+	}
+	if ms, err := strconv.ParseInt(redisString(reservedUntil), 10, 64); err == nil {
+		state.ReservedUntil = time.UnixMilli(ms)
+	}
+	state.Calls, _ = strconv.Atoi(redisString(calls))
+	return state
+}
+
+// redisString: Lua (int64) ve HMGET (string / nil) dönüşlerini string'e çevirir.
+func redisString(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case int64:
+		return strconv.FormatInt(x, 10)
+	}
+	return ""
+}
+
+func agentKey(agentID string) string {
+	return fmt.Sprintf("dialplan:acd:agent:%s", agentID)
+}
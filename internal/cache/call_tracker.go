@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// acquireCallScript: Tenant ve route kümelerinden süresi dolan çağrıları temizler; kapasite varsa
+// çağrıyı iki kümeye de (skor: bitiş zamanı, ms) ekler ve çağrı => küme eşlemesini yazar.
+// Aynı çağrı tekrar çözülürse kapasiteye ikinci kez sayılmaz, yalnızca süresi uzar.
+// Dönüş: 0 => kapasite ayrıldı, 1 => tenant dolu, 2 => route dolu.
+var acquireCallScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local ttl = tonumber(ARGV[4])
+local limits = {tonumber(ARGV[2]), tonumber(ARGV[3])}
+for i = 1, 2 do
+	redis.call('ZREMRANGEBYSCORE', KEYS[i], '-inf', now)
+	if limits[i] > 0 and not redis.call('ZSCORE', KEYS[i], ARGV[1]) and redis.call('ZCARD', KEYS[i]) >= limits[i] then
+		return i
+	end
+end
+for i = 1, 2 do
+	redis.call('ZADD', KEYS[i], now + ttl, ARGV[1])
+	redis.call('PEXPIRE', KEYS[i], ttl)
+end
+redis.call('SET', KEYS[3], KEYS[1] .. '\n' .. KEYS[2], 'PX', ttl)
+return 0
+`)
+
+// releaseCallScript: Çağrıyı eşlemesindeki kümelerden düşürür. Dönüş: 1 => bırakıldı, 0 => kayıt yok.
+// Küme anahtarları eşlemeden okunduğu için betik tek node'lu (cluster olmayan) Redis varsayar.
+var releaseCallScript = redis.NewScript(`
+local sets = redis.call('GET', KEYS[1])
+if not sets then
+	return 0
+end
+local sep = string.find(sets, '\n', 1, true)
+redis.call('ZREM', string.sub(sets, 1, sep - 1), ARGV[1])
+redis.call('ZREM', string.sub(sets, sep + 1), ARGV[1])
+redis.call('DEL', KEYS[1])
+return 1
+`)
+
+// CallTracker: Tenant ve route bazında aktif çağrı kapasitesini Redis sorted set'lerinde izler.
+type CallTracker struct {
+	redis *redis.Client
+}
+
+func NewCallTracker(redisClient *redis.Client) *CallTracker {
+	return &CallTracker{redis: redisClient}
+}
+
+// Acquire: Çağrı için kapasite ayırır. Limit 0 ise o kapsam sınırsızdır ancak çağrı yine sayılır.
+// Kapasite doluysa dolu olan kapsamı ("tenant" veya "route") döner.
+func (c *CallTracker) Acquire(ctx context.Context, callID, tenantID, routeKey string, tenantLimit, routeLimit int, ttl time.Duration) (string, error) {
+	res, err := acquireCallScript.Run(ctx, c.redis,
+		[]string{callSetKey("tenant", tenantID), callSetKey("route", routeKey), callKey(callID)},
+		callID, tenantLimit, routeLimit, ttl.Milliseconds()).Int()
+	if err != nil {
+		return "", err
+	}
+	switch res {
+	case 1:
+		return "tenant", nil
+	case 2:
+		return "route", nil
+	}
+	return "", nil
+}
+
+// Release: Çağrının kapasitesini bırakır; çağrı bulunamadıysa (süresi dolmuş veya hiç sayılmamış) false döner.
+func (c *CallTracker) Release(ctx context.Context, callID string) (bool, error) {
+	res, err := releaseCallScript.Run(ctx, c.redis, []string{callKey(callID)}, callID).Int()
+	return res == 1, err
+}
+
+// ActiveCalls: Tenant'ın süresi dolmamış aktif çağrı sayısı.
+func (c *CallTracker) ActiveCalls(ctx context.Context, tenantID string) (int64, error) {
+	now := time.Now().UnixMilli()
+	return c.redis.ZCount(ctx, callSetKey("tenant", tenantID), fmt.Sprintf("(%d", now), "+inf").Result()
+}
+
+func callSetKey(scope, id string) string {
+	return fmt.Sprintf("dialplan:calls:%s:%s", scope, id)
+}
+
+func callKey(callID string) string {
+	return fmt.Sprintf("dialplan:calls:call:%s", callID)
+}
@@ -0,0 +1,269 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// queueHandleSamples: Ortalama görüşme süresi (AHT) için saklanan son tamamlanmış çağrı sayısı.
+	queueHandleSamples = 50
+	// queueStatsTTL: Günlük kuyruk sayaçlarının saklanma süresi.
+	queueStatsTTL = 48 * time.Hour
+	// queueAgentCallTTL: Cevaplanan çağrı ile ajan arasındaki bağın (AHT ölçümü için) azami ömrü.
+	queueAgentCallTTL = 4 * time.Hour
+	// QueueCallTTL: Dequeue/abandon bildirimi hiç gelmeyen (örn: çöken istemci) bekleyen çağrı, geliş zamanından
+	// max(kuyruğun max_wait süresi, QueueCallTTL) sonra kuyruktan sessizce düşer; sayaçlara işlenmez.
+	QueueCallTTL = 4 * time.Hour
+	// queueSweepBatch: Tek betik çağrısında düşürülen azami eski çağrı sayısı.
+	queueSweepBatch = 500
+)
+
+// queueSweepLua: Geliş zamanı now - maxAge'den eski çağrıları waiting (KEYS[1]) ve arrivals (KEYS[2])
+// kümelerinden siler. waiting skorları öncelik boost'u ile kaydırıldığı için eski çağrılar arrivals'tan bulunur.
+const queueSweepLua = `
+local function sweep(now, maxAge, batch)
+	local stale = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', now - maxAge, 'LIMIT', 0, batch)
+	if #stale > 0 then
+		redis.call('ZREM', KEYS[1], unpack(stale))
+		redis.call('ZREM', KEYS[2], unpack(stale))
+	end
+end
+`
+
+// enqueueScript: Çağrıyı kuyruğa ekler (zaten bekliyorsa yerinde bırakır) ve konumunu döner.
+// waiting kümesi sırayı (geliş zamanı - öncelik boost'u, ms), arrivals kümesi gerçek geliş zamanını (ms) tutar.
+// Önce eski çağrılar süpürülür; kümelerin ömrü her eklemede maxAge'e uzatılır, böylece boşalan kuyruk kendiliğinden silinir.
+// ARGV: callID, boost (ms), maxAge (ms), süpürme limiti
+// Dönüş: {sıra (0'dan), bekleyen sayısı, bekleme (ms)}
+var enqueueScript = redis.NewScript(queueSweepLua + `
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+sweep(now, tonumber(ARGV[3]), tonumber(ARGV[4]))
+redis.call('ZADD', KEYS[1], 'NX', now - tonumber(ARGV[2]), ARGV[1])
+redis.call('ZADD', KEYS[2], 'NX', now, ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+redis.call('PEXPIRE', KEYS[2], ARGV[3])
+local arrival = tonumber(redis.call('ZSCORE', KEYS[2], ARGV[1]))
+return {redis.call('ZRANK', KEYS[1], ARGV[1]), redis.call('ZCARD', KEYS[1]), now - arrival}
+`)
+
+// positionScript: Eski çağrıları süpürür ve bekleyen çağrının konumunu döner.
+// ARGV: callID, maxAge (ms), süpürme limiti
+// Dönüş: {sıra, bekleyen sayısı, bekleme (ms)} veya çağrı yoksa nil.
+var positionScript = redis.NewScript(queueSweepLua + `
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+sweep(now, tonumber(ARGV[2]), tonumber(ARGV[3]))
+local rank = redis.call('ZRANK', KEYS[1], ARGV[1])
+if not rank then
+	return nil
+end
+local arrival = tonumber(redis.call('ZSCORE', KEYS[2], ARGV[1]) or now)
+return {rank, redis.call('ZCARD', KEYS[1]), now - arrival}
+`)
+
+// sweepScript: Eski çağrıları süpürür. ARGV: maxAge (ms), süpürme limiti. Dönüş: yok.
+var sweepScript = redis.NewScript(queueSweepLua + `
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+sweep(now, tonumber(ARGV[1]), tonumber(ARGV[2]))
+return 0
+`)
+
+// leaveScript: Çağrıyı kuyruktan çıkarır ve günlük sayaçları işler.
+// ARGV: callID, sonuç (QueueOutcome*), servis seviyesi eşiği (ms), kuyruk ID, ajan ID (boş olabilir),
+// bağ TTL (ms), sayaç TTL (ms)
+// Cevaplanan çağrıda ajan verilmişse ajan => kuyruk bağı yazılır; görüşme süresi ajan müsait olduğunda ölçülür.
+// Dönüş: bekleme (ms) veya çağrı kuyrukta yoksa -1.
+var leaveScript = redis.NewScript(`
+local arrival = redis.call('ZSCORE', KEYS[2], ARGV[1])
+if not arrival then
+	return -1
+end
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local waited = now - tonumber(arrival)
+redis.call('ZREM', KEYS[1], ARGV[1])
+redis.call('ZREM', KEYS[2], ARGV[1])
+if ARGV[2] == 'answered' then
+	redis.call('HINCRBY', KEYS[3], 'answered', 1)
+	if waited <= tonumber(ARGV[3]) then
+		redis.call('HINCRBY', KEYS[3], 'answered_in_sl', 1)
+	end
+	if ARGV[5] ~= '' then
+		redis.call('SET', KEYS[4], ARGV[4] .. '\n' .. now, 'PX', ARGV[6])
+	end
+else
+	redis.call('HINCRBY', KEYS[3], ARGV[2], 1)
+end
+redis.call('PEXPIRE', KEYS[3], ARGV[7])
+return waited
+`)
+
+// completeAgentCallScript: Ajanın cevapladığı kuyruk çağrısını kapatır ve görüşme süresini kuyruğun son
+// örneklerine ekler. Örnek listesinin anahtarı bağdan okunduğu için betik tek node'lu Redis varsayar.
+// Dönüş: görüşme süresi (ms) veya açık çağrı yoksa -1.
+var completeAgentCallScript = redis.NewScript(`
+local link = redis.call('GET', KEYS[1])
+if not link then
+	return -1
+end
+redis.call('DEL', KEYS[1])
+local sep = string.find(link, '\n', 1, true)
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local handled = now - tonumber(string.sub(link, sep + 1))
+local key = ARGV[1] .. string.sub(link, 1, sep - 1) .. ':handle'
+redis.call('LPUSH', key, handled)
+redis.call('LTRIM', key, 0, tonumber(ARGV[2]) - 1)
+return handled
+`)
+
+// Kuyruktan çıkış sonuçları; günlük sayaç alan adı olarak da kullanılır.
+const (
+	QueueOutcomeAnswered   = "answered"
+	QueueOutcomeAbandoned  = "abandoned"
+	QueueOutcomeOverflowed = "overflowed"
+)
+
+// QueueSlot: Bekleyen bir çağrının kuyruktaki yeri.
+type QueueSlot struct {
+	Position int // 1'den başlar
+	Waiting  int
+	Waited   time.Duration
+}
+
+// QueueStats: Kuyruğun anlık ve günlük (UTC) sayaçları.
+type QueueStats struct {
+	Waiting           int
+	LongestWait       time.Duration
+	Answered          int
+	AnsweredInSL      int
+	Abandoned         int
+	Overflowed        int
+	AverageHandle     time.Duration // Son tamamlanan çağrılar; örnek yoksa 0
+	HandleSampleCount int
+}
+
+// QueueTracker: Kuyrukta bekleyen çağrıları ve kuyruk istatistiklerini Redis'te tutar.
+type QueueTracker struct {
+	redis *redis.Client
+}
+
+func NewQueueTracker(redisClient *redis.Client) *QueueTracker {
+	return &QueueTracker{redis: redisClient}
+}
+
+// queueCallMaxAge: Bekleyen çağrının kuyruktan düşeceği yaş: max(maxWait, QueueCallTTL).
+func queueCallMaxAge(maxWait time.Duration) int64 {
+	return max(maxWait, QueueCallTTL).Milliseconds()
+}
+
+func waitingKeys(queueID string) []string {
+	return []string{queueKey(queueID, "waiting"), queueKey(queueID, "arrivals")}
+}
+
+// Enqueue: Çağrıyı kuyruğa ekler; çağrı zaten bekliyorsa yeri değişmez. boost > 0 ise çağrı, kendisinden
+// en fazla boost kadar önce gelmiş çağrıların önüne yerleşir. maxWait kuyruğun azami bekleme süresidir (0 = sınırsız);
+// Enqueue, Position ve Stats, max(maxWait, QueueCallTTL)'den uzun süredir bekleyen çağrıları kuyruktan düşürür.
+func (t *QueueTracker) Enqueue(ctx context.Context, queueID, callID string, boost, maxWait time.Duration) (QueueSlot, error) {
+	res, err := enqueueScript.Run(ctx, t.redis, waitingKeys(queueID),
+		callID, boost.Milliseconds(), queueCallMaxAge(maxWait), queueSweepBatch).Int64Slice()
+	if err != nil {
+		return QueueSlot{}, err
+	}
+	return queueSlot(res), nil
+}
+
+// Position: Bekleyen çağrının yeri; çağrı kuyrukta değilse false döner.
+func (t *QueueTracker) Position(ctx context.Context, queueID, callID string, maxWait time.Duration) (QueueSlot, bool, error) {
+	res, err := positionScript.Run(ctx, t.redis, waitingKeys(queueID), callID, queueCallMaxAge(maxWait), queueSweepBatch).Int64Slice()
+	if err == redis.Nil {
+		return QueueSlot{}, false, nil
+	}
+	if err != nil {
+		return QueueSlot{}, false, err
+	}
+	return queueSlot(res), true, nil
+}
+
+// Leave: Çağrıyı outcome (QueueOutcome*) sonucuyla kuyruktan çıkarır. serviceLevel içinde cevaplanan
+// çağrılar servis seviyesine dahildir. Çağrı kuyrukta değilse false döner.
+func (t *QueueTracker) Leave(ctx context.Context, queueID, callID, agentID, outcome string, serviceLevel time.Duration) (time.Duration, bool, error) {
+	keys := append(waitingKeys(queueID), queueStatsKey(queueID, time.Now()), queueAgentCallKey(agentID))
+	waited, err := leaveScript.Run(ctx, t.redis, keys,
+		callID, outcome, serviceLevel.Milliseconds(), queueID, agentID, queueAgentCallTTL.Milliseconds(), queueStatsTTL.Milliseconds()).Int64()
+	if err != nil {
+		return 0, false, err
+	}
+	if waited < 0 {
+		return 0, false, nil
+	}
+	return time.Duration(waited) * time.Millisecond, true, nil
+}
+
+// CompleteAgentCall: Ajanın cevapladığı kuyruk çağrısı bittiğinde görüşme süresini kaydeder.
+// Açık bir kuyruk çağrısı yoksa hiçbir şey yapmaz.
+func (t *QueueTracker) CompleteAgentCall(ctx context.Context, agentID string) error {
+	return completeAgentCallScript.Run(ctx, t.redis, []string{queueAgentCallKey(agentID)},
+		"dialplan:acd:queue:", queueHandleSamples).Err()
+}
+
+// Stats: Kuyruğun bekleyen çağrılarını, bugünkü (UTC) sayaçlarını ve son görüşme sürelerini okur.
+func (t *QueueTracker) Stats(ctx context.Context, queueID string, maxWait time.Duration) (QueueStats, error) {
+	if err := sweepScript.Run(ctx, t.redis, waitingKeys(queueID), queueCallMaxAge(maxWait), queueSweepBatch).Err(); err != nil {
+		return QueueStats{}, err
+	}
+	now := time.Now()
+	pipe := t.redis.Pipeline()
+	waiting := pipe.ZCard(ctx, queueKey(queueID, "waiting"))
+	oldest := pipe.ZRangeWithScores(ctx, queueKey(queueID, "arrivals"), 0, 0)
+	counters := pipe.HMGet(ctx, queueStatsKey(queueID, now), QueueOutcomeAnswered, "answered_in_sl", QueueOutcomeAbandoned, QueueOutcomeOverflowed)
+	handles := pipe.LRange(ctx, queueKey(queueID, "handle"), 0, -1)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return QueueStats{}, err
+	}
+
+	stats := QueueStats{Waiting: int(waiting.Val())}
+	if o := oldest.Val(); len(o) > 0 {
+		stats.LongestWait = now.Sub(time.UnixMilli(int64(o[0].Score)))
+	}
+	vals := counters.Val()
+	stats.Answered, _ = strconv.Atoi(redisString(vals[0]))
+	stats.AnsweredInSL, _ = strconv.Atoi(redisString(vals[1]))
+	stats.Abandoned, _ = strconv.Atoi(redisString(vals[2]))
+	stats.Overflowed, _ = strconv.Atoi(redisString(vals[3]))
+
+	var total int64
+	for _, raw := range handles.Val() {
+		if ms, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			total += ms
+			stats.HandleSampleCount++
+		}
+	}
+	if stats.HandleSampleCount > 0 {
+		stats.AverageHandle = time.Duration(total/int64(stats.HandleSampleCount)) * time.Millisecond
+	}
+	return stats, nil
+}
+
+func queueSlot(res []int64) QueueSlot {
+	return QueueSlot{Position: int(res[0]) + 1, Waiting: int(res[1]), Waited: time.Duration(res[2]) * time.Millisecond}
+}
+
+func queueKey(queueID, suffix string) string {
+	return fmt.Sprintf("dialplan:acd:queue:%s:%s", queueID, suffix)
+}
+
+func queueStatsKey(queueID string, day time.Time) string {
+	return queueKey(queueID, "stats:"+day.UTC().Format("2006-01-02"))
+}
+
+func queueAgentCallKey(agentID string) string {
+	return fmt.Sprintf("dialplan:acd:agent:%s:call", agentID)
+}
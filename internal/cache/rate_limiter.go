@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript: Pencere dışına düşen kayıtları temizler; limit dolmamışsa çağrıyı kaydeder.
+// Skorlar milisaniyedir; zaman Redis'ten (TIME) alınır, böylece replikalar arası saat kayması sayaçları bozmaz.
+// Dönüş: 1 => izin verildi, 0 => limit aşıldı.
+var slidingWindowScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local window = tonumber(ARGV[1])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+if redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('ZADD', KEYS[1], now, ARGV[3])
+redis.call('PEXPIRE', KEYS[1], window)
+return 1
+`)
+
+// RateLimiter: Redis sorted set üzerinde kayan pencere (sliding window log) sayaçları.
+type RateLimiter struct {
+	redis *redis.Client
+}
+
+func NewRateLimiter(redisClient *redis.Client) *RateLimiter {
+	return &RateLimiter{redis: redisClient}
+}
+
+// Allow: key için window içinde limit'ten az çağrı varsa çağrıyı sayar ve true döner.
+func (l *RateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	member := strconv.FormatInt(time.Now().UnixNano(), 36) + "-" + strconv.FormatUint(rand.Uint64(), 36)
+	res, err := slidingWindowScript.Run(ctx, l.redis, []string{rateLimitKey(key)},
+		window.Milliseconds(), limit, member).Int()
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
+
+func rateLimitKey(key string) string {
+	return fmt.Sprintf("dialplan:ratelimit:%s", key)
+}
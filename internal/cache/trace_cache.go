@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const DecisionTraceTTL = 1 * time.Hour
+
+// TraceCache: ResolveDialplan karar izlerini trace_id bazında saklar.
+type TraceCache struct {
+	redis *redis.Client
+}
+
+func NewTraceCache(redisClient *redis.Client) *TraceCache {
+	return &TraceCache{redis: redisClient}
+}
+
+func (c *TraceCache) SaveTrace(ctx context.Context, traceID string, data []byte) error {
+	return c.redis.Set(ctx, traceKey(traceID), data, DecisionTraceTTL).Err()
+}
+
+// GetTrace: Kayıt yoksa (nil, nil) döner.
+func (c *TraceCache) GetTrace(ctx context.Context, traceID string) ([]byte, error) {
+	val, err := c.redis.Get(ctx, traceKey(traceID)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return val, err
+}
+
+func traceKey(traceID string) string {
+	return fmt.Sprintf("dialplan:trace:%s", traceID)
+}
@@ -4,6 +4,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -12,6 +15,12 @@ type ServerConfig struct {
 	HttpPort    string
 	GRPCPort    string
 	MetricsPort string
+	// AdminHTTPEnabled: /admin/* uç noktaları (explain, takvim aboneliği) açılsın mı?
+	AdminHTTPEnabled bool
+	// AdminHTTPAddr: Admin uç noktalarının dinlendiği ayrı adres; health/metrics portundan bağımsızdır.
+	AdminHTTPAddr string
+	// AdminToken: Admin isteklerinde zorunlu bearer token. Boşsa admin uç noktaları açılmaz.
+	AdminToken string
 }
 
 type TLSConfig struct {
@@ -20,6 +29,37 @@ type TLSConfig struct {
 	CaPath   string
 }
 
+// NumberingConfig: Numara normalizasyonu için ülke ayarları.
+type NumberingConfig struct {
+	DefaultCountry  string
+	TenantCountries map[string]string // tenant_id -> ISO ülke kodu
+}
+
+// ScheduleConfig: Takvim önbelleği ve bakım görevleri.
+type ScheduleConfig struct {
+	// OverridePurgeInterval: Süresi dolmuş takvim istisnalarının silinme periyodu (0 => kapalı).
+	OverridePurgeInterval time.Duration
+	// CacheTTL: Derlenmiş takvimlerin süreç içi önbellekte kalma süresi (0 => kapalı).
+	CacheTTL time.Duration
+}
+
+// RateLimit: Window içinde izin verilen azami çağrı sayısı (Limit 0 => kapalı).
+type RateLimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+// RateLimitConfig: ResolveDialplan flood koruması. Limitler "adet/süre" formatında okunur (örn: "30/1m")
+// ve varsayılan olarak kapalıdır. Arayan limiti tenant bazında sayılır.
+type RateLimitConfig struct {
+	PerCaller          RateLimit
+	PerDestination     RateLimit
+	PerTenant          RateLimit
+	ThrottleDialplanID string
+	// CheckTimeout: Tek bir sayaç kontrolünün azami süresi; aşılırsa çağrı sınırlanmadan devam eder.
+	CheckTimeout time.Duration
+}
+
 type Config struct {
 	Env            string
 	LogLevel       string
@@ -31,6 +71,16 @@ type Config struct {
 	RedisURL       string
 	Server         ServerConfig
 	TLS            TLSConfig
+	Numbering      NumberingConfig
+	Schedule       ScheduleConfig
+	RateLimit      RateLimitConfig
+	// CallTTL: ReleaseCall gelmeyen çağrıların eşzamanlı çağrı kapasitesinden otomatik düşme süresi.
+	CallTTL time.Duration
+	// QueueServiceLevel: Kuyruk servis seviyesi eşiği; bu süre içinde cevaplanan çağrılar hedefe dahildir.
+	QueueServiceLevel time.Duration
+	// TraceSampleRate: Explain istenmeyen çağrılardan karar izi saklanacakların oranı (0-1, 0 => kapalı).
+	// İzler arayan numarası içerdiğinden varsayılan kapalıdır.
+	TraceSampleRate float64
 }
 
 func Load() (*Config, error) {
@@ -47,15 +97,37 @@ func Load() (*Config, error) {
 		UserServiceURL: getEnvOrFail("USER_SERVICE_TARGET_GRPC_URL"),
 		RedisURL:       getEnv("REDIS_URL", "redis://redis.service.sentiric.cloud:6379/0"),
 		Server: ServerConfig{
-			HttpPort:    getEnv("DIALPLAN_SERVICE_HTTP_PORT", "12020"),
-			GRPCPort:    getEnv("DIALPLAN_SERVICE_GRPC_PORT", "12021"),
-			MetricsPort: getEnv("DIALPLAN_SERVICE_METRICS_PORT", "12022"),
+			HttpPort:         getEnv("DIALPLAN_SERVICE_HTTP_PORT", "12020"),
+			GRPCPort:         getEnv("DIALPLAN_SERVICE_GRPC_PORT", "12021"),
+			MetricsPort:      getEnv("DIALPLAN_SERVICE_METRICS_PORT", "12022"),
+			AdminHTTPEnabled: getEnvBool("DIALPLAN_ADMIN_HTTP_ENABLED", false),
+			AdminHTTPAddr:    getEnv("DIALPLAN_ADMIN_HTTP_ADDR", "127.0.0.1:12023"),
+			AdminToken:       getEnv("DIALPLAN_ADMIN_TOKEN", ""),
 		},
 		TLS: TLSConfig{
 			CertPath: getEnvOrFail("DIALPLAN_SERVICE_CERT_PATH"),
 			KeyPath:  getEnvOrFail("DIALPLAN_SERVICE_KEY_PATH"),
 			CaPath:   getEnvOrFail("GRPC_TLS_CA_PATH"),
 		},
+		Numbering: NumberingConfig{
+			DefaultCountry: getEnv("DIALPLAN_DEFAULT_COUNTRY", "TR"),
+			// Örn: "tenant_acme=DE,tenant_globex=GB"
+			TenantCountries: getEnvMap("DIALPLAN_TENANT_COUNTRIES"),
+		},
+		Schedule: ScheduleConfig{
+			OverridePurgeInterval: getEnvDuration("DIALPLAN_OVERRIDE_PURGE_INTERVAL", 5*time.Minute),
+			CacheTTL:              getEnvDuration("DIALPLAN_SCHEDULE_CACHE_TTL", 30*time.Second),
+		},
+		RateLimit: RateLimitConfig{
+			PerCaller:          getEnvRateLimit("DIALPLAN_RATE_LIMIT_CALLER", RateLimit{}),
+			PerDestination:     getEnvRateLimit("DIALPLAN_RATE_LIMIT_DESTINATION", RateLimit{}),
+			PerTenant:          getEnvRateLimit("DIALPLAN_RATE_LIMIT_TENANT", RateLimit{}),
+			ThrottleDialplanID: getEnv("DIALPLAN_RATE_LIMIT_DIALPLAN_ID", "DP_SYSTEM_THROTTLED"),
+			CheckTimeout:       getEnvDuration("DIALPLAN_RATE_LIMIT_TIMEOUT", 250*time.Millisecond),
+		},
+		CallTTL:           getEnvDuration("DIALPLAN_CALL_TTL", 4*time.Hour),
+		QueueServiceLevel: getEnvDuration("DIALPLAN_QUEUE_SERVICE_LEVEL", 20*time.Second),
+		TraceSampleRate:   getEnvFloat("DIALPLAN_TRACE_SAMPLE_RATE", 0),
 	}
 	return cfg, nil
 }
@@ -67,6 +139,89 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+func getEnvBool(key string, fallback bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+// getEnvRateLimit: "adet/süre" formatındaki limiti okur (örn: "500/1h"). "0" limiti kapatır.
+// Okunamayan değer sessizce yutulmaz; stderr'e yazılır ve fallback kullanılır.
+func getEnvRateLimit(key string, fallback RateLimit) RateLimit {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	limit, err := parseRateLimit(value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Uyarı: %s=%q okunamadı (%v), varsayılan kullanılıyor: %d/%s\n", key, value, err, fallback.Limit, fallback.Window)
+		return fallback
+	}
+	return limit
+}
+
+func parseRateLimit(value string) (RateLimit, error) {
+	if strings.TrimSpace(value) == "0" {
+		return RateLimit{}, nil
+	}
+	count, window, ok := strings.Cut(value, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("beklenen format adet/süre")
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || limit < 0 {
+		return RateLimit{}, fmt.Errorf("geçersiz adet: %q", count)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(window))
+	if err != nil || d <= 0 {
+		return RateLimit{}, fmt.Errorf("geçersiz süre: %q", window)
+	}
+	return RateLimit{Limit: limit, Window: d}, nil
+}
+
+// getEnvMap: "anahtar=değer,anahtar2=değer2" formatındaki değişkeni map'e çevirir.
+func getEnvMap(key string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(getEnv(key, ""), ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			continue
+		}
+		result[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return result
+}
+
 func getEnvOrFail(key string) string {
 	value, exists := os.LookupEnv(key)
 	if !exists {
@@ -3,24 +3,30 @@ package logger
 
 // SUTS v4.0 Standard Event IDs for dialplan-service
 const (
-	EventSystemStartup         = "SYSTEM_STARTUP"
-	EventSystemShutdown        = "SYSTEM_SHUTDOWN"
-	EventDBConnectionFail      = "DB_CONNECTION_FAILED"
-	EventRedisConnected        = "REDIS_CONNECTED"
-	EventRedisConnectionFail   = "REDIS_CONNECTION_FAILED"
-	EventUserSvcConnectionFail = "USER_SVC_CONNECTION_FAILED"
-	EventHTTPServerStart       = "HTTP_SERVER_START"
-	EventHTTPServerFail        = "HTTP_SERVER_FAILED"
-	EventHTTPServerStop        = "HTTP_SERVER_STOPPED"
-	EventGRPCServerStart       = "GRPC_SERVER_START"
-	EventGRPCServerFail        = "GRPC_SERVER_FAILED"
-	EventGRPCServerStop        = "GRPC_SERVER_STOPPED"
+	EventSystemStartup          = "SYSTEM_STARTUP"
+	EventSystemShutdown         = "SYSTEM_SHUTDOWN"
+	EventDBConnectionFail       = "DB_CONNECTION_FAILED"
+	EventRedisConnected         = "REDIS_CONNECTED"
+	EventRedisConnectionFail    = "REDIS_CONNECTION_FAILED"
+	EventUserSvcConnectionFail  = "USER_SVC_CONNECTION_FAILED"
+	EventHTTPServerStart        = "HTTP_SERVER_START"
+	EventHTTPServerFail         = "HTTP_SERVER_FAILED"
+	EventHTTPServerStop         = "HTTP_SERVER_STOPPED"
+	EventGRPCServerStart        = "GRPC_SERVER_START"
+	EventGRPCServerFail         = "GRPC_SERVER_FAILED"
+	EventGRPCServerStop         = "GRPC_SERVER_STOPPED"
+	EventNumberingConfigInvalid = "NUMBERING_CONFIG_INVALID"
 
 	EventGrpcRequest          = "GRPC_REQUEST_RECEIVED"
 	EventGrpcInSuccess        = "GRPC_IN_SUCCESS"
 	EventGrpcInFail           = "GRPC_IN_FAIL"
+	EventDomainErrorMapped    = "DOMAIN_ERROR_MAPPED"
 	EventDialplanResolveStart = "DIALPLAN_RESOLUTION_START"
 	EventDialplanResolveDone  = "DIALPLAN_RESOLUTION_SUCCESS"
+	EventTraceStoreFailed     = "DECISION_TRACE_STORE_FAILED"
+	EventHTTPAdminRequestFail = "HTTP_ADMIN_REQUEST_FAILED"
+	EventAdminHTTPServerStart = "ADMIN_HTTP_SERVER_START"
+	EventAdminHTTPDisabled    = "ADMIN_HTTP_DISABLED"
 
 	EventRouteNotFound    = "ROUTE_NOT_FOUND"
 	EventRouteQueryFailed = "ROUTE_QUERY_FAILED"
@@ -28,10 +34,40 @@ const (
 	EventMaintenanceMode  = "MAINTENANCE_MODE_ACTIVE"
 	EventFailsafeMissing  = "FAILSAFE_PLAN_MISSING"
 
-	EventScheduleParseError = "SCHEDULE_PARSE_ERROR"
-	EventScheduleLoadFailed = "SCHEDULE_LOAD_FAILED"
-	EventWorkingHoursActive = "WORKING_HOURS_ACTIVE"
-	EventOffHoursActive     = "OFF_HOURS_ACTIVE"
+	EventCallerBlocked         = "CALLER_BLOCKED"
+	EventCallerListCheckFailed = "CALLER_LIST_CHECK_FAILED"
+
+	EventRateLimitExceeded    = "RATE_LIMIT_EXCEEDED"
+	EventRateLimitCheckFailed = "RATE_LIMIT_CHECK_FAILED"
+
+	EventCallCapacityExceeded    = "CALL_CAPACITY_EXCEEDED"
+	EventCallCapacityCheckFailed = "CALL_CAPACITY_CHECK_FAILED"
+
+	EventQueueAgentSelected = "QUEUE_AGENT_SELECTED"
+	EventAgentStatusChanged = "AGENT_STATUS_CHANGED"
+
+	EventQueueCallEnqueued     = "QUEUE_CALL_ENQUEUED"
+	EventQueueCallAnswered     = "QUEUE_CALL_ANSWERED"
+	EventQueueCallAbandoned    = "QUEUE_CALL_ABANDONED"
+	EventQueueStatsWriteFailed = "QUEUE_STATS_WRITE_FAILED"
+
+	EventQueueOverflow         = "QUEUE_OVERFLOW"
+	EventQueueFallbackCycle    = "QUEUE_FALLBACK_CYCLE"
+	EventQueueFallbackInvalid  = "QUEUE_FALLBACK_INVALID"
+	EventQueueAgentStateFailed = "QUEUE_AGENT_STATE_FAILED"
+
+	EventQueuePriorityAssigned = "QUEUE_PRIORITY_ASSIGNED"
+
+	EventScheduleParseError   = "SCHEDULE_PARSE_ERROR"
+	EventScheduleLoadFailed   = "SCHEDULE_LOAD_FAILED"
+	EventUnknownFieldsIgnored = "UNKNOWN_FIELDS_IGNORED"
+	EventWorkingHoursActive   = "WORKING_HOURS_ACTIVE"
+	EventOffHoursActive       = "OFF_HOURS_ACTIVE"
+
+	EventHolidayCalendarLoadFailed  = "HOLIDAY_CALENDAR_LOAD_FAILED"
+	EventScheduleOverrideLoadFailed = "SCHEDULE_OVERRIDE_LOAD_FAILED"
+	EventScheduleOverridesPurged    = "SCHEDULE_OVERRIDES_PURGED"
+	EventScheduleOverridePurgeFail  = "SCHEDULE_OVERRIDE_PURGE_FAILED"
 
 	EventUserCacheHit     = "USER_CACHE_HIT"
 	EventUserCacheMiss    = "USER_CACHE_MISS"
@@ -44,4 +80,5 @@ const (
 	EventAutoProvisionStart   = "AUTO_PROVISIONING_STARTED"
 	EventAutoProvisionSuccess = "AUTO_PROVISIONING_SUCCESS"
 	EventAutoProvisionFail    = "AUTO_PROVISIONING_FAILED"
+	EventAutoProvisionSkipped = "AUTO_PROVISIONING_SKIPPED"
 )
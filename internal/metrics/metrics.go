@@ -0,0 +1,26 @@
+// sentiric-dialplan-service/internal/metrics/metrics.go
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Servis metrikleri varsayılan registry'ye kaydedilir ve /metrics üzerinden yayınlanır.
+var (
+	// RateLimitedCalls: Rate limit aşımı nedeniyle throttle dialplan'ına yönlendirilen çağrılar (scope: caller, destination, tenant).
+	RateLimitedCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "sentiric",
+		Subsystem: "dialplan",
+		Name:      "rate_limited_calls_total",
+		Help:      "Rate limit aşımı nedeniyle throttle edilen çağrı sayısı.",
+	}, []string{"scope"})
+
+	// OverCapacityCalls: Eşzamanlı çağrı kapasitesi dolu olduğu için meşgul dialplan'ına yönlendirilen çağrılar (scope: tenant, route).
+	OverCapacityCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "sentiric",
+		Subsystem: "dialplan",
+		Name:      "over_capacity_calls_total",
+		Help:      "Eşzamanlı çağrı kapasitesi dolu olduğu için reddedilen çağrı sayısı.",
+	}, []string{"scope"})
+)
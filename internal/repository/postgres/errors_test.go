@@ -0,0 +1,77 @@
+// sentiric-dialplan-service/internal/repository/postgres/errors_test.go
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sentiric/sentiric-dialplan-service/internal/service/dialplan"
+)
+
+func TestHandleError(t *testing.T) {
+	r := &Repository{}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"no rows", pgx.ErrNoRows, dialplan.ErrNotFound},
+		{"unique", &pgconn.PgError{Code: "23505"}, dialplan.ErrConflict},
+		{"exclusion", &pgconn.PgError{Code: "23P01"}, dialplan.ErrConflict},
+		{"foreign key", &pgconn.PgError{Code: "23503"}, dialplan.ErrReferenceNotFound},
+		{"not null", &pgconn.PgError{Code: "23502"}, dialplan.ErrConstraintViolation},
+		{"check", &pgconn.PgError{Code: "23514"}, dialplan.ErrConstraintViolation},
+		{"invalid uuid", &pgconn.PgError{Code: "22P02"}, dialplan.ErrInvalidArgument},
+		{"table missing", &pgconn.PgError{Code: "42P01"}, dialplan.ErrTableMissing},
+		{"connection failure", &pgconn.PgError{Code: "08006"}, dialplan.ErrDatabaseUnavailable},
+		{"admin shutdown", &pgconn.PgError{Code: "57P01"}, dialplan.ErrDatabaseUnavailable},
+		{"too many connections", &pgconn.PgError{Code: "53300"}, dialplan.ErrDatabaseUnavailable},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), dialplan.ErrDatabaseUnavailable},
+		{"syntax", &pgconn.PgError{Code: "42601"}, dialplan.ErrDatabase},
+		{"unknown", errors.New("boom"), dialplan.ErrDatabase},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.handleError(tt.err)
+			if !errors.Is(got, tt.want) {
+				t.Fatalf("handleError(%v) = %v, beklenen %v", tt.err, got, tt.want)
+			}
+			if tt.want == dialplan.ErrDatabase && errors.Is(got, dialplan.ErrDatabaseUnavailable) {
+				t.Fatalf("kalıcı hata yeniden denenebilir olarak işaretlendi: %v", got)
+			}
+		})
+	}
+}
+
+func TestHandleDeleteError(t *testing.T) {
+	r := &Repository{}
+	fk := &pgconn.PgError{Code: "23503", TableName: "inbound_routes"}
+	if got := r.handleDeleteError(fk); !errors.Is(got, dialplan.ErrInUse) {
+		t.Fatalf("silme yolunda 23503 = %v, beklenen ErrInUse", got)
+	}
+	if got := r.handleDeleteError(&pgconn.PgError{Code: "08006"}); !errors.Is(got, dialplan.ErrDatabaseUnavailable) {
+		t.Fatalf("silme yolunda bağlantı hatası = %v, beklenen ErrDatabaseUnavailable", got)
+	}
+}
+
+func TestReferencedResource(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *pgconn.PgError
+		want string
+	}{
+		{"detail", &pgconn.PgError{Detail: `Key (active_dialplan_id)=(DP_X) is not present in table "dialplans".`, ConstraintName: "fk_active"}, "dialplans.active_dialplan_id"},
+		{"fallback", &pgconn.PgError{Detail: "Anahtar bulunamadı", ConstraintName: "fk_active"}, "fk_active"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := referencedResource(tt.err); got != tt.want {
+				t.Fatalf("referencedResource = %q, beklenen %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,6 +7,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -34,38 +38,91 @@ func (r *Repository) handleError(err error) error {
 	}
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) {
-		switch pgErr.Code {
-		case "23505":
+		switch {
+		case pgErr.Code == "23505", pgErr.Code == "23P01": // unique / exclusion
 			return dialplan.ErrConflict
+		case pgErr.Code == "23503": // yazma yolunda: başvurulan kayıt yok
+			return fmt.Errorf("%w: %s", dialplan.ErrReferenceNotFound, referencedResource(pgErr))
+		case pgErr.Code == "42P01":
+			return fmt.Errorf("%w: %s", dialplan.ErrTableMissing, pgErr.Message)
+		case strings.HasPrefix(pgErr.Code, "22"): // data exception: geçersiz girdi (örn: UUID sözdizimi)
+			// Sürücü metni istemciye taşınmaz; yalnızca SQLSTATE eklenir.
+			return fmt.Errorf("%w: veritabanı değeri reddetti (SQLSTATE %s)", dialplan.ErrInvalidArgument, pgErr.Code)
+		case strings.HasPrefix(pgErr.Code, "23"):
+			return fmt.Errorf("%w: %s (%s)", dialplan.ErrConstraintViolation, pgErr.ConstraintName, pgErr.Message)
+		case strings.HasPrefix(pgErr.Code, "08"), strings.HasPrefix(pgErr.Code, "57P"), pgErr.Code == "53300":
+			// Bağlantı hatası, sunucu kapanıyor, bağlantı sınırı
+			return fmt.Errorf("%w: %v", dialplan.ErrDatabaseUnavailable, err)
 		}
+		return fmt.Errorf("%w: %v", dialplan.ErrDatabase, err)
+	}
+	if isConnectionError(err) {
+		return fmt.Errorf("%w: %v", dialplan.ErrDatabaseUnavailable, err)
 	}
 	return fmt.Errorf("%w: %v", dialplan.ErrDatabase, err)
 }
 
+// handleDeleteError: Silme yollarında yabancı anahtar ihlali, kaydın hâlâ kullanımda olduğu anlamına gelir.
+func (r *Repository) handleDeleteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return fmt.Errorf("%w: %s", dialplan.ErrInUse, pgErr.TableName)
+	}
+	return r.handleError(err)
+}
+
+// fkDetailPattern: 23503 Detail metni; örn: `Key (active_dialplan_id)=(DP_X) is not present in table "dialplans".`
+var fkDetailPattern = regexp.MustCompile(`^Key \(([^)]+)\)=\(.*\) is not present in table "([^"]+)"`)
+
+// referencedResource: Yabancı anahtar hatasından başvurulan tabloyu ve sütunu çıkarır.
+// Detail ayrıştırılamazsa (örn: farklı lc_messages) kısıt adına düşer.
+func referencedResource(pgErr *pgconn.PgError) string {
+	if m := fkDetailPattern.FindStringSubmatch(pgErr.Detail); m != nil {
+		return fmt.Sprintf("%s.%s", m[2], m[1])
+	}
+	return pgErr.ConstraintName
+}
+
+// isConnectionError: Sunucuya ulaşılamadığını veya isteğin zaman aşımına uğradığını gösteren hatalar.
+func isConnectionError(err error) bool {
+	var connErr *pgconn.ConnectError
+	var netErr net.Error
+	return errors.As(err, &connErr) ||
+		errors.As(err, &netErr) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		pgconn.Timeout(err) ||
+		pgconn.SafeToRetry(err)
+}
+
 // --- INBOUND ROUTES ---
 
-func (r *Repository) FindInboundRouteByPhone(ctx context.Context, phoneNumber string) (*dialplanv1.InboundRoute, error) {
+const inboundRouteColumns = `
+	phone_number, tenant_id,
+	active_dialplan_id, off_hours_dialplan_id, failsafe_dialplan_id, schedule_id,
+	is_maintenance_mode, block_anonymous, default_language_code, sip_trunk_id, state_dialplans,
+	rejection_dialplan_id, blocked_caller_dialplan_id, lines_busy_dialplan_id, max_concurrent_calls, queue_priority`
+
+func scanInboundRoute(row pgx.Row) (*dialplanv1.InboundRoute, error) {
 	var route dialplanv1.InboundRoute
-	var activeDP, offHoursDP, failsafeDP, scheduleID sql.NullString
+	var activeDP, offHoursDP, failsafeDP, scheduleID, rejectionDP, blockedCallerDP, linesBusyDP sql.NullString
 	// TrunkID'yi alıyoruz ama Contracts'ta henüz yoksa kullanamayız.
 	// Ancak DB'den çekmek iyi pratiktir.
 	var trunkID sql.NullInt32
+	var stateDialplans []byte
 
-	query := `
-		SELECT 
-			phone_number, tenant_id, 
-			active_dialplan_id, off_hours_dialplan_id, failsafe_dialplan_id, schedule_id,
-			is_maintenance_mode, block_anonymous, default_language_code, sip_trunk_id 
-		FROM inbound_routes 
-		WHERE phone_number = $1`
-
-	err := r.db.QueryRow(ctx, query, phoneNumber).Scan(
+	err := row.Scan(
 		&route.PhoneNumber, &route.TenantId,
 		&activeDP, &offHoursDP, &failsafeDP, &scheduleID,
-		&route.IsMaintenanceMode, &route.BlockAnonymous, &route.DefaultLanguageCode, &trunkID,
+		&route.IsMaintenanceMode, &route.BlockAnonymous, &route.DefaultLanguageCode, &trunkID, &stateDialplans,
+		&rejectionDP, &blockedCallerDP, &linesBusyDP, &route.MaxConcurrentCalls, &route.QueuePriority,
 	)
 	if err != nil {
-		return nil, r.handleError(err)
+		return nil, err
+	}
+	if len(stateDialplans) > 0 {
+		if err := json.Unmarshal(stateDialplans, &route.StateDialplanIds); err != nil {
+			return nil, err
+		}
 	}
 
 	if activeDP.Valid {
@@ -80,6 +137,15 @@ func (r *Repository) FindInboundRouteByPhone(ctx context.Context, phoneNumber st
 	if scheduleID.Valid {
 		route.ScheduleId = &scheduleID.String
 	}
+	if rejectionDP.Valid {
+		route.RejectionDialplanId = &rejectionDP.String
+	}
+	if blockedCallerDP.Valid {
+		route.BlockedCallerDialplanId = &blockedCallerDP.String
+	}
+	if linesBusyDP.Valid {
+		route.LinesBusyDialplanId = &linesBusyDP.String
+	}
 
 	// [FIX]: Trunk ID ataması yapıldı (Proto definition'da mevcut değilse compile hatası verir,
 	// contracts güncellendiği için bu alanın olduğunu varsayıyoruz.
@@ -93,41 +159,167 @@ func (r *Repository) FindInboundRouteByPhone(ctx context.Context, phoneNumber st
 	return &route, nil
 }
 
+// stateDialplansJSON: state_dialplans kolonu için JSON; eşleme yoksa NULL yazılır.
+func stateDialplansJSON(route *dialplanv1.InboundRoute) *string {
+	if len(route.StateDialplanIds) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(route.StateDialplanIds)
+	if err != nil {
+		return nil
+	}
+	str := string(data)
+	return &str
+}
+
+func (r *Repository) FindInboundRouteByPhone(ctx context.Context, phoneNumber string) (*dialplanv1.InboundRoute, error) {
+	query := `SELECT ` + inboundRouteColumns + ` FROM inbound_routes WHERE phone_number = $1`
+
+	route, err := scanInboundRoute(r.db.QueryRow(ctx, query, phoneNumber))
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	return route, nil
+}
+
+// FindPatternRoutesForNumber: phone_number kolonunda "90212555*" (prefix) veya
+// "902125550000-902125559999" (range) formunda saklanan ve numarayı kapsayan route'ları döner.
+// En spesifik olanın seçimi servis katmanındadır.
+func (r *Repository) FindPatternRoutesForNumber(ctx context.Context, phoneNumber string) ([]*dialplanv1.InboundRoute, error) {
+	query := `SELECT ` + inboundRouteColumns + ` FROM inbound_routes
+		WHERE (phone_number LIKE '%*' AND $1 LIKE rtrim(phone_number, '*') || '%')
+		   OR (phone_number LIKE '%-%'
+		       AND length(split_part(phone_number, '-', 1)) = length($1)
+		       AND $1 BETWEEN split_part(phone_number, '-', 1) AND split_part(phone_number, '-', 2))`
+
+	rows, err := r.db.Query(ctx, query, phoneNumber)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+
+	var routes []*dialplanv1.InboundRoute
+	for rows.Next() {
+		route, err := scanInboundRoute(rows)
+		if err != nil {
+			return nil, r.handleError(err)
+		}
+		routes = append(routes, route)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, r.handleError(err)
+	}
+	return routes, nil
+}
+
+// routeWriteLockKey: Route yazımlarını sıralayan transaction-scope advisory lock anahtarı. Çakışma kontrolü
+// ile yazma aynı kilit altında yapıldığından eşzamanlı iki create/update birbirinin çakışmasını kaçıramaz.
+const routeWriteLockKey int64 = 0x5e47_d1a1_0001
+
+// checkRouteConflict: Kilit altında, anahtarı kapsadığı numaralarda mevcut bir route ile çakışan kayıtları arar.
+// Range anahtarı için prefix'ler, range'ler ve range'e düşen exact numaralar; diğerleri için yalnızca range'ler adaydır.
+func checkRouteConflict(ctx context.Context, tx pgx.Tx, key string) error {
+	p, err := dialplan.ParseRoutePattern(key, dialplan.NumberingPlan{})
+	if err != nil {
+		return err
+	}
+	query := `SELECT phone_number FROM inbound_routes WHERE phone_number <> $1 AND phone_number LIKE '%-%'`
+	args := []interface{}{key}
+	if p.Kind == dialplan.RouteMatchRange {
+		query = `
+			SELECT phone_number FROM inbound_routes
+			WHERE phone_number <> $1 AND (
+				phone_number LIKE '%*' OR phone_number LIKE '%-%' OR
+				(length(phone_number) = $2 AND phone_number BETWEEN $3 AND $4)
+			)`
+		args = append(args, len(p.Start), p.Start, p.End)
+	}
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var existing string
+		if err := rows.Scan(&existing); err != nil {
+			return err
+		}
+		o, err := dialplan.ParseRoutePattern(existing, dialplan.NumberingPlan{})
+		if err != nil {
+			continue // Eski/bozuk anahtar çakışma kararını etkilemez.
+		}
+		if p.Conflicts(o) {
+			return fmt.Errorf("%w: %s, mevcut %s route'u ile çakışıyor", dialplan.ErrConflict, key, existing)
+		}
+	}
+	return rows.Err()
+}
+
+// writeRoute: Route yazımını advisory lock'lu bir transaction içinde çakışma kontrolünden sonra yapar.
+func (r *Repository) writeRoute(ctx context.Context, key string, write func(pgx.Tx) error) error {
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", routeWriteLockKey); err != nil {
+			return err
+		}
+		if err := checkRouteConflict(ctx, tx, key); err != nil {
+			return err
+		}
+		return write(tx)
+	})
+	if errors.Is(err, dialplan.ErrConflict) || errors.Is(err, dialplan.ErrInvalidArgument) {
+		return err
+	}
+	return r.handleError(err)
+}
+
 func (r *Repository) CreateInboundRoute(ctx context.Context, route *dialplanv1.InboundRoute) error {
 	query := `
 		INSERT INTO inbound_routes (
 			phone_number, tenant_id, active_dialplan_id, off_hours_dialplan_id, failsafe_dialplan_id, schedule_id,
-			is_maintenance_mode, block_anonymous, default_language_code, sip_trunk_id
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, 99)` // Default Trunk 99 (Dev)
+			is_maintenance_mode, block_anonymous, default_language_code, sip_trunk_id, state_dialplans,
+			rejection_dialplan_id, blocked_caller_dialplan_id, lines_busy_dialplan_id, max_concurrent_calls, queue_priority
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, 99, $10::jsonb, $11, $12, $13, $14, $15)` // Default Trunk 99 (Dev)
 
-	_, err := r.db.Exec(ctx, query,
-		route.PhoneNumber, route.TenantId, route.ActiveDialplanId, route.OffHoursDialplanId, route.FailsafeDialplanId, route.ScheduleId,
-		route.IsMaintenanceMode, route.BlockAnonymous, route.DefaultLanguageCode,
-	)
-	return r.handleError(err)
+	return r.writeRoute(ctx, route.PhoneNumber, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, query,
+			route.PhoneNumber, route.TenantId, route.ActiveDialplanId, route.OffHoursDialplanId, route.FailsafeDialplanId, route.ScheduleId,
+			route.IsMaintenanceMode, route.BlockAnonymous, route.DefaultLanguageCode, stateDialplansJSON(route),
+			route.RejectionDialplanId, route.BlockedCallerDialplanId, route.LinesBusyDialplanId, route.MaxConcurrentCalls, route.QueuePriority,
+		)
+		return err
+	})
 }
 
 func (r *Repository) UpdateInboundRoute(ctx context.Context, route *dialplanv1.InboundRoute) (int64, error) {
 	query := `
 		UPDATE inbound_routes SET 
 			tenant_id = $2, active_dialplan_id = $3, off_hours_dialplan_id = $4, failsafe_dialplan_id = $5, schedule_id = $6,
-			is_maintenance_mode = $7, block_anonymous = $8, default_language_code = $9 
+			is_maintenance_mode = $7, block_anonymous = $8, default_language_code = $9, state_dialplans = $10::jsonb,
+			rejection_dialplan_id = $11, blocked_caller_dialplan_id = $12,
+			lines_busy_dialplan_id = $13, max_concurrent_calls = $14, queue_priority = $15
 		WHERE phone_number = $1`
 
-	cmdTag, err := r.db.Exec(ctx, query,
-		route.PhoneNumber, route.TenantId, route.ActiveDialplanId, route.OffHoursDialplanId, route.FailsafeDialplanId, route.ScheduleId,
-		route.IsMaintenanceMode, route.BlockAnonymous, route.DefaultLanguageCode,
-	)
+	var affected int64
+	err := r.writeRoute(ctx, route.PhoneNumber, func(tx pgx.Tx) error {
+		cmdTag, err := tx.Exec(ctx, query,
+			route.PhoneNumber, route.TenantId, route.ActiveDialplanId, route.OffHoursDialplanId, route.FailsafeDialplanId, route.ScheduleId,
+			route.IsMaintenanceMode, route.BlockAnonymous, route.DefaultLanguageCode, stateDialplansJSON(route),
+			route.RejectionDialplanId, route.BlockedCallerDialplanId, route.LinesBusyDialplanId, route.MaxConcurrentCalls, route.QueuePriority,
+		)
+		affected = cmdTag.RowsAffected()
+		return err
+	})
 	if err != nil {
-		return 0, r.handleError(err)
+		return 0, err
 	}
-	return cmdTag.RowsAffected(), nil
+	return affected, nil
 }
 
 func (r *Repository) DeleteInboundRoute(ctx context.Context, phoneNumber string) (int64, error) {
 	cmdTag, err := r.db.Exec(ctx, "DELETE FROM inbound_routes WHERE phone_number = $1", phoneNumber)
 	if err != nil {
-		return 0, r.handleError(err)
+		return 0, r.handleDeleteError(err)
 	}
 	return cmdTag.RowsAffected(), nil
 }
@@ -220,7 +412,7 @@ func (r *Repository) UpdateDialplan(ctx context.Context, dp *dialplanv1.Dialplan
 func (r *Repository) DeleteDialplan(ctx context.Context, id string) (int64, error) {
 	cmdTag, err := r.db.Exec(ctx, "DELETE FROM dialplans WHERE id = $1", id)
 	if err != nil {
-		return 0, r.handleError(err)
+		return 0, r.handleDeleteError(err)
 	}
 	return cmdTag.RowsAffected(), nil
 }
@@ -279,34 +471,70 @@ func (r *Repository) CountDialplans(ctx context.Context, tenantID string) (int32
 
 // --- QUEUES ---
 
-func (r *Repository) CreateQueue(ctx context.Context, q *dialplanv1.Queue) error {
-	query := `
-		INSERT INTO queues (id, tenant_id, name, routing_strategy, max_wait_time_seconds, fallback_action, is_active) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7)`
-	_, err := r.db.Exec(ctx, query,
-		q.Id, q.TenantId, q.Name, q.RoutingStrategy, q.MaxWaitTimeSeconds, q.FallbackAction, q.IsActive)
-	return r.handleError(err)
-}
+const queueColumns = `id, tenant_id, name, routing_strategy, max_wait_time_seconds, fallback_action, is_active,
+	skill_requirements, skill_relax_seconds`
 
-func (r *Repository) GetQueue(ctx context.Context, id string) (*dialplanv1.Queue, error) {
+func scanQueue(row pgx.Row) (*dialplanv1.Queue, error) {
 	var q dialplanv1.Queue
 	var fallbackAction sql.NullString
-	query := `SELECT id, tenant_id, name, routing_strategy, max_wait_time_seconds, fallback_action, is_active FROM queues WHERE id = $1`
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	var skillRequirements []byte
+	err := row.Scan(
 		&q.Id, &q.TenantId, &q.Name, &q.RoutingStrategy, &q.MaxWaitTimeSeconds, &fallbackAction, &q.IsActive,
+		&skillRequirements, &q.SkillRelaxSeconds,
 	)
 	if err != nil {
-		return nil, r.handleError(err)
+		return nil, err
 	}
 	if fallbackAction.Valid {
 		q.FallbackAction = fallbackAction.String
 	}
+	if len(skillRequirements) > 0 {
+		if err := json.Unmarshal(skillRequirements, &q.SkillRequirements); err != nil {
+			return nil, err
+		}
+	}
 	return &q, nil
 }
 
+// skillRequirementsJSON: skill_requirements kolonu için JSON; gereksinim yoksa NULL yazılır.
+func skillRequirementsJSON(q *dialplanv1.Queue) *string {
+	if len(q.SkillRequirements) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(q.SkillRequirements)
+	if err != nil {
+		return nil
+	}
+	str := string(data)
+	return &str
+}
+
+func (r *Repository) CreateQueue(ctx context.Context, q *dialplanv1.Queue) error {
+	query := `
+		INSERT INTO queues (id, tenant_id, name, routing_strategy, max_wait_time_seconds, fallback_action, is_active,
+			skill_requirements, skill_relax_seconds)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8::jsonb, $9)`
+	_, err := r.db.Exec(ctx, query,
+		q.Id, q.TenantId, q.Name, q.RoutingStrategy, q.MaxWaitTimeSeconds, q.FallbackAction, q.IsActive,
+		skillRequirementsJSON(q), q.SkillRelaxSeconds)
+	return r.handleError(err)
+}
+
+func (r *Repository) GetQueue(ctx context.Context, id string) (*dialplanv1.Queue, error) {
+	q, err := scanQueue(r.db.QueryRow(ctx, "SELECT "+queueColumns+" FROM queues WHERE id = $1", id))
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	return q, nil
+}
+
 func (r *Repository) UpdateQueue(ctx context.Context, q *dialplanv1.Queue) (int64, error) {
-	query := `UPDATE queues SET name = $2, routing_strategy = $3, max_wait_time_seconds = $4, fallback_action = $5, is_active = $6 WHERE id = $1`
-	cmdTag, err := r.db.Exec(ctx, query, q.Id, q.Name, q.RoutingStrategy, q.MaxWaitTimeSeconds, q.FallbackAction, q.IsActive)
+	query := `
+		UPDATE queues SET name = $2, routing_strategy = $3, max_wait_time_seconds = $4, fallback_action = $5, is_active = $6,
+			skill_requirements = $7::jsonb, skill_relax_seconds = $8
+		WHERE id = $1`
+	cmdTag, err := r.db.Exec(ctx, query, q.Id, q.Name, q.RoutingStrategy, q.MaxWaitTimeSeconds, q.FallbackAction, q.IsActive,
+		skillRequirementsJSON(q), q.SkillRelaxSeconds)
 	if err != nil {
 		return 0, r.handleError(err)
 	}
@@ -316,13 +544,13 @@ func (r *Repository) UpdateQueue(ctx context.Context, q *dialplanv1.Queue) (int6
 func (r *Repository) DeleteQueue(ctx context.Context, id string) (int64, error) {
 	cmdTag, err := r.db.Exec(ctx, "DELETE FROM queues WHERE id = $1", id)
 	if err != nil {
-		return 0, r.handleError(err)
+		return 0, r.handleDeleteError(err)
 	}
 	return cmdTag.RowsAffected(), nil
 }
 
 func (r *Repository) ListQueues(ctx context.Context, tenantID string, pageSize, offset int32) ([]*dialplanv1.Queue, error) {
-	baseQuery := "SELECT id, tenant_id, name, routing_strategy, max_wait_time_seconds, fallback_action, is_active FROM queues"
+	baseQuery := "SELECT " + queueColumns + " FROM queues"
 	args := []interface{}{}
 	if tenantID != "" {
 		baseQuery += " WHERE tenant_id = $1"
@@ -336,15 +564,11 @@ func (r *Repository) ListQueues(ctx context.Context, tenantID string, pageSize,
 	defer rows.Close()
 	var queues []*dialplanv1.Queue
 	for rows.Next() {
-		var q dialplanv1.Queue
-		var fa sql.NullString
-		if err := rows.Scan(&q.Id, &q.TenantId, &q.Name, &q.RoutingStrategy, &q.MaxWaitTimeSeconds, &fa, &q.IsActive); err != nil {
+		q, err := scanQueue(rows)
+		if err != nil {
 			return nil, r.handleError(err)
 		}
-		if fa.Valid {
-			q.FallbackAction = fa.String
-		}
-		queues = append(queues, &q)
+		queues = append(queues, q)
 	}
 	return queues, nil
 }
@@ -361,6 +585,75 @@ func (r *Repository) CountQueues(ctx context.Context, tenantID string) (int32, e
 	return totalCount, r.handleError(err)
 }
 
+// --- QUEUE MEMBERS ---
+
+func (r *Repository) AddQueueMember(ctx context.Context, m *dialplanv1.QueueMember) error {
+	_, err := r.db.Exec(ctx, "INSERT INTO queue_members (queue_id, agent_id) VALUES ($1, $2)", m.QueueId, m.AgentId)
+	return r.handleError(err)
+}
+
+func (r *Repository) RemoveQueueMember(ctx context.Context, queueID, agentID string) (int64, error) {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM queue_members WHERE queue_id = $1 AND agent_id = $2", queueID, agentID)
+	if err != nil {
+		return 0, r.handleError(err)
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
+// ListQueueMembers: Üyeleri agent_id sırasıyla döner; round-robin sırası bu düzene dayanır.
+func (r *Repository) ListQueueMembers(ctx context.Context, queueID string) ([]*dialplanv1.QueueMember, error) {
+	rows, err := r.db.Query(ctx, "SELECT queue_id, agent_id FROM queue_members WHERE queue_id = $1 ORDER BY agent_id ASC", queueID)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+	var members []*dialplanv1.QueueMember
+	for rows.Next() {
+		var m dialplanv1.QueueMember
+		if err := rows.Scan(&m.QueueId, &m.AgentId); err != nil {
+			return nil, r.handleError(err)
+		}
+		members = append(members, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, r.handleError(err)
+	}
+	return members, nil
+}
+
+// --- AGENT SKILLS ---
+
+// ReplaceAgentSkills: Ajanın becerilerini tek batch (örtük transaction) içinde siler ve yeniden yazar.
+func (r *Repository) ReplaceAgentSkills(ctx context.Context, agentID string, skills []*dialplanv1.AgentSkill) error {
+	batch := &pgx.Batch{}
+	batch.Queue("DELETE FROM agent_skills WHERE agent_id = $1", agentID)
+	for _, sk := range skills {
+		batch.Queue("INSERT INTO agent_skills (agent_id, skill, level) VALUES ($1, $2, $3)", agentID, sk.Skill, sk.Level)
+	}
+	return r.handleError(r.db.SendBatch(ctx, batch).Close())
+}
+
+func (r *Repository) ListAgentSkills(ctx context.Context, agentIDs []string) ([]*dialplanv1.AgentSkill, error) {
+	query := "SELECT agent_id, skill, level FROM agent_skills WHERE agent_id = ANY($1) ORDER BY agent_id, skill"
+	rows, err := r.db.Query(ctx, query, agentIDs)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+	var skills []*dialplanv1.AgentSkill
+	for rows.Next() {
+		var sk dialplanv1.AgentSkill
+		if err := rows.Scan(&sk.AgentId, &sk.Skill, &sk.Level); err != nil {
+			return nil, r.handleError(err)
+		}
+		skills = append(skills, &sk)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, r.handleError(err)
+	}
+	return skills, nil
+}
+
 // --- SCHEDULES ---
 
 func (r *Repository) CreateSchedule(ctx context.Context, s *dialplanv1.Schedule) error {
@@ -380,3 +673,492 @@ func (r *Repository) GetSchedule(ctx context.Context, id string) (*dialplanv1.Sc
 	s.ScheduleJson = string(jsonData)
 	return &s, nil
 }
+
+func (r *Repository) UpdateSchedule(ctx context.Context, s *dialplanv1.Schedule) (int64, error) {
+	query := `UPDATE schedules SET tenant_id = $2, name = $3, timezone = $4, schedule_data = $5::jsonb WHERE id = $1`
+	cmdTag, err := r.db.Exec(ctx, query, s.Id, s.TenantId, s.Name, s.Timezone, s.ScheduleJson)
+	if err != nil {
+		return 0, r.handleError(err)
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
+func (r *Repository) DeleteSchedule(ctx context.Context, id string) (int64, error) {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM schedules WHERE id = $1", id)
+	if err != nil {
+		return 0, r.handleDeleteError(err)
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
+func (r *Repository) ListSchedules(ctx context.Context, tenantID string, pageSize, offset int32) ([]*dialplanv1.Schedule, error) {
+	baseQuery := "SELECT id, tenant_id, name, timezone, schedule_data FROM schedules"
+	args := []interface{}{}
+	if tenantID != "" {
+		baseQuery += " WHERE tenant_id = $1"
+		args = append(args, tenantID)
+	}
+	dataQuery := baseQuery + fmt.Sprintf(" ORDER BY name ASC LIMIT %d OFFSET %d", pageSize, offset)
+	rows, err := r.db.Query(ctx, dataQuery, args...)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+	var schedules []*dialplanv1.Schedule
+	for rows.Next() {
+		var s dialplanv1.Schedule
+		var jsonData []byte
+		if err := rows.Scan(&s.Id, &s.TenantId, &s.Name, &s.Timezone, &jsonData); err != nil {
+			return nil, r.handleError(err)
+		}
+		s.ScheduleJson = string(jsonData)
+		schedules = append(schedules, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, r.handleError(err)
+	}
+	return schedules, nil
+}
+
+func (r *Repository) CountSchedules(ctx context.Context, tenantID string) (int32, error) {
+	var totalCount int32
+	baseQuery := "SELECT count(*) FROM schedules"
+	args := []interface{}{}
+	if tenantID != "" {
+		baseQuery += " WHERE tenant_id = $1"
+		args = append(args, tenantID)
+	}
+	err := r.db.QueryRow(ctx, baseQuery, args...).Scan(&totalCount)
+	return totalCount, r.handleError(err)
+}
+
+// CountRoutesUsingSchedule: inbound_routes.schedule_id ile takvime bağlı route sayısı.
+func (r *Repository) CountRoutesUsingSchedule(ctx context.Context, scheduleID string) (int32, error) {
+	var count int32
+	err := r.db.QueryRow(ctx, "SELECT count(*) FROM inbound_routes WHERE schedule_id = $1", scheduleID).Scan(&count)
+	return count, r.handleError(err)
+}
+
+// --- SCHEDULE OVERRIDES ---
+
+const scheduleOverrideColumns = `id, schedule_id, mode, reason, starts_at, expires_at, created_at`
+
+func scanScheduleOverride(row pgx.Row) (*dialplanv1.ScheduleOverride, error) {
+	var o dialplanv1.ScheduleOverride
+	var startsAt, expiresAt, createdAt time.Time
+	if err := row.Scan(&o.Id, &o.ScheduleId, &o.Mode, &o.Reason, &startsAt, &expiresAt, &createdAt); err != nil {
+		return nil, err
+	}
+	o.StartsAt = startsAt.UTC().Format(time.RFC3339)
+	o.ExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+	o.CreatedAt = createdAt.UTC().Format(time.RFC3339)
+	return &o, nil
+}
+
+func (r *Repository) CreateScheduleOverride(ctx context.Context, o *dialplanv1.ScheduleOverride) error {
+	query := `
+		INSERT INTO schedule_overrides (schedule_id, mode, reason, starts_at, expires_at)
+		VALUES ($1, $2, $3, $4::timestamptz, $5::timestamptz)
+		RETURNING ` + scheduleOverrideColumns
+
+	created, err := scanScheduleOverride(r.db.QueryRow(ctx, query, o.ScheduleId, o.Mode, o.Reason, o.StartsAt, o.ExpiresAt))
+	if err != nil {
+		return r.handleError(err)
+	}
+	*o = *created
+	return nil
+}
+
+func (r *Repository) ImportSchedule(ctx context.Context, s *dialplanv1.Schedule, overrides []*dialplanv1.ScheduleOverride) (int32, error) {
+	var inserted int32
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if s != nil {
+			cmdTag, err := tx.Exec(ctx,
+				`UPDATE schedules SET tenant_id = $2, name = $3, timezone = $4, schedule_data = $5::jsonb WHERE id = $1`,
+				s.Id, s.TenantId, s.Name, s.Timezone, s.ScheduleJson)
+			if err != nil {
+				return err
+			}
+			if cmdTag.RowsAffected() == 0 {
+				return dialplan.ErrNotFound
+			}
+		}
+		// Aynı takvime eşzamanlı iki içe aktarım aynı override'ı iki kez ekleyemesin.
+		if len(overrides) > 0 {
+			if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext('schedule_overrides:' || $1))", overrides[0].ScheduleId); err != nil {
+				return err
+			}
+		}
+		for _, o := range overrides {
+			cmdTag, err := tx.Exec(ctx, `
+				INSERT INTO schedule_overrides (schedule_id, mode, reason, starts_at, expires_at)
+				SELECT $1, $2, $3, $4::timestamptz, $5::timestamptz
+				WHERE NOT EXISTS (
+					SELECT 1 FROM schedule_overrides
+					WHERE schedule_id = $1 AND starts_at = $4::timestamptz AND expires_at = $5::timestamptz AND reason = $3
+				)`,
+				o.ScheduleId, o.Mode, o.Reason, o.StartsAt, o.ExpiresAt)
+			if err != nil {
+				return err
+			}
+			inserted += int32(cmdTag.RowsAffected())
+		}
+		return nil
+	})
+	if errors.Is(err, dialplan.ErrNotFound) {
+		return 0, err
+	}
+	if err != nil {
+		return 0, r.handleError(err)
+	}
+	return inserted, nil
+}
+
+func (r *Repository) ListScheduleOverrides(ctx context.Context, scheduleID string, notExpiredAt *time.Time) ([]*dialplanv1.ScheduleOverride, error) {
+	query := `SELECT ` + scheduleOverrideColumns + ` FROM schedule_overrides WHERE schedule_id = $1`
+	args := []interface{}{scheduleID}
+	if notExpiredAt != nil {
+		query += " AND expires_at > $2"
+		args = append(args, *notExpiredAt)
+	}
+	query += " ORDER BY starts_at ASC"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+	var overrides []*dialplanv1.ScheduleOverride
+	for rows.Next() {
+		o, err := scanScheduleOverride(rows)
+		if err != nil {
+			return nil, r.handleError(err)
+		}
+		overrides = append(overrides, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, r.handleError(err)
+	}
+	return overrides, nil
+}
+
+func (r *Repository) DeleteScheduleOverride(ctx context.Context, id string) (int64, error) {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM schedule_overrides WHERE id = $1", id)
+	if err != nil {
+		return 0, r.handleError(err)
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
+func (r *Repository) DeleteExpiredScheduleOverrides(ctx context.Context, before time.Time) (int64, error) {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM schedule_overrides WHERE expires_at <= $1", before)
+	if err != nil {
+		return 0, r.handleError(err)
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
+// --- HOLIDAY CALENDARS ---
+
+func (r *Repository) CreateHolidayCalendar(ctx context.Context, c *dialplanv1.HolidayCalendar) error {
+	query := `INSERT INTO holiday_calendars (id, tenant_id, name, calendar_data) VALUES ($1, $2, $3, $4::jsonb)`
+	_, err := r.db.Exec(ctx, query, c.Id, c.TenantId, c.Name, c.CalendarJson)
+	return r.handleError(err)
+}
+
+func (r *Repository) GetHolidayCalendar(ctx context.Context, id string) (*dialplanv1.HolidayCalendar, error) {
+	var c dialplanv1.HolidayCalendar
+	var jsonData []byte
+	query := `SELECT id, tenant_id, name, calendar_data FROM holiday_calendars WHERE id = $1`
+	err := r.db.QueryRow(ctx, query, id).Scan(&c.Id, &c.TenantId, &c.Name, &jsonData)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	c.CalendarJson = string(jsonData)
+	return &c, nil
+}
+
+func (r *Repository) UpdateHolidayCalendar(ctx context.Context, c *dialplanv1.HolidayCalendar) (int64, error) {
+	query := `UPDATE holiday_calendars SET tenant_id = $2, name = $3, calendar_data = $4::jsonb WHERE id = $1`
+	cmdTag, err := r.db.Exec(ctx, query, c.Id, c.TenantId, c.Name, c.CalendarJson)
+	if err != nil {
+		return 0, r.handleError(err)
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
+func (r *Repository) DeleteHolidayCalendar(ctx context.Context, id string) (int64, error) {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM holiday_calendars WHERE id = $1", id)
+	if err != nil {
+		return 0, r.handleError(err)
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
+func (r *Repository) ListHolidayCalendars(ctx context.Context, tenantID string, pageSize, offset int32) ([]*dialplanv1.HolidayCalendar, error) {
+	baseQuery := "SELECT id, tenant_id, name, calendar_data FROM holiday_calendars"
+	args := []interface{}{}
+	if tenantID != "" {
+		baseQuery += " WHERE tenant_id = $1"
+		args = append(args, tenantID)
+	}
+	dataQuery := baseQuery + fmt.Sprintf(" ORDER BY name ASC LIMIT %d OFFSET %d", pageSize, offset)
+	rows, err := r.db.Query(ctx, dataQuery, args...)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+	var calendars []*dialplanv1.HolidayCalendar
+	for rows.Next() {
+		var c dialplanv1.HolidayCalendar
+		var jsonData []byte
+		if err := rows.Scan(&c.Id, &c.TenantId, &c.Name, &jsonData); err != nil {
+			return nil, r.handleError(err)
+		}
+		c.CalendarJson = string(jsonData)
+		calendars = append(calendars, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, r.handleError(err)
+	}
+	return calendars, nil
+}
+
+func (r *Repository) CountHolidayCalendars(ctx context.Context, tenantID string) (int32, error) {
+	var totalCount int32
+	baseQuery := "SELECT count(*) FROM holiday_calendars"
+	args := []interface{}{}
+	if tenantID != "" {
+		baseQuery += " WHERE tenant_id = $1"
+		args = append(args, tenantID)
+	}
+	err := r.db.QueryRow(ctx, baseQuery, args...).Scan(&totalCount)
+	return totalCount, r.handleError(err)
+}
+
+// CountSchedulesUsingCalendar: schedule_data->'calendars' dizisinde takvim ID'sini içeren schedule sayısı.
+func (r *Repository) CountSchedulesUsingCalendar(ctx context.Context, calendarID string) (int32, error) {
+	var count int32
+	err := r.db.QueryRow(ctx, "SELECT count(*) FROM schedules WHERE schedule_data->'calendars' ? $1", calendarID).Scan(&count)
+	return count, r.handleError(err)
+}
+
+// --- CALLER LISTS ---
+
+const callerListColumns = `id, tenant_id, route_phone_number, list_type, number, reason, expires_at, created_at, priority`
+
+func scanCallerListEntry(row pgx.Row) (*dialplanv1.CallerListEntry, error) {
+	var e dialplanv1.CallerListEntry
+	var routePhone sql.NullString
+	var expiresAt sql.NullTime
+	var createdAt time.Time
+	if err := row.Scan(&e.Id, &e.TenantId, &routePhone, &e.ListType, &e.Number, &e.Reason, &expiresAt, &createdAt, &e.Priority); err != nil {
+		return nil, err
+	}
+	if routePhone.Valid {
+		e.RoutePhoneNumber = &routePhone.String
+	}
+	if expiresAt.Valid {
+		e.ExpiresAt = expiresAt.Time.UTC().Format(time.RFC3339)
+	}
+	e.CreatedAt = createdAt.UTC().Format(time.RFC3339)
+	return &e, nil
+}
+
+// nullableTime: Boş RFC3339 değeri NULL olarak yazılır.
+func nullableTime(raw string) *string {
+	if raw == "" {
+		return nil
+	}
+	return &raw
+}
+
+func (r *Repository) CreateCallerListEntry(ctx context.Context, e *dialplanv1.CallerListEntry) error {
+	query := `
+		INSERT INTO caller_list_entries (tenant_id, route_phone_number, list_type, number, reason, expires_at, priority)
+		VALUES ($1, $2, $3, $4, $5, $6::timestamptz, $7)
+		RETURNING ` + callerListColumns
+
+	created, err := scanCallerListEntry(r.db.QueryRow(ctx, query,
+		e.TenantId, e.RoutePhoneNumber, e.ListType, e.Number, e.Reason, nullableTime(e.ExpiresAt), e.Priority))
+	if err != nil {
+		return r.handleError(err)
+	}
+	*e = *created
+	return nil
+}
+
+func (r *Repository) GetCallerListEntry(ctx context.Context, id string) (*dialplanv1.CallerListEntry, error) {
+	e, err := scanCallerListEntry(r.db.QueryRow(ctx, `SELECT `+callerListColumns+` FROM caller_list_entries WHERE id = $1`, id))
+	return e, r.handleError(err)
+}
+
+func (r *Repository) UpdateCallerListEntry(ctx context.Context, e *dialplanv1.CallerListEntry) (int64, error) {
+	query := `
+		UPDATE caller_list_entries SET
+			tenant_id = $2, route_phone_number = $3, list_type = $4, number = $5, reason = $6, expires_at = $7::timestamptz,
+			priority = $8
+		WHERE id = $1`
+
+	cmdTag, err := r.db.Exec(ctx, query,
+		e.Id, e.TenantId, e.RoutePhoneNumber, e.ListType, e.Number, e.Reason, nullableTime(e.ExpiresAt), e.Priority)
+	if err != nil {
+		return 0, r.handleError(err)
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
+func (r *Repository) DeleteCallerListEntry(ctx context.Context, id string) (int64, error) {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM caller_list_entries WHERE id = $1", id)
+	if err != nil {
+		return 0, r.handleError(err)
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
+// callerListWhere: Liste filtresinden WHERE koşulu ve argümanlarını üretir.
+func callerListWhere(f dialplan.CallerListFilter) (string, []interface{}) {
+	where := " WHERE tenant_id = $1"
+	args := []interface{}{f.TenantID}
+	if f.RoutePhoneNumber != nil {
+		args = append(args, *f.RoutePhoneNumber)
+		where += fmt.Sprintf(" AND route_phone_number = $%d", len(args))
+	}
+	if f.ListType != "" {
+		args = append(args, f.ListType)
+		where += fmt.Sprintf(" AND list_type = $%d", len(args))
+	}
+	if f.NotExpiredAt != nil {
+		args = append(args, *f.NotExpiredAt)
+		where += fmt.Sprintf(" AND (expires_at IS NULL OR expires_at > $%d)", len(args))
+	}
+	return where, args
+}
+
+func (r *Repository) ListCallerListEntries(ctx context.Context, f dialplan.CallerListFilter, pageSize, offset int32) ([]*dialplanv1.CallerListEntry, error) {
+	where, args := callerListWhere(f)
+	args = append(args, pageSize, offset)
+	query := `SELECT ` + callerListColumns + ` FROM caller_list_entries` + where +
+		fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+	var entries []*dialplanv1.CallerListEntry
+	for rows.Next() {
+		e, err := scanCallerListEntry(rows)
+		if err != nil {
+			return nil, r.handleError(err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, r.handleError(err)
+	}
+	return entries, nil
+}
+
+func (r *Repository) CountCallerListEntries(ctx context.Context, f dialplan.CallerListFilter) (int32, error) {
+	where, args := callerListWhere(f)
+	var count int32
+	err := r.db.QueryRow(ctx, `SELECT count(*) FROM caller_list_entries`+where, args...).Scan(&count)
+	return count, r.handleError(err)
+}
+
+// ImportCallerListEntries: Kayıtları tek batch'te ekler; benzersizlik kısıtına takılanlar atlanır.
+func (r *Repository) ImportCallerListEntries(ctx context.Context, entries []*dialplanv1.CallerListEntry) (int64, error) {
+	query := `
+		INSERT INTO caller_list_entries (tenant_id, route_phone_number, list_type, number, reason, expires_at, priority)
+		VALUES ($1, $2, $3, $4, $5, $6::timestamptz, $7)
+		ON CONFLICT DO NOTHING`
+
+	batch := &pgx.Batch{}
+	for _, e := range entries {
+		batch.Queue(query, e.TenantId, e.RoutePhoneNumber, e.ListType, e.Number, e.Reason, nullableTime(e.ExpiresAt), e.Priority)
+	}
+	results := r.db.SendBatch(ctx, batch)
+	defer results.Close()
+
+	var inserted int64
+	for range entries {
+		cmdTag, err := results.Exec()
+		if err != nil {
+			return inserted, r.handleError(err)
+		}
+		inserted += cmdTag.RowsAffected()
+	}
+	return inserted, nil
+}
+
+// MatchCallerList: Arayan numaraya uyan, at anında geçerli tenant ve route kapsamlı kayıtları döner.
+// Prefix kayıtları sonda '*' ile saklanır (örn: "90850*").
+func (r *Repository) MatchCallerList(ctx context.Context, tenantID, routePhoneNumber, caller string, at time.Time) ([]*dialplanv1.CallerListEntry, error) {
+	query := `
+		SELECT ` + callerListColumns + ` FROM caller_list_entries
+		WHERE tenant_id = $1
+		  AND (route_phone_number IS NULL OR route_phone_number = $2)
+		  AND (expires_at IS NULL OR expires_at > $3)
+		  AND (number = $4 OR (right(number, 1) = '*' AND starts_with($4, rtrim(number, '*'))))`
+
+	rows, err := r.db.Query(ctx, query, tenantID, routePhoneNumber, at, caller)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	defer rows.Close()
+	var entries []*dialplanv1.CallerListEntry
+	for rows.Next() {
+		e, err := scanCallerListEntry(rows)
+		if err != nil {
+			return nil, r.handleError(err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, r.handleError(err)
+	}
+	return entries, nil
+}
+
+// HasActiveAllowList: Route'a (veya tenant geneline) at anında geçerli bir izin listesi kaydı var mı?
+func (r *Repository) HasActiveAllowList(ctx context.Context, tenantID, routePhoneNumber string, at time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM caller_list_entries
+			WHERE tenant_id = $1
+			  AND (route_phone_number IS NULL OR route_phone_number = $2)
+			  AND list_type = 'allow'
+			  AND (expires_at IS NULL OR expires_at > $3)
+		)`
+	var exists bool
+	err := r.db.QueryRow(ctx, query, tenantID, routePhoneNumber, at).Scan(&exists)
+	return exists, r.handleError(err)
+}
+
+// --- TENANT CALL LIMITS ---
+
+func (r *Repository) GetTenantCallLimit(ctx context.Context, tenantID string) (*dialplanv1.TenantCallLimit, error) {
+	var l dialplanv1.TenantCallLimit
+	query := `SELECT tenant_id, max_concurrent_calls FROM tenant_call_limits WHERE tenant_id = $1`
+	if err := r.db.QueryRow(ctx, query, tenantID).Scan(&l.TenantId, &l.MaxConcurrentCalls); err != nil {
+		return nil, r.handleError(err)
+	}
+	return &l, nil
+}
+
+func (r *Repository) UpsertTenantCallLimit(ctx context.Context, l *dialplanv1.TenantCallLimit) error {
+	query := `
+		INSERT INTO tenant_call_limits (tenant_id, max_concurrent_calls) VALUES ($1, $2)
+		ON CONFLICT (tenant_id) DO UPDATE SET max_concurrent_calls = EXCLUDED.max_concurrent_calls`
+	_, err := r.db.Exec(ctx, query, l.TenantId, l.MaxConcurrentCalls)
+	return r.handleError(err)
+}
+
+func (r *Repository) DeleteTenantCallLimit(ctx context.Context, tenantID string) (int64, error) {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM tenant_call_limits WHERE tenant_id = $1", tenantID)
+	if err != nil {
+		return 0, r.handleError(err)
+	}
+	return cmdTag.RowsAffected(), nil
+}
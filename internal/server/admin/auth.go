@@ -0,0 +1,29 @@
+// sentiric-dialplan-service/internal/server/admin/auth.go
+package admin
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// TokenQueryParam: Özel başlık gönderemeyen takvim istemcileri (ICS aboneliği) için token parametresi.
+const TokenQueryParam = "token"
+
+// RequireToken: İsteği yalnızca "Authorization: Bearer <token>" başlığı veya token sorgu parametresi
+// beklenen değerle eşleşiyorsa next'e iletir. Karşılaştırma sabit zamanlıdır.
+func RequireToken(token string, next http.Handler) http.Handler {
+	expected := []byte(token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			got = r.URL.Query().Get(TokenQueryParam)
+		}
+		if token == "" || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(got)), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="dialplan-admin"`)
+			writeError(w, http.StatusUnauthorized, "geçersiz veya eksik admin token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
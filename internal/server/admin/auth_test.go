@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireToken(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		query  string
+		want   int
+	}{
+		{name: "bearer", token: "s3cret", header: "Bearer s3cret", want: http.StatusNoContent},
+		{name: "query token", token: "s3cret", query: "?token=s3cret", want: http.StatusNoContent},
+		{name: "wrong bearer", token: "s3cret", header: "Bearer nope", want: http.StatusUnauthorized},
+		{name: "missing", token: "s3cret", want: http.StatusUnauthorized},
+		{name: "non-bearer scheme", token: "s3cret", header: "Basic s3cret", want: http.StatusUnauthorized},
+		{name: "empty configured token", token: "", want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin/explain"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			RequireToken(tt.token, ok).ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
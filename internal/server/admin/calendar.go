@@ -0,0 +1,57 @@
+// sentiric-dialplan-service/internal/server/admin/calendar.go
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+)
+
+// CalendarService: Takvim aboneliği (ICS) uç noktasının ihtiyaç duyduğu servis metodları.
+type CalendarService interface {
+	ExportScheduleIcs(ctx context.Context, req *dialplanv1.ExportScheduleIcsRequest) (*dialplanv1.ExportScheduleIcsResponse, error)
+}
+
+// registerCalendarRoutes: Takvim istemcilerinin (Google Calendar, Outlook) abone olabileceği ICS akışı.
+//
+//	GET /admin/schedules/calendar.ics?schedule_id=...[&days=90]  -> Şu andan itibaren kapalı dilimler
+func registerCalendarRoutes(mux *http.ServeMux, svc CalendarService, log zerolog.Logger) {
+	mux.HandleFunc("/admin/schedules/calendar.ics", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "yalnızca GET desteklenir")
+			return
+		}
+		q := r.URL.Query()
+		scheduleID := q.Get("schedule_id")
+		if scheduleID == "" {
+			writeError(w, http.StatusBadRequest, "schedule_id parametresi gerekli")
+			return
+		}
+
+		req := &dialplanv1.ExportScheduleIcsRequest{ScheduleId: scheduleID}
+		if raw := q.Get("days"); raw != "" {
+			days, err := strconv.Atoi(raw)
+			if err != nil || days <= 0 {
+				writeError(w, http.StatusBadRequest, "days pozitif bir tam sayı olmalı")
+				return
+			}
+			now := time.Now()
+			req.From = now.Format(time.RFC3339)
+			req.To = now.AddDate(0, 0, days).Format(time.RFC3339)
+		}
+
+		resp, err := svc.ExportScheduleIcs(r.Context(), req)
+		if err != nil {
+			writeServiceError(w, log, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", `inline; filename="`+scheduleID+`.ics"`)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(resp.IcsData)
+	})
+}
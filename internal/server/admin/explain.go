@@ -0,0 +1,111 @@
+// sentiric-dialplan-service/internal/server/admin/explain.go
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
+	"github.com/sentiric/sentiric-dialplan-service/internal/service/dialplan"
+)
+
+// ExplainService: Admin "explain" uç noktasının ihtiyaç duyduğu servis metodları.
+type ExplainService interface {
+	ExplainDialplan(ctx context.Context, caller, destination string, at time.Time) (*dialplanv1.ResolveDialplanResponse, *dialplan.DecisionTrace, error)
+	GetDecisionTrace(ctx context.Context, traceID string) (*dialplan.DecisionTrace, error)
+}
+
+// Service: Admin uç noktalarının tamamının ihtiyaç duyduğu servis metodları.
+type Service interface {
+	ExplainService
+	CalendarService
+}
+
+type explainResponse struct {
+	DialplanID string                  `json:"dialplan_id,omitempty"`
+	TenantID   string                  `json:"tenant_id,omitempty"`
+	Action     string                  `json:"action,omitempty"`
+	Trace      *dialplan.DecisionTrace `json:"trace"`
+}
+
+// RegisterRoutes: Admin uç noktalarını HTTP mux'a ekler.
+//
+//	GET /admin/explain?trace_id=...                       -> Geçmiş bir çağrının karar izi
+//	GET /admin/explain?caller=...&destination=...[&at=]   -> Yan etkisiz canlı değerlendirme (at: RFC3339)
+//	GET /admin/schedules/calendar.ics?schedule_id=...     -> Takvim aboneliği (bkz. registerCalendarRoutes)
+func RegisterRoutes(mux *http.ServeMux, svc Service, log zerolog.Logger) {
+	registerCalendarRoutes(mux, svc, log)
+
+	mux.HandleFunc("/admin/explain", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "yalnızca GET desteklenir")
+			return
+		}
+		q := r.URL.Query()
+
+		if traceID := q.Get("trace_id"); traceID != "" {
+			trace, err := svc.GetDecisionTrace(r.Context(), traceID)
+			if err != nil {
+				writeServiceError(w, log, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, explainResponse{DialplanID: trace.DialplanID, Trace: trace})
+			return
+		}
+
+		caller, destination := q.Get("caller"), q.Get("destination")
+		if destination == "" {
+			writeError(w, http.StatusBadRequest, "trace_id veya destination parametresi gerekli")
+			return
+		}
+		var at time.Time
+		if raw := q.Get("at"); raw != "" {
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "at RFC3339 formatında olmalı")
+				return
+			}
+			at = parsed
+		}
+
+		resp, trace, err := svc.ExplainDialplan(r.Context(), caller, destination, at)
+		if err != nil {
+			// Hata ile sonuçlanan değerlendirmelerde iz yine de döner.
+			writeJSON(w, http.StatusOK, explainResponse{Trace: trace})
+			return
+		}
+		writeJSON(w, http.StatusOK, explainResponse{
+			DialplanID: resp.GetDialplanId(),
+			TenantID:   resp.TenantId,
+			Action:     resp.GetAction().GetAction(),
+			Trace:      trace,
+		})
+	})
+}
+
+func writeServiceError(w http.ResponseWriter, log zerolog.Logger, err error) {
+	switch {
+	case errors.Is(err, dialplan.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, dialplan.ErrInvalidArgument):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		log.Error().Err(err).Str("event", logger.EventHTTPAdminRequestFail).Msg("Admin HTTP isteği başarısız.")
+		writeError(w, http.StatusInternalServerError, "internal error")
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]string{"error": msg})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
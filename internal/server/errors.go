@@ -0,0 +1,121 @@
+// sentiric-dialplan-service/internal/server/errors.go
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog"
+	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
+	"github.com/sentiric/sentiric-dialplan-service/internal/service/dialplan"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain: errdetails.ErrorInfo içinde hatanın kaynağını belirtir.
+const ErrorDomain = "dialplan.sentiric.cloud"
+
+// domainErrorMapping: Servis katmanı sentinel hatalarının gRPC karşılıkları.
+var domainErrorMapping = []struct {
+	err    error
+	code   codes.Code
+	reason string
+	// exposeMessage: false ise iç hata mesajı (SQL detayları vb.) istemciye sızdırılmaz.
+	exposeMessage bool
+}{
+	{dialplan.ErrNotFound, codes.NotFound, "RECORD_NOT_FOUND", true},
+	{dialplan.ErrConflict, codes.AlreadyExists, "RECORD_ALREADY_EXISTS", true},
+	{dialplan.ErrInvalidArgument, codes.InvalidArgument, "INVALID_ARGUMENT", true},
+	{dialplan.ErrReferenceNotFound, codes.FailedPrecondition, "REFERENCED_RECORD_NOT_FOUND", true},
+	{dialplan.ErrInUse, codes.FailedPrecondition, "RECORD_IN_USE", true},
+	{dialplan.ErrNotConfigured, codes.FailedPrecondition, "FEATURE_NOT_CONFIGURED", true},
+	{dialplan.ErrConstraintViolation, codes.FailedPrecondition, "CONSTRAINT_VIOLATION", false},
+	{dialplan.ErrTableMissing, codes.Internal, "DATABASE_TABLE_MISSING", false},
+	{dialplan.ErrDatabaseUnavailable, codes.Unavailable, "DATABASE_UNAVAILABLE", false},
+	{dialplan.ErrDatabase, codes.Internal, "DATABASE_ERROR", false},
+}
+
+// internalErrorMessage: Eşlenmeyen hatalarda istemciye dönen mesaj; asıl hata interceptor'da loglanır.
+const internalErrorMessage = "internal error"
+
+// ErrorMappingInterceptor: Handler'dan dönen domain hatalarını anlamlı gRPC
+// status kodlarına ve errdetails bilgisine çevirir.
+func ErrorMappingInterceptor(log zerolog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		mapped := ToStatusError(err)
+		// Mesajı istemciden gizlenen hataların asıl nedeni yalnızca burada görünür.
+		if code := status.Code(mapped); code == codes.Internal || code == codes.Unavailable || errors.Is(err, dialplan.ErrConstraintViolation) {
+			l := logger.ContextLogger(ctx, log)
+			l.Error().
+				Str("event", logger.EventDomainErrorMapped).
+				Str("grpc.method", info.FullMethod).
+				Str("grpc.code", status.Code(mapped).String()).
+				Err(err).
+				Msg("Altyapı hatası gRPC status'una çevrildi")
+		}
+		return resp, mapped
+	}
+}
+
+// ToStatusError: Bir hatayı gRPC status hatasına dönüştürür.
+// Zaten status taşıyan hatalar olduğu gibi geçirilir.
+func ToStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var valErr *dialplan.ValidationError
+	if errors.As(err, &valErr) {
+		br := &errdetails.BadRequest{}
+		for _, v := range valErr.Violations {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Description,
+			})
+		}
+		st := status.New(codes.InvalidArgument, err.Error())
+		if withDetails, dErr := st.WithDetails(&errdetails.ErrorInfo{Reason: "VALIDATION_FAILED", Domain: ErrorDomain}, br); dErr == nil {
+			return withDetails.Err()
+		}
+		return st.Err()
+	}
+
+	for _, m := range domainErrorMapping {
+		if !errors.Is(err, m.err) {
+			continue
+		}
+		msg := m.err.Error()
+		if m.exposeMessage {
+			msg = err.Error()
+		}
+		st := status.New(m.code, msg)
+
+		info := &errdetails.ErrorInfo{Reason: m.reason, Domain: ErrorDomain}
+		var resErr *dialplan.ResourceError
+		if errors.As(err, &resErr) {
+			if withDetails, dErr := st.WithDetails(info, &errdetails.ResourceInfo{
+				ResourceType: resErr.ResourceType,
+				ResourceName: resErr.ResourceName,
+				Description:  m.err.Error(),
+			}); dErr == nil {
+				return withDetails.Err()
+			}
+			return st.Err()
+		}
+		if withDetails, dErr := st.WithDetails(info); dErr == nil {
+			return withDetails.Err()
+		}
+		return st.Err()
+	}
+
+	return status.Error(codes.Internal, internalErrorMessage)
+}
@@ -0,0 +1,46 @@
+// sentiric-dialplan-service/internal/server/errors_test.go
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sentiric/sentiric-dialplan-service/internal/service/dialplan"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatusError(t *testing.T) {
+	const driverText = `pq: duplicate key value violates "secret_idx"`
+	tests := []struct {
+		name     string
+		err      error
+		code     codes.Code
+		leakFree bool // Mesaj sürücü metnini içermemeli
+	}{
+		{"not found", &dialplan.ResourceError{Err: dialplan.ErrNotFound, ResourceType: "dialplan", ResourceName: "x"}, codes.NotFound, false},
+		{"conflict", dialplan.ErrConflict, codes.AlreadyExists, false},
+		{"validation", &dialplan.ValidationError{Violations: []dialplan.FieldViolation{{Field: "id", Description: "zorunlu"}}}, codes.InvalidArgument, false},
+		{"in use", dialplan.ErrInUse, codes.FailedPrecondition, false},
+		{"not configured", fmt.Errorf("%w: ajan durum deposu", dialplan.ErrNotConfigured), codes.FailedPrecondition, false},
+		{"reference", fmt.Errorf("%w: dialplans.active_dialplan_id", dialplan.ErrReferenceNotFound), codes.FailedPrecondition, false},
+		{"constraint", fmt.Errorf("%w: %s", dialplan.ErrConstraintViolation, driverText), codes.FailedPrecondition, true},
+		{"unavailable", fmt.Errorf("%w: %s", dialplan.ErrDatabaseUnavailable, driverText), codes.Unavailable, true},
+		{"database", fmt.Errorf("%w: %s", dialplan.ErrDatabase, driverText), codes.Internal, true},
+		{"table missing", fmt.Errorf("%w: %s", dialplan.ErrTableMissing, driverText), codes.Internal, true},
+		{"unmapped", errors.New(driverText), codes.Internal, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, _ := status.FromError(ToStatusError(tt.err))
+			if st.Code() != tt.code {
+				t.Fatalf("kod = %s, beklenen %s", st.Code(), tt.code)
+			}
+			if tt.leakFree && strings.Contains(st.Message(), "secret_idx") {
+				t.Fatalf("iç hata metni istemciye sızdı: %q", st.Message())
+			}
+		})
+	}
+}
@@ -69,7 +69,11 @@ func NewServer(cfg config.Config, log zerolog.Logger) (*grpc.Server, error) {
 
 	opts := []grpc.ServerOption{
 		grpc.Creds(credentials.NewTLS(tlsCfg)),
-		grpc.UnaryInterceptor(LoggingInterceptor(log)), // Interceptor Eklendi
+		// Logging en dışta: hata eşleştirmesinden sonraki nihai status kodunu loglar.
+		grpc.ChainUnaryInterceptor(
+			LoggingInterceptor(log),
+			ErrorMappingInterceptor(log),
+		),
 	}
 
 	return grpc.NewServer(opts...), nil
@@ -3,13 +3,18 @@ package grpc
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/rs/zerolog"
 	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/service/dialplan"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 type Service interface {
-	ResolveDialplan(ctx context.Context, caller, destination string) (*dialplanv1.ResolveDialplanResponse, error)
+	ResolveDialplan(ctx context.Context, caller, destination string, opts dialplan.ResolveOptions) (*dialplanv1.ResolveDialplanResponse, error)
 
 	// Routes
 	CreateInboundRoute(ctx context.Context, route *dialplanv1.InboundRoute) error
@@ -31,10 +36,53 @@ type Service interface {
 	UpdateQueue(ctx context.Context, req *dialplanv1.UpdateQueueRequest) error
 	DeleteQueue(ctx context.Context, id string) error
 	ListQueues(ctx context.Context, req *dialplanv1.ListQueuesRequest) (*dialplanv1.ListQueuesResponse, error)
+	AddQueueMember(ctx context.Context, m *dialplanv1.QueueMember) error
+	RemoveQueueMember(ctx context.Context, queueID, agentID string) error
+	ListQueueMembers(ctx context.Context, queueID string) ([]*dialplanv1.QueueMember, error)
+	SetAgentStatus(ctx context.Context, agentID, status string) (*dialplanv1.AgentState, error)
+	SelectQueueAgent(ctx context.Context, req *dialplanv1.SelectQueueAgentRequest) (*dialplanv1.SelectQueueAgentResponse, error)
+	SetAgentSkills(ctx context.Context, agentID string, skills []*dialplanv1.AgentSkill) ([]*dialplanv1.AgentSkill, error)
+	ListAgentSkills(ctx context.Context, agentID string) ([]*dialplanv1.AgentSkill, error)
+	EnqueueCall(ctx context.Context, queueID, callID, priority string) (*dialplanv1.QueuePosition, error)
+	GetQueuePosition(ctx context.Context, queueID, callID string) (*dialplanv1.QueuePosition, error)
+	DequeueCall(ctx context.Context, queueID, callID, agentID string) (time.Duration, error)
+	AbandonCall(ctx context.Context, queueID, callID string) (time.Duration, error)
+	GetQueueStats(ctx context.Context, queueID string) (*dialplanv1.QueueStats, error)
 
 	// [YENİ] Schedules
 	CreateSchedule(ctx context.Context, req *dialplanv1.CreateScheduleRequest) error
 	GetSchedule(ctx context.Context, id string) (*dialplanv1.Schedule, error)
+	UpdateSchedule(ctx context.Context, req *dialplanv1.UpdateScheduleRequest) error
+	DeleteSchedule(ctx context.Context, id string) error
+	ListSchedules(ctx context.Context, req *dialplanv1.ListSchedulesRequest) (*dialplanv1.ListSchedulesResponse, error)
+	GetScheduleStatus(ctx context.Context, req *dialplanv1.GetScheduleStatusRequest) (*dialplanv1.GetScheduleStatusResponse, error)
+	ImportScheduleIcs(ctx context.Context, req *dialplanv1.ImportScheduleIcsRequest) (*dialplanv1.ImportScheduleIcsResponse, error)
+	ExportScheduleIcs(ctx context.Context, req *dialplanv1.ExportScheduleIcsRequest) (*dialplanv1.ExportScheduleIcsResponse, error)
+
+	// Schedule Overrides
+	CreateScheduleOverride(ctx context.Context, req *dialplanv1.CreateScheduleOverrideRequest) error
+	ListScheduleOverrides(ctx context.Context, req *dialplanv1.ListScheduleOverridesRequest) (*dialplanv1.ListScheduleOverridesResponse, error)
+	DeleteScheduleOverride(ctx context.Context, id string) error
+
+	// Holiday Calendars
+	CreateHolidayCalendar(ctx context.Context, req *dialplanv1.CreateHolidayCalendarRequest) error
+	GetHolidayCalendar(ctx context.Context, id string) (*dialplanv1.HolidayCalendar, error)
+	UpdateHolidayCalendar(ctx context.Context, req *dialplanv1.UpdateHolidayCalendarRequest) error
+	DeleteHolidayCalendar(ctx context.Context, id string) error
+	ListHolidayCalendars(ctx context.Context, req *dialplanv1.ListHolidayCalendarsRequest) (*dialplanv1.ListHolidayCalendarsResponse, error)
+
+	// Caller Lists
+	CreateCallerListEntry(ctx context.Context, req *dialplanv1.CreateCallerListEntryRequest) error
+	GetCallerListEntry(ctx context.Context, id string) (*dialplanv1.CallerListEntry, error)
+	UpdateCallerListEntry(ctx context.Context, req *dialplanv1.UpdateCallerListEntryRequest) error
+	DeleteCallerListEntry(ctx context.Context, id string) error
+	ListCallerListEntries(ctx context.Context, req *dialplanv1.ListCallerListEntriesRequest) (*dialplanv1.ListCallerListEntriesResponse, error)
+	ImportCallerListEntries(ctx context.Context, req *dialplanv1.ImportCallerListEntriesRequest) (*dialplanv1.ImportCallerListEntriesResponse, error)
+
+	// Call Capacity
+	ReleaseCall(ctx context.Context, callID string) (bool, error)
+	SetTenantCallLimit(ctx context.Context, req *dialplanv1.SetTenantCallLimitRequest) error
+	GetTenantCallLimit(ctx context.Context, tenantID string) (*dialplanv1.TenantCallLimit, error)
 }
 
 type Handler struct {
@@ -47,11 +95,43 @@ func NewHandler(svc Service, log zerolog.Logger) *Handler {
 	return &Handler{svc: svc, log: log}
 }
 
-// --- Mevcut Metodlar (Değişiklik Yok) ---
+// --- Resolve Handler ---
 func (h *Handler) ResolveDialplan(ctx context.Context, req *dialplanv1.ResolveDialplanRequest) (*dialplanv1.ResolveDialplanResponse, error) {
-	return h.svc.ResolveDialplan(ctx, req.GetCallerContactValue(), req.GetDestinationNumber())
+	opts, err := resolveOptionsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Dry-run raporu (would_provision) karar izi üzerinden taşındığı için iz her durumda toplanır.
+	explain := opts.Trace != nil
+	if opts.DryRun && opts.Trace == nil {
+		opts.Trace = &dialplan.DecisionTrace{}
+	}
+
+	resp, err := h.svc.ResolveDialplan(ctx, req.GetCallerContactValue(), req.GetDestinationNumber(), opts)
+
+	header := metadata.MD{}
+	if explain {
+		if data, mErr := json.Marshal(opts.Trace); mErr == nil {
+			header.Set(MetadataTrace, string(data))
+		}
+	}
+	if opts.DryRun {
+		header.Set(MetadataDryRun, "true")
+		if opts.Trace.WouldProvision != nil {
+			if data, mErr := json.Marshal(opts.Trace.WouldProvision); mErr == nil {
+				header.Set(MetadataWouldProvision, string(data))
+			}
+		}
+	}
+	if header.Len() > 0 {
+		if hErr := grpc.SetHeader(ctx, header); hErr != nil {
+			h.log.Warn().Err(hErr).Msg("Resolve rapor başlıkları yanıta eklenemedi.")
+		}
+	}
+	return resp, err
 }
 
+// --- InboundRoute Handlers ---
 func (h *Handler) CreateInboundRoute(ctx context.Context, req *dialplanv1.CreateInboundRouteRequest) (*dialplanv1.CreateInboundRouteResponse, error) {
 	if err := h.svc.CreateInboundRoute(ctx, req.GetRoute()); err != nil {
 		return nil, err
@@ -59,8 +139,65 @@ func (h *Handler) CreateInboundRoute(ctx context.Context, req *dialplanv1.Create
 	return &dialplanv1.CreateInboundRouteResponse{Route: req.GetRoute()}, nil
 }
 
-// ... (Diğer InboundRoute metodları aynı) ...
-// ... (Diğer Dialplan metodları aynı) ...
+func (h *Handler) GetInboundRoute(ctx context.Context, req *dialplanv1.GetInboundRouteRequest) (*dialplanv1.GetInboundRouteResponse, error) {
+	route, err := h.svc.GetInboundRoute(ctx, req.GetPhoneNumber())
+	if err != nil {
+		return nil, err
+	}
+	return &dialplanv1.GetInboundRouteResponse{Route: route}, nil
+}
+
+func (h *Handler) UpdateInboundRoute(ctx context.Context, req *dialplanv1.UpdateInboundRouteRequest) (*dialplanv1.UpdateInboundRouteResponse, error) {
+	if err := h.svc.UpdateInboundRoute(ctx, req.GetRoute()); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.UpdateInboundRouteResponse{Route: req.GetRoute()}, nil
+}
+
+func (h *Handler) DeleteInboundRoute(ctx context.Context, req *dialplanv1.DeleteInboundRouteRequest) (*dialplanv1.DeleteInboundRouteResponse, error) {
+	if err := h.svc.DeleteInboundRoute(ctx, req.GetPhoneNumber()); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.DeleteInboundRouteResponse{Success: true}, nil
+}
+
+func (h *Handler) ListInboundRoutes(ctx context.Context, req *dialplanv1.ListInboundRoutesRequest) (*dialplanv1.ListInboundRoutesResponse, error) {
+	return h.svc.ListInboundRoutes(ctx, req)
+}
+
+// --- Dialplan Handlers ---
+func (h *Handler) CreateDialplan(ctx context.Context, req *dialplanv1.CreateDialplanRequest) (*dialplanv1.CreateDialplanResponse, error) {
+	if err := h.svc.CreateDialplan(ctx, req); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.CreateDialplanResponse{Dialplan: req.GetDialplan()}, nil
+}
+
+func (h *Handler) GetDialplan(ctx context.Context, req *dialplanv1.GetDialplanRequest) (*dialplanv1.GetDialplanResponse, error) {
+	dp, err := h.svc.GetDialplan(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &dialplanv1.GetDialplanResponse{Dialplan: dp}, nil
+}
+
+func (h *Handler) UpdateDialplan(ctx context.Context, req *dialplanv1.UpdateDialplanRequest) (*dialplanv1.UpdateDialplanResponse, error) {
+	if err := h.svc.UpdateDialplan(ctx, req); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.UpdateDialplanResponse{Dialplan: req.GetDialplan()}, nil
+}
+
+func (h *Handler) DeleteDialplan(ctx context.Context, req *dialplanv1.DeleteDialplanRequest) (*dialplanv1.DeleteDialplanResponse, error) {
+	if err := h.svc.DeleteDialplan(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.DeleteDialplanResponse{Success: true}, nil
+}
+
+func (h *Handler) ListDialplans(ctx context.Context, req *dialplanv1.ListDialplansRequest) (*dialplanv1.ListDialplansResponse, error) {
+	return h.svc.ListDialplans(ctx, req)
+}
 
 // --- [YENİ] Queue Handlers ---
 func (h *Handler) CreateQueue(ctx context.Context, req *dialplanv1.CreateQueueRequest) (*dialplanv1.CreateQueueResponse, error) {
@@ -96,6 +233,96 @@ func (h *Handler) ListQueues(ctx context.Context, req *dialplanv1.ListQueuesRequ
 	return h.svc.ListQueues(ctx, req)
 }
 
+func (h *Handler) AddQueueMember(ctx context.Context, req *dialplanv1.AddQueueMemberRequest) (*dialplanv1.AddQueueMemberResponse, error) {
+	if err := h.svc.AddQueueMember(ctx, req.GetMember()); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.AddQueueMemberResponse{Member: req.GetMember()}, nil
+}
+
+func (h *Handler) RemoveQueueMember(ctx context.Context, req *dialplanv1.RemoveQueueMemberRequest) (*dialplanv1.RemoveQueueMemberResponse, error) {
+	if err := h.svc.RemoveQueueMember(ctx, req.GetQueueId(), req.GetAgentId()); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.RemoveQueueMemberResponse{Success: true}, nil
+}
+
+func (h *Handler) ListQueueMembers(ctx context.Context, req *dialplanv1.ListQueueMembersRequest) (*dialplanv1.ListQueueMembersResponse, error) {
+	members, err := h.svc.ListQueueMembers(ctx, req.GetQueueId())
+	if err != nil {
+		return nil, err
+	}
+	return &dialplanv1.ListQueueMembersResponse{Members: members}, nil
+}
+
+func (h *Handler) SetAgentStatus(ctx context.Context, req *dialplanv1.SetAgentStatusRequest) (*dialplanv1.SetAgentStatusResponse, error) {
+	state, err := h.svc.SetAgentStatus(ctx, req.GetAgentId(), req.GetStatus())
+	if err != nil {
+		return nil, err
+	}
+	return &dialplanv1.SetAgentStatusResponse{State: state}, nil
+}
+
+func (h *Handler) SelectQueueAgent(ctx context.Context, req *dialplanv1.SelectQueueAgentRequest) (*dialplanv1.SelectQueueAgentResponse, error) {
+	return h.svc.SelectQueueAgent(ctx, req)
+}
+
+func (h *Handler) SetAgentSkills(ctx context.Context, req *dialplanv1.SetAgentSkillsRequest) (*dialplanv1.SetAgentSkillsResponse, error) {
+	skills, err := h.svc.SetAgentSkills(ctx, req.GetAgentId(), req.GetSkills())
+	if err != nil {
+		return nil, err
+	}
+	return &dialplanv1.SetAgentSkillsResponse{Skills: skills}, nil
+}
+
+func (h *Handler) ListAgentSkills(ctx context.Context, req *dialplanv1.ListAgentSkillsRequest) (*dialplanv1.ListAgentSkillsResponse, error) {
+	skills, err := h.svc.ListAgentSkills(ctx, req.GetAgentId())
+	if err != nil {
+		return nil, err
+	}
+	return &dialplanv1.ListAgentSkillsResponse{Skills: skills}, nil
+}
+
+func (h *Handler) EnqueueCall(ctx context.Context, req *dialplanv1.EnqueueCallRequest) (*dialplanv1.EnqueueCallResponse, error) {
+	pos, err := h.svc.EnqueueCall(ctx, req.GetQueueId(), req.GetCallId(), req.GetPriority())
+	if err != nil {
+		return nil, err
+	}
+	return &dialplanv1.EnqueueCallResponse{Position: pos}, nil
+}
+
+func (h *Handler) GetQueuePosition(ctx context.Context, req *dialplanv1.GetQueuePositionRequest) (*dialplanv1.GetQueuePositionResponse, error) {
+	pos, err := h.svc.GetQueuePosition(ctx, req.GetQueueId(), req.GetCallId())
+	if err != nil {
+		return nil, err
+	}
+	return &dialplanv1.GetQueuePositionResponse{Position: pos}, nil
+}
+
+func (h *Handler) DequeueCall(ctx context.Context, req *dialplanv1.DequeueCallRequest) (*dialplanv1.DequeueCallResponse, error) {
+	waited, err := h.svc.DequeueCall(ctx, req.GetQueueId(), req.GetCallId(), req.GetAgentId())
+	if err != nil {
+		return nil, err
+	}
+	return &dialplanv1.DequeueCallResponse{WaitSeconds: int32(waited / time.Second)}, nil
+}
+
+func (h *Handler) AbandonCall(ctx context.Context, req *dialplanv1.AbandonCallRequest) (*dialplanv1.AbandonCallResponse, error) {
+	waited, err := h.svc.AbandonCall(ctx, req.GetQueueId(), req.GetCallId())
+	if err != nil {
+		return nil, err
+	}
+	return &dialplanv1.AbandonCallResponse{WaitSeconds: int32(waited / time.Second)}, nil
+}
+
+func (h *Handler) GetQueueStats(ctx context.Context, req *dialplanv1.GetQueueStatsRequest) (*dialplanv1.GetQueueStatsResponse, error) {
+	stats, err := h.svc.GetQueueStats(ctx, req.GetQueueId())
+	if err != nil {
+		return nil, err
+	}
+	return &dialplanv1.GetQueueStatsResponse{Stats: stats}, nil
+}
+
 // --- [YENİ] Schedule Handlers ---
 func (h *Handler) CreateSchedule(ctx context.Context, req *dialplanv1.CreateScheduleRequest) (*dialplanv1.CreateScheduleResponse, error) {
 	if err := h.svc.CreateSchedule(ctx, req); err != nil {
@@ -111,3 +338,148 @@ func (h *Handler) GetSchedule(ctx context.Context, req *dialplanv1.GetScheduleRe
 	}
 	return &dialplanv1.GetScheduleResponse{Schedule: s}, nil
 }
+
+func (h *Handler) UpdateSchedule(ctx context.Context, req *dialplanv1.UpdateScheduleRequest) (*dialplanv1.UpdateScheduleResponse, error) {
+	if err := h.svc.UpdateSchedule(ctx, req); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.UpdateScheduleResponse{Schedule: req.GetSchedule()}, nil
+}
+
+func (h *Handler) DeleteSchedule(ctx context.Context, req *dialplanv1.DeleteScheduleRequest) (*dialplanv1.DeleteScheduleResponse, error) {
+	if err := h.svc.DeleteSchedule(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.DeleteScheduleResponse{Success: true}, nil
+}
+
+func (h *Handler) ListSchedules(ctx context.Context, req *dialplanv1.ListSchedulesRequest) (*dialplanv1.ListSchedulesResponse, error) {
+	return h.svc.ListSchedules(ctx, req)
+}
+
+func (h *Handler) GetScheduleStatus(ctx context.Context, req *dialplanv1.GetScheduleStatusRequest) (*dialplanv1.GetScheduleStatusResponse, error) {
+	return h.svc.GetScheduleStatus(ctx, req)
+}
+
+func (h *Handler) ImportScheduleIcs(ctx context.Context, req *dialplanv1.ImportScheduleIcsRequest) (*dialplanv1.ImportScheduleIcsResponse, error) {
+	return h.svc.ImportScheduleIcs(ctx, req)
+}
+
+func (h *Handler) ExportScheduleIcs(ctx context.Context, req *dialplanv1.ExportScheduleIcsRequest) (*dialplanv1.ExportScheduleIcsResponse, error) {
+	return h.svc.ExportScheduleIcs(ctx, req)
+}
+
+// --- Schedule Override Handlers ---
+func (h *Handler) CreateScheduleOverride(ctx context.Context, req *dialplanv1.CreateScheduleOverrideRequest) (*dialplanv1.CreateScheduleOverrideResponse, error) {
+	if err := h.svc.CreateScheduleOverride(ctx, req); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.CreateScheduleOverrideResponse{Override: req.GetOverride()}, nil
+}
+
+func (h *Handler) ListScheduleOverrides(ctx context.Context, req *dialplanv1.ListScheduleOverridesRequest) (*dialplanv1.ListScheduleOverridesResponse, error) {
+	return h.svc.ListScheduleOverrides(ctx, req)
+}
+
+func (h *Handler) DeleteScheduleOverride(ctx context.Context, req *dialplanv1.DeleteScheduleOverrideRequest) (*dialplanv1.DeleteScheduleOverrideResponse, error) {
+	if err := h.svc.DeleteScheduleOverride(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.DeleteScheduleOverrideResponse{Success: true}, nil
+}
+
+// --- Holiday Calendar Handlers ---
+func (h *Handler) CreateHolidayCalendar(ctx context.Context, req *dialplanv1.CreateHolidayCalendarRequest) (*dialplanv1.CreateHolidayCalendarResponse, error) {
+	if err := h.svc.CreateHolidayCalendar(ctx, req); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.CreateHolidayCalendarResponse{Calendar: req.GetCalendar()}, nil
+}
+
+func (h *Handler) GetHolidayCalendar(ctx context.Context, req *dialplanv1.GetHolidayCalendarRequest) (*dialplanv1.GetHolidayCalendarResponse, error) {
+	c, err := h.svc.GetHolidayCalendar(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &dialplanv1.GetHolidayCalendarResponse{Calendar: c}, nil
+}
+
+func (h *Handler) UpdateHolidayCalendar(ctx context.Context, req *dialplanv1.UpdateHolidayCalendarRequest) (*dialplanv1.UpdateHolidayCalendarResponse, error) {
+	if err := h.svc.UpdateHolidayCalendar(ctx, req); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.UpdateHolidayCalendarResponse{Calendar: req.GetCalendar()}, nil
+}
+
+func (h *Handler) DeleteHolidayCalendar(ctx context.Context, req *dialplanv1.DeleteHolidayCalendarRequest) (*dialplanv1.DeleteHolidayCalendarResponse, error) {
+	if err := h.svc.DeleteHolidayCalendar(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.DeleteHolidayCalendarResponse{Success: true}, nil
+}
+
+func (h *Handler) ListHolidayCalendars(ctx context.Context, req *dialplanv1.ListHolidayCalendarsRequest) (*dialplanv1.ListHolidayCalendarsResponse, error) {
+	return h.svc.ListHolidayCalendars(ctx, req)
+}
+
+// --- Caller List Handlers ---
+func (h *Handler) CreateCallerListEntry(ctx context.Context, req *dialplanv1.CreateCallerListEntryRequest) (*dialplanv1.CreateCallerListEntryResponse, error) {
+	if err := h.svc.CreateCallerListEntry(ctx, req); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.CreateCallerListEntryResponse{Entry: req.GetEntry()}, nil
+}
+
+func (h *Handler) GetCallerListEntry(ctx context.Context, req *dialplanv1.GetCallerListEntryRequest) (*dialplanv1.GetCallerListEntryResponse, error) {
+	e, err := h.svc.GetCallerListEntry(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &dialplanv1.GetCallerListEntryResponse{Entry: e}, nil
+}
+
+func (h *Handler) UpdateCallerListEntry(ctx context.Context, req *dialplanv1.UpdateCallerListEntryRequest) (*dialplanv1.UpdateCallerListEntryResponse, error) {
+	if err := h.svc.UpdateCallerListEntry(ctx, req); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.UpdateCallerListEntryResponse{Entry: req.GetEntry()}, nil
+}
+
+func (h *Handler) DeleteCallerListEntry(ctx context.Context, req *dialplanv1.DeleteCallerListEntryRequest) (*dialplanv1.DeleteCallerListEntryResponse, error) {
+	if err := h.svc.DeleteCallerListEntry(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.DeleteCallerListEntryResponse{Success: true}, nil
+}
+
+func (h *Handler) ListCallerListEntries(ctx context.Context, req *dialplanv1.ListCallerListEntriesRequest) (*dialplanv1.ListCallerListEntriesResponse, error) {
+	return h.svc.ListCallerListEntries(ctx, req)
+}
+
+func (h *Handler) ImportCallerListEntries(ctx context.Context, req *dialplanv1.ImportCallerListEntriesRequest) (*dialplanv1.ImportCallerListEntriesResponse, error) {
+	return h.svc.ImportCallerListEntries(ctx, req)
+}
+
+// --- Call Capacity Handlers ---
+func (h *Handler) ReleaseCall(ctx context.Context, req *dialplanv1.ReleaseCallRequest) (*dialplanv1.ReleaseCallResponse, error) {
+	released, err := h.svc.ReleaseCall(ctx, req.GetCallId())
+	if err != nil {
+		return nil, err
+	}
+	return &dialplanv1.ReleaseCallResponse{Released: released}, nil
+}
+
+func (h *Handler) SetTenantCallLimit(ctx context.Context, req *dialplanv1.SetTenantCallLimitRequest) (*dialplanv1.SetTenantCallLimitResponse, error) {
+	if err := h.svc.SetTenantCallLimit(ctx, req); err != nil {
+		return nil, err
+	}
+	return &dialplanv1.SetTenantCallLimitResponse{Limit: req.GetLimit()}, nil
+}
+
+func (h *Handler) GetTenantCallLimit(ctx context.Context, req *dialplanv1.GetTenantCallLimitRequest) (*dialplanv1.GetTenantCallLimitResponse, error) {
+	limit, err := h.svc.GetTenantCallLimit(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+	return &dialplanv1.GetTenantCallLimitResponse{Limit: limit}, nil
+}
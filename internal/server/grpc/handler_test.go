@@ -0,0 +1,404 @@
+// sentiric-dialplan-service/internal/server/grpc/handler_test.go
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	server "github.com/sentiric/sentiric-dialplan-service/internal/server"
+	"github.com/sentiric/sentiric-dialplan-service/internal/service/dialplan"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// memRepo: Route ve dialplan tabloları için bellek içi repository. Diğer metodlar çağrılırsa panik eder
+// (gömülü nil arayüz), böylece testin beklenmeyen bir yola sapması fark edilir.
+type memRepo struct {
+	dialplan.Repository
+
+	mu        sync.Mutex
+	routes    map[string]*dialplanv1.InboundRoute
+	dialplans map[string]*dialplanv1.Dialplan
+}
+
+func newMemRepo() *memRepo {
+	return &memRepo{
+		routes:    map[string]*dialplanv1.InboundRoute{},
+		dialplans: map[string]*dialplanv1.Dialplan{},
+	}
+}
+
+func (r *memRepo) FindInboundRouteByPhone(_ context.Context, phoneNumber string) (*dialplanv1.InboundRoute, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	route, ok := r.routes[phoneNumber]
+	if !ok {
+		return nil, dialplan.ErrNotFound
+	}
+	return route, nil
+}
+
+func (r *memRepo) CreateInboundRoute(_ context.Context, route *dialplanv1.InboundRoute) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.routes[route.PhoneNumber]; ok {
+		return dialplan.ErrConflict
+	}
+	r.routes[route.PhoneNumber] = route
+	return nil
+}
+
+func (r *memRepo) UpdateInboundRoute(_ context.Context, route *dialplanv1.InboundRoute) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.routes[route.PhoneNumber]; !ok {
+		return 0, nil
+	}
+	r.routes[route.PhoneNumber] = route
+	return 1, nil
+}
+
+func (r *memRepo) DeleteInboundRoute(_ context.Context, phoneNumber string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.routes[phoneNumber]; !ok {
+		return 0, nil
+	}
+	delete(r.routes, phoneNumber)
+	return 1, nil
+}
+
+func (r *memRepo) ListInboundRoutes(_ context.Context, tenantID string, pageSize, offset int32) ([]*dialplanv1.InboundRoute, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*dialplanv1.InboundRoute
+	for _, route := range r.routes {
+		if tenantID == "" || route.TenantId == tenantID {
+			out = append(out, route)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].PhoneNumber < out[j].PhoneNumber })
+	return page(out, pageSize, offset), nil
+}
+
+func (r *memRepo) CountInboundRoutes(ctx context.Context, tenantID string) (int32, error) {
+	list, err := r.ListInboundRoutes(ctx, tenantID, 0, 0)
+	return int32(len(list)), err
+}
+
+func (r *memRepo) FindDialplanByID(_ context.Context, id string) (*dialplanv1.Dialplan, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	dp, ok := r.dialplans[id]
+	if !ok {
+		return nil, dialplan.ErrNotFound
+	}
+	return dp, nil
+}
+
+func (r *memRepo) CreateDialplan(_ context.Context, dp *dialplanv1.Dialplan, _ []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.dialplans[dp.Id]; ok {
+		return dialplan.ErrConflict
+	}
+	r.dialplans[dp.Id] = dp
+	return nil
+}
+
+func (r *memRepo) UpdateDialplan(_ context.Context, dp *dialplanv1.Dialplan, _ []byte) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.dialplans[dp.Id]; !ok {
+		return 0, nil
+	}
+	r.dialplans[dp.Id] = dp
+	return 1, nil
+}
+
+func (r *memRepo) DeleteDialplan(_ context.Context, id string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.dialplans[id]; !ok {
+		return 0, nil
+	}
+	// Postgres'teki yabancı anahtarın karşılığı: route'ların kullandığı dialplan silinemez.
+	for _, route := range r.routes {
+		if route.GetActiveDialplanId() == id {
+			return 0, fmt.Errorf("%w: inbound_routes", dialplan.ErrInUse)
+		}
+	}
+	delete(r.dialplans, id)
+	return 1, nil
+}
+
+func (r *memRepo) ListDialplans(_ context.Context, tenantID string, pageSize, offset int32) ([]*dialplanv1.Dialplan, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*dialplanv1.Dialplan
+	for _, dp := range r.dialplans {
+		if tenantID == "" || dp.TenantId == tenantID {
+			out = append(out, dp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Id < out[j].Id })
+	return page(out, pageSize, offset), nil
+}
+
+func (r *memRepo) CountDialplans(ctx context.Context, tenantID string) (int32, error) {
+	list, err := r.ListDialplans(ctx, tenantID, 0, 0)
+	return int32(len(list)), err
+}
+
+// page: LIMIT/OFFSET karşılığı; pageSize 0 ise tüm liste döner.
+func page[T any](list []T, pageSize, offset int32) []T {
+	if offset > int32(len(list)) {
+		return nil
+	}
+	list = list[offset:]
+	if pageSize > 0 && pageSize < int32(len(list)) {
+		list = list[:pageSize]
+	}
+	return list
+}
+
+// startServer: Gerçek dialplan servisini bellek içi repository ile, üretimdeki interceptor zinciriyle
+// bufconn üzerinde çalışan bir gRPC sunucusunda başlatır.
+func startServer(t *testing.T, repo dialplan.Repository) dialplanv1.DialplanServiceClient {
+	t.Helper()
+	log := zerolog.Nop()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		server.LoggingInterceptor(log),
+		server.ErrorMappingInterceptor(log),
+	))
+	dialplanv1.RegisterDialplanServiceServer(srv, NewHandler(dialplan.NewService(repo, nil, nil, log), log))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("bufconn bağlantısı kurulamadı: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return dialplanv1.NewDialplanServiceClient(conn)
+}
+
+func strPtr(s string) *string { return &s }
+
+// wantCode: err'in beklenen gRPC koduna ve errdetails.ErrorInfo nedenine sahip olduğunu doğrular.
+func wantCode(t *testing.T, err error, code codes.Code, reason string) *status.Status {
+	t.Helper()
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatalf("status hatası bekleniyordu, gelen: %v", err)
+	}
+	if st.Code() != code {
+		t.Fatalf("kod = %s (%s), beklenen %s", st.Code(), st.Message(), code)
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			if info.Reason != reason {
+				t.Fatalf("ErrorInfo.Reason = %q, beklenen %q", info.Reason, reason)
+			}
+			return st
+		}
+	}
+	t.Fatalf("ErrorInfo detayı yok: %v", st.Details())
+	return st
+}
+
+func resourceInfo(st *status.Status) *errdetails.ResourceInfo {
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.ResourceInfo); ok {
+			return ri
+		}
+	}
+	return nil
+}
+
+func TestInboundRouteRPCs(t *testing.T) {
+	ctx := context.Background()
+	client := startServer(t, newMemRepo())
+
+	route := &dialplanv1.InboundRoute{PhoneNumber: "902125550000", TenantId: "acme", ActiveDialplanId: strPtr("DP_WELCOME")}
+	if _, err := client.CreateInboundRoute(ctx, &dialplanv1.CreateInboundRouteRequest{Route: route}); err != nil {
+		t.Fatalf("CreateInboundRoute: %v", err)
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		resp, err := client.GetInboundRoute(ctx, &dialplanv1.GetInboundRouteRequest{PhoneNumber: route.PhoneNumber})
+		if err != nil {
+			t.Fatalf("GetInboundRoute: %v", err)
+		}
+		if resp.Route.GetTenantId() != "acme" || resp.Route.GetActiveDialplanId() != "DP_WELCOME" {
+			t.Fatalf("beklenmeyen route: %+v", resp.Route)
+		}
+	})
+
+	t.Run("GetMissing", func(t *testing.T) {
+		_, err := client.GetInboundRoute(ctx, &dialplanv1.GetInboundRouteRequest{PhoneNumber: "900000"})
+		st := wantCode(t, err, codes.NotFound, "RECORD_NOT_FOUND")
+		if ri := resourceInfo(st); ri == nil || ri.ResourceType != dialplan.ResourceInboundRoute || ri.ResourceName != "900000" {
+			t.Fatalf("beklenmeyen ResourceInfo: %+v", ri)
+		}
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		_, err := client.CreateInboundRoute(ctx, &dialplanv1.CreateInboundRouteRequest{Route: route})
+		wantCode(t, err, codes.AlreadyExists, "RECORD_ALREADY_EXISTS")
+	})
+
+	t.Run("UpdateMissing", func(t *testing.T) {
+		_, err := client.UpdateInboundRoute(ctx, &dialplanv1.UpdateInboundRouteRequest{Route: &dialplanv1.InboundRoute{PhoneNumber: "900000", TenantId: "acme"}})
+		wantCode(t, err, codes.NotFound, "RECORD_NOT_FOUND")
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		updated := &dialplanv1.InboundRoute{PhoneNumber: route.PhoneNumber, TenantId: "acme", ActiveDialplanId: strPtr("DP_SALES")}
+		resp, err := client.UpdateInboundRoute(ctx, &dialplanv1.UpdateInboundRouteRequest{Route: updated})
+		if err != nil {
+			t.Fatalf("UpdateInboundRoute: %v", err)
+		}
+		if resp.Route.GetActiveDialplanId() != "DP_SALES" {
+			t.Fatalf("yanıt güncel route'u taşımıyor: %+v", resp.Route)
+		}
+		got, err := client.GetInboundRoute(ctx, &dialplanv1.GetInboundRouteRequest{PhoneNumber: route.PhoneNumber})
+		if err != nil || got.Route.GetActiveDialplanId() != "DP_SALES" {
+			t.Fatalf("güncelleme kalıcı değil: %+v, %v", got, err)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		other := &dialplanv1.InboundRoute{PhoneNumber: "903125550000", TenantId: "globex"}
+		if _, err := client.CreateInboundRoute(ctx, &dialplanv1.CreateInboundRouteRequest{Route: other}); err != nil {
+			t.Fatalf("CreateInboundRoute: %v", err)
+		}
+		resp, err := client.ListInboundRoutes(ctx, &dialplanv1.ListInboundRoutesRequest{TenantId: "acme", Page: 1, PageSize: 10})
+		if err != nil {
+			t.Fatalf("ListInboundRoutes: %v", err)
+		}
+		if resp.TotalCount != 1 || len(resp.Routes) != 1 || resp.Routes[0].PhoneNumber != route.PhoneNumber {
+			t.Fatalf("beklenmeyen liste: %+v", resp)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		resp, err := client.DeleteInboundRoute(ctx, &dialplanv1.DeleteInboundRouteRequest{PhoneNumber: route.PhoneNumber})
+		if err != nil || !resp.Success {
+			t.Fatalf("DeleteInboundRoute: %+v, %v", resp, err)
+		}
+		_, err = client.GetInboundRoute(ctx, &dialplanv1.GetInboundRouteRequest{PhoneNumber: route.PhoneNumber})
+		wantCode(t, err, codes.NotFound, "RECORD_NOT_FOUND")
+	})
+
+	t.Run("DeleteMissing", func(t *testing.T) {
+		_, err := client.DeleteInboundRoute(ctx, &dialplanv1.DeleteInboundRouteRequest{PhoneNumber: route.PhoneNumber})
+		wantCode(t, err, codes.NotFound, "RECORD_NOT_FOUND")
+	})
+}
+
+func TestDialplanRPCs(t *testing.T) {
+	ctx := context.Background()
+	client := startServer(t, newMemRepo())
+
+	dp := &dialplanv1.Dialplan{
+		Id: "DP_WELCOME", TenantId: "acme", Description: "Karşılama",
+		Action: &dialplanv1.DialplanAction{Action: "PLAY_ANNOUNCEMENT", ActionData: map[string]string{"announcement_id": "ANNOUNCE_WELCOME"}},
+	}
+	if _, err := client.CreateDialplan(ctx, &dialplanv1.CreateDialplanRequest{Dialplan: dp}); err != nil {
+		t.Fatalf("CreateDialplan: %v", err)
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		resp, err := client.GetDialplan(ctx, &dialplanv1.GetDialplanRequest{Id: dp.Id})
+		if err != nil {
+			t.Fatalf("GetDialplan: %v", err)
+		}
+		if resp.Dialplan.GetAction().GetActionData()["announcement_id"] != "ANNOUNCE_WELCOME" {
+			t.Fatalf("beklenmeyen dialplan: %+v", resp.Dialplan)
+		}
+	})
+
+	t.Run("GetMissing", func(t *testing.T) {
+		_, err := client.GetDialplan(ctx, &dialplanv1.GetDialplanRequest{Id: "DP_MISSING"})
+		st := wantCode(t, err, codes.NotFound, "RECORD_NOT_FOUND")
+		if ri := resourceInfo(st); ri == nil || ri.ResourceType != dialplan.ResourceDialplan || ri.ResourceName != "DP_MISSING" {
+			t.Fatalf("beklenmeyen ResourceInfo: %+v", ri)
+		}
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		_, err := client.CreateDialplan(ctx, &dialplanv1.CreateDialplanRequest{Dialplan: dp})
+		wantCode(t, err, codes.AlreadyExists, "RECORD_ALREADY_EXISTS")
+	})
+
+	t.Run("UpdateMissing", func(t *testing.T) {
+		_, err := client.UpdateDialplan(ctx, &dialplanv1.UpdateDialplanRequest{Dialplan: &dialplanv1.Dialplan{Id: "DP_MISSING", Action: &dialplanv1.DialplanAction{Action: "HANGUP"}}})
+		wantCode(t, err, codes.NotFound, "RECORD_NOT_FOUND")
+	})
+
+	t.Run("DeleteInUse", func(t *testing.T) {
+		route := &dialplanv1.InboundRoute{PhoneNumber: "902125550000", TenantId: "acme", ActiveDialplanId: strPtr(dp.Id)}
+		if _, err := client.CreateInboundRoute(ctx, &dialplanv1.CreateInboundRouteRequest{Route: route}); err != nil {
+			t.Fatalf("CreateInboundRoute: %v", err)
+		}
+		_, err := client.DeleteDialplan(ctx, &dialplanv1.DeleteDialplanRequest{Id: dp.Id})
+		wantCode(t, err, codes.FailedPrecondition, "RECORD_IN_USE")
+		if _, err := client.DeleteInboundRoute(ctx, &dialplanv1.DeleteInboundRouteRequest{PhoneNumber: route.PhoneNumber}); err != nil {
+			t.Fatalf("DeleteInboundRoute: %v", err)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		updated := &dialplanv1.Dialplan{Id: dp.Id, TenantId: "acme", Action: &dialplanv1.DialplanAction{Action: "ROUTE_TO_QUEUE", ActionData: map[string]string{"queue_id": "sales"}}}
+		resp, err := client.UpdateDialplan(ctx, &dialplanv1.UpdateDialplanRequest{Dialplan: updated})
+		if err != nil {
+			t.Fatalf("UpdateDialplan: %v", err)
+		}
+		if resp.Dialplan.GetAction().GetAction() != "ROUTE_TO_QUEUE" {
+			t.Fatalf("yanıt güncel dialplan'ı taşımıyor: %+v", resp.Dialplan)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		other := &dialplanv1.Dialplan{Id: "DP_OTHER", TenantId: "globex", Action: &dialplanv1.DialplanAction{Action: "HANGUP"}}
+		if _, err := client.CreateDialplan(ctx, &dialplanv1.CreateDialplanRequest{Dialplan: other}); err != nil {
+			t.Fatalf("CreateDialplan: %v", err)
+		}
+		resp, err := client.ListDialplans(ctx, &dialplanv1.ListDialplansRequest{TenantId: "acme", Page: 1, PageSize: 10})
+		if err != nil {
+			t.Fatalf("ListDialplans: %v", err)
+		}
+		if resp.TotalCount != 1 || len(resp.Dialplans) != 1 || resp.Dialplans[0].Id != dp.Id {
+			t.Fatalf("beklenmeyen liste: %+v", resp)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		resp, err := client.DeleteDialplan(ctx, &dialplanv1.DeleteDialplanRequest{Id: dp.Id})
+		if err != nil || !resp.Success {
+			t.Fatalf("DeleteDialplan: %+v, %v", resp, err)
+		}
+		_, err = client.GetDialplan(ctx, &dialplanv1.GetDialplanRequest{Id: dp.Id})
+		wantCode(t, err, codes.NotFound, "RECORD_NOT_FOUND")
+	})
+
+	t.Run("DeleteMissing", func(t *testing.T) {
+		_, err := client.DeleteDialplan(ctx, &dialplanv1.DeleteDialplanRequest{Id: dp.Id})
+		wantCode(t, err, codes.NotFound, "RECORD_NOT_FOUND")
+	})
+}
@@ -0,0 +1,85 @@
+// sentiric-dialplan-service/internal/server/grpc/resolve_options.go
+package grpc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sentiric/sentiric-dialplan-service/internal/service/dialplan"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ResolveDialplan davranışını değiştiren istek metadata anahtarları.
+const (
+	// MetadataSimulateAt: RFC3339 zaman damgası; yönlendirme bu anda, yan etkisiz değerlendirilir.
+	MetadataSimulateAt = "x-dialplan-simulate-at"
+	// MetadataExplain: "true" ise karar izi JSON olarak MetadataTrace yanıt başlığında döner ve
+	// /admin/explain?trace_id=... ile sonradan okunabilmesi için saklanır.
+	MetadataExplain = "x-dialplan-explain"
+	// MetadataTrace: Karar izinin (DecisionTrace) döndüğü yanıt başlığı.
+	MetadataTrace = "x-dialplan-trace"
+	// MetadataDryRun: "true" ise hiçbir yazma yapılmaz (health probe, test ve yük testi çağrıları için).
+	MetadataDryRun = "x-dialplan-dry-run"
+	// MetadataSipPrivacy: Gelen INVITE'ın SIP Privacy başlığı; gizli arayan tespitinde kullanılır.
+	MetadataSipPrivacy = "x-sip-privacy"
+	// MetadataCallID: SIP Call-ID; eşzamanlı çağrı kapasitesi bu ID ile sayılır ve ReleaseCall ile bırakılır.
+	MetadataCallID = "x-call-id"
+	// MetadataWouldProvision: Dry-run'da oluşturulacak profilin JSON özetinin döndüğü yanıt başlığı.
+	MetadataWouldProvision = "x-dialplan-would-provision"
+)
+
+// resolveOptionsFromContext: gelen metadata'dan dialplan.ResolveOptions üretir.
+func resolveOptionsFromContext(ctx context.Context) (dialplan.ResolveOptions, error) {
+	var opts dialplan.ResolveOptions
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return opts, nil
+	}
+
+	if raw := firstMetadataValue(md, MetadataSimulateAt); raw != "" {
+		at, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return opts, status.Errorf(codes.InvalidArgument, "%s RFC3339 formatında olmalı: %v", MetadataSimulateAt, err)
+		}
+		opts.SimulateAt = at
+	}
+
+	explain, err := boolMetadata(md, MetadataExplain)
+	if err != nil {
+		return opts, err
+	}
+	if explain {
+		opts.Trace = &dialplan.DecisionTrace{}
+		opts.PersistTrace = true
+	}
+
+	if opts.DryRun, err = boolMetadata(md, MetadataDryRun); err != nil {
+		return opts, err
+	}
+	opts.CallerPrivacy = firstMetadataValue(md, MetadataSipPrivacy)
+	opts.CallID = firstMetadataValue(md, MetadataCallID)
+	return opts, nil
+}
+
+func boolMetadata(md metadata.MD, key string) (bool, error) {
+	raw := firstMetadataValue(md, key)
+	if raw == "" {
+		return false, nil
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, status.Errorf(codes.InvalidArgument, "%s true/false olmalı", key)
+	}
+	return v, nil
+}
+
+func firstMetadataValue(md metadata.MD, key string) string {
+	if vals := md.Get(key); len(vals) > 0 {
+		return strings.TrimSpace(vals[0])
+	}
+	return ""
+}
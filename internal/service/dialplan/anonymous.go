@@ -0,0 +1,74 @@
+// sentiric-dialplan-service/internal/service/dialplan/anonymous.go
+package dialplan
+
+import "strings"
+
+// anonymousHost: RFC 3261 §8.1.1.3 gizli arayan URI'si (sip:anonymous@anonymous.invalid) alan adı.
+const anonymousHost = "anonymous.invalid"
+
+// withheldCallerIDs: Operatörlerin numara yerine gönderdiği bilinen "gizli" işaretleri (küçük harf).
+var withheldCallerIDs = map[string]struct{}{
+	"anonymous":   {},
+	"restricted":  {},
+	"unavailable": {},
+	"unknown":     {},
+	"private":     {},
+	"withheld":    {},
+}
+
+// isWithheldCaller: Arayanın kimliğini gizleyip gizlemediğini; ham From/PAI değeri ve SIP Privacy
+// başlığı üzerinden belirler. Boş numara kontrolü normalizasyon sonrası çağıran tarafta yapılır.
+func isWithheldCaller(caller, privacy string) bool {
+	if privacyWithholdsIdentity(privacy) {
+		return true
+	}
+	user, host := sipUserHost(caller)
+	if host == anonymousHost {
+		return true
+	}
+	_, ok := withheldCallerIDs[user]
+	return ok
+}
+
+// privacyWithholdsIdentity: Privacy başlığında kimliği gizleyen bir değer (id, user, header) var mı?
+// "none" ve "session" kimliği gizlemez.
+func privacyWithholdsIdentity(privacy string) bool {
+	for _, token := range strings.FieldsFunc(strings.ToLower(privacy), func(r rune) bool {
+		return r == ';' || r == ',' || r == ' '
+	}) {
+		switch token {
+		case "id", "user", "header":
+			return true
+		}
+	}
+	return false
+}
+
+// sipUserHost: SIP/TEL URI veya çıplak değerden küçük harfli kullanıcı ve alan adı bölümlerini ayıklar.
+// Görünen ad (display name) dikkate alınmaz.
+func sipUserHost(raw string) (user, host string) {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	if start := strings.Index(s, "<"); start != -1 {
+		s = s[start+1:]
+		if end := strings.Index(s, ">"); end != -1 {
+			s = s[:end]
+		}
+	}
+	for _, scheme := range []string{"sips:", "sip:", "tel:"} {
+		if strings.HasPrefix(s, scheme) {
+			s = s[len(scheme):]
+			break
+		}
+	}
+	user = s
+	if at := strings.Index(s, "@"); at != -1 {
+		user, host = s[:at], s[at+1:]
+		if end := strings.IndexAny(host, ";:?"); end != -1 {
+			host = host[:end]
+		}
+	}
+	if end := strings.IndexAny(user, ";:?"); end != -1 {
+		user = user[:end]
+	}
+	return strings.TrimSpace(user), host
+}
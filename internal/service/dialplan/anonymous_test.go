@@ -0,0 +1,91 @@
+package dialplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+)
+
+func TestIsWithheldCaller(t *testing.T) {
+	tests := []struct {
+		name    string
+		caller  string
+		privacy string
+		want    bool
+	}{
+		{"plain number", "905321112233", "", false},
+		{"sip number", "<sip:905321112233@10.0.0.1>", "", false},
+		{"anonymous rfc 3261", `"Anonymous" <sip:anonymous@anonymous.invalid>`, "", true},
+		{"anonymous host only", "sip:thisis@anonymous.invalid;tag=1", "", true},
+		{"restricted", "sip:restricted@10.0.0.1", "", true},
+		{"unavailable", "Unavailable", "", true},
+		{"tel private", "tel:PRIVATE", "", true},
+		{"user with params", "sip:withheld;user=phone@10.0.0.1", "", true},
+		{"privacy id", "905321112233", "id", true},
+		{"privacy header list", "905321112233", "header; critical", true},
+		{"privacy user upper", "905321112233", "USER", true},
+		{"privacy none", "905321112233", "none", false},
+		{"privacy session", "905321112233", "session", false},
+		{"display name ignored", `"Restricted" <sip:905321112233@10.0.0.1>`, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isWithheldCaller(tt.caller, tt.privacy); got != tt.want {
+				t.Errorf("isWithheldCaller(%q, %q) = %v, want %v", tt.caller, tt.privacy, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveDialplanAnonymous(t *testing.T) {
+	dialplans := map[string]*dialplanv1.Dialplan{
+		"DP1":                         {Id: "DP1", TenantId: "t1", Action: &dialplanv1.DialplanAction{Action: "PROCESS_GUEST_CALL"}},
+		"DP_REJECT":                   {Id: "DP_REJECT", TenantId: "t1", Action: &dialplanv1.DialplanAction{Action: ActionPlayAnnouncement}},
+		DialplanSystemAnonymousReject: {Id: DialplanSystemAnonymousReject, TenantId: "system", Action: &dialplanv1.DialplanAction{Action: ActionPlayAnnouncement}},
+		DialplanSystemCallerBlocked:   {Id: DialplanSystemCallerBlocked, TenantId: "system", Action: &dialplanv1.DialplanAction{Action: ActionPlayAnnouncement}},
+	}
+	tests := []struct {
+		name        string
+		block       bool
+		rejectionID string
+		caller      string
+		privacy     string
+		allowList   bool
+		want        string
+	}{
+		{name: "restricted blocked with route rejection", block: true, rejectionID: "DP_REJECT", caller: "sip:restricted@10.0.0.1", want: "DP_REJECT"},
+		{name: "privacy id blocked with system default", block: true, caller: "905321112233", privacy: "id", want: DialplanSystemAnonymousReject},
+		{name: "empty caller blocked", block: true, caller: "", want: DialplanSystemAnonymousReject},
+		{name: "unavailable allowed when not blocking", caller: "unavailable", want: "DP1"},
+		{name: "visible caller not blocked", block: true, rejectionID: "DP_REJECT", caller: "905321112233", privacy: "none", want: "DP1"},
+		// Gizli arayan izin listesi olan hatta, block_anonymous kapalı olsa bile izinli değildir.
+		{name: "withheld not on allowlist", caller: "restricted", allowList: true, want: DialplanSystemCallerBlocked},
+		// block_anonymous açıksa ret dialplan'ı izin listesinden önce uygulanır.
+		{name: "anonymous rejection precedes allowlist", block: true, rejectionID: "DP_REJECT", caller: "anonymous", allowList: true, want: "DP_REJECT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := &dialplanv1.InboundRoute{
+				PhoneNumber: "902125550000", TenantId: "t1", ActiveDialplanId: toPtr("DP1"),
+				DefaultLanguageCode: "tr", BlockAnonymous: tt.block,
+			}
+			if tt.rejectionID != "" {
+				route.RejectionDialplanId = toPtr(tt.rejectionID)
+			}
+			repo := &resolveRepo{Repository: &callerListRepo{allowList: tt.allowList}, route: route, dialplans: dialplans}
+			svc := NewService(repo, &userClient{}, nil, zerolog.Nop())
+
+			trace := &DecisionTrace{}
+			resp, err := svc.ResolveDialplan(context.Background(), tt.caller, "902125550000",
+				ResolveOptions{DryRun: true, CallerPrivacy: tt.privacy, Trace: trace})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.DialplanId != tt.want {
+				t.Errorf("DialplanId = %q, want %q", resp.DialplanId, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+// sentiric-dialplan-service/internal/service/dialplan/call_capacity.go
+package dialplan
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
+	"github.com/sentiric/sentiric-dialplan-service/internal/metrics"
+)
+
+// Kapasite kapsamları; CallTracker dönüşünde, metrik etiketinde ve karar izinde kullanılır.
+const (
+	CapacityScopeTenant = "tenant"
+	CapacityScopeRoute  = "route"
+)
+
+// DefaultCallTTL: ReleaseCall hiç gelmezse çağrının kapasiteden otomatik düşeceği süre.
+const DefaultCallTTL = 4 * time.Hour
+
+// CallTracker: Aktif çağrı kapasitesi deposu (örn: Redis).
+type CallTracker interface {
+	// Acquire: Kapasite doluysa dolu kapsamı (CapacityScopeTenant / CapacityScopeRoute), aksi halde "" döner.
+	// Limit 0 ise o kapsam sınırsızdır ancak çağrı yine sayılır.
+	Acquire(ctx context.Context, callID, tenantID, routeKey string, tenantLimit, routeLimit int, ttl time.Duration) (string, error)
+	Release(ctx context.Context, callID string) (bool, error)
+	ActiveCalls(ctx context.Context, tenantID string) (int64, error)
+}
+
+// WithCallTracker: Eşzamanlı çağrı limitlerini etkinleştirir. ttl <= 0 ise DefaultCallTTL kullanılır.
+func WithCallTracker(tracker CallTracker, ttl time.Duration) Option {
+	return func(s *Service) {
+		if ttl <= 0 {
+			ttl = DefaultCallTTL
+		}
+		s.callTracker = tracker
+		s.callTTL = ttl
+	}
+}
+
+// callID: Kapasite kaydının anahtarı. Öncelik SIP Call-ID (x-call-id), sonra trace_id'dir.
+// İkisi de yoksa "" döner: ReleaseCall ile bırakılamayacak bir kayıt kapasiteyi TTL boyunca
+// boşuna işgal edeceğinden böyle çağrılar sayılmaz.
+func callID(opts ResolveOptions, traceID string) string {
+	if id := strings.TrimSpace(opts.CallID); id != "" {
+		return id
+	}
+	if traceID != "" && traceID != "unknown" {
+		return traceID
+	}
+	return ""
+}
+
+// overCapacity: Çağrı için tenant ve route kapasitesi ayırır; kapasite doluysa true döner.
+// Kararlı bir çağrı ID'si yoksa kapasite hesabı atlanır.
+// [ARCH-COMPLIANCE] Kapasite deposuna veya tenant limitine erişilemezse çağrı sınırlanmaz (fail-open).
+func (s *Service) overCapacity(ctx context.Context, route *dialplanv1.InboundRoute, id string, trace *DecisionTrace, l zerolog.Logger) bool {
+	if id == "" {
+		trace.record(TraceStepCallCapacity, "skipped", "reason", "no_call_id")
+		l.Debug().Msg("Çağrı ID'si (x-call-id / x-trace-id) yok, kapasite hesabı atlanıyor.")
+		return false
+	}
+
+	tenantLimit, err := s.tenantCallLimit(ctx, route.TenantId)
+	if err != nil {
+		l.Warn().Err(err).
+			Str("event", logger.EventCallCapacityCheckFailed).
+			Str("tenant", route.TenantId).
+			Msg("Tenant çağrı limiti okunamadı, tenant limiti uygulanmıyor.")
+	}
+
+	scope, err := s.callTracker.Acquire(ctx, id, route.TenantId, route.PhoneNumber,
+		int(tenantLimit), int(route.MaxConcurrentCalls), s.callTTL)
+	if err != nil {
+		trace.record(TraceStepCallCapacity, "error", "call_id", id, "error", err.Error())
+		l.Warn().Err(err).
+			Str("event", logger.EventCallCapacityCheckFailed).
+			Msg("Çağrı kapasitesi kontrol edilemedi, çağrı sınırlanmadan devam ediliyor.")
+		return false
+	}
+	if scope == "" {
+		trace.record(TraceStepCallCapacity, "acquired", "call_id", id,
+			"tenant_limit", strconv.Itoa(int(tenantLimit)), "route_limit", strconv.Itoa(int(route.MaxConcurrentCalls)))
+		return false
+	}
+
+	limit := tenantLimit
+	if scope == CapacityScopeRoute {
+		limit = route.MaxConcurrentCalls
+	}
+	metrics.OverCapacityCalls.WithLabelValues(scope).Inc()
+	trace.record(TraceStepCallCapacity, "full", "call_id", id, "scope", scope, "limit", strconv.Itoa(int(limit)))
+	l.Warn().
+		Str("event", logger.EventCallCapacityExceeded).
+		Str("scope", scope).
+		Int32("limit", limit).
+		Msg("📵 Eşzamanlı çağrı kapasitesi dolu, meşgul akışına yönlendiriliyor.")
+	return true
+}
+
+// tenantCallLimit: Tenant'ın eşzamanlı çağrı limiti; tanımlı değilse 0 (sınırsız).
+func (s *Service) tenantCallLimit(ctx context.Context, tenantID string) (int32, error) {
+	limit, err := s.repo.GetTenantCallLimit(ctx, tenantID)
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return limit.MaxConcurrentCalls, nil
+}
+
+// ReleaseCall: Sonlanan çağrının kapasitesini bırakır. Çağrı bulunamazsa (süresi dolmuş,
+// hiç sayılmamış veya zaten bırakılmış) false döner; bu bir hata değildir.
+func (s *Service) ReleaseCall(ctx context.Context, id string) (bool, error) {
+	if strings.TrimSpace(id) == "" {
+		var v violations
+		v.add("call_id", "call_id zorunludur")
+		return false, v.err()
+	}
+	if s.callTracker == nil {
+		return false, nil
+	}
+	return s.callTracker.Release(ctx, id)
+}
+
+// SetTenantCallLimit: Tenant limitini yazar; 0 limiti kaldırır (sınırsız).
+func (s *Service) SetTenantCallLimit(ctx context.Context, req *dialplanv1.SetTenantCallLimitRequest) error {
+	var v violations
+	limit := req.Limit
+	switch {
+	case limit == nil:
+		v.add("limit", "limit zorunludur")
+	case strings.TrimSpace(limit.TenantId) == "":
+		v.add("limit.tenant_id", "tenant_id zorunludur")
+	case limit.MaxConcurrentCalls < 0:
+		v.add("limit.max_concurrent_calls", "negatif olamaz")
+	}
+	if err := v.err(); err != nil {
+		return err
+	}
+	if limit.MaxConcurrentCalls == 0 {
+		_, err := s.repo.DeleteTenantCallLimit(ctx, limit.TenantId)
+		return resourceErr(err, ResourceTenantCallLimit, limit.TenantId)
+	}
+	return resourceErr(s.repo.UpsertTenantCallLimit(ctx, limit), ResourceTenantCallLimit, limit.TenantId)
+}
+
+// GetTenantCallLimit: Tenant limitini ve anlık aktif çağrı sayısını döner. Limit tanımlı değilse 0 (sınırsız).
+func (s *Service) GetTenantCallLimit(ctx context.Context, tenantID string) (*dialplanv1.TenantCallLimit, error) {
+	limit, err := s.tenantCallLimit(ctx, tenantID)
+	if err != nil {
+		return nil, resourceErr(err, ResourceTenantCallLimit, tenantID)
+	}
+	resp := &dialplanv1.TenantCallLimit{TenantId: tenantID, MaxConcurrentCalls: limit}
+	if s.callTracker != nil {
+		active, err := s.callTracker.ActiveCalls(ctx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		resp.ActiveCalls = int32(active)
+	}
+	return resp, nil
+}
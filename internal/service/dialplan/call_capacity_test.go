@@ -0,0 +1,257 @@
+package dialplan
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/cache"
+	"google.golang.org/grpc/metadata"
+)
+
+// capacityRepo: Arayan listesi boş, tenant çağrı limiti sabit olan sahte repository.
+type capacityRepo struct {
+	callerListRepo
+
+	tenantLimit int32
+	limitErr    error
+}
+
+func (r *capacityRepo) GetTenantCallLimit(_ context.Context, tenantID string) (*dialplanv1.TenantCallLimit, error) {
+	if r.limitErr != nil {
+		return nil, r.limitErr
+	}
+	if r.tenantLimit == 0 {
+		return nil, ErrNotFound
+	}
+	return &dialplanv1.TenantCallLimit{TenantId: tenantID, MaxConcurrentCalls: r.tenantLimit}, nil
+}
+
+// memCallTracker: cache.CallTracker'ın betik semantiğini bellekte taklit eder (TTL yok sayılır).
+type memCallTracker struct {
+	mu       sync.Mutex
+	calls    map[string][2]string // callID -> {tenant, route}
+	acquires int
+	err      error
+}
+
+func newMemCallTracker() *memCallTracker {
+	return &memCallTracker{calls: map[string][2]string{}}
+}
+
+func (m *memCallTracker) Acquire(_ context.Context, callID, tenantID, routeKey string, tenantLimit, routeLimit int, _ time.Duration) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.acquires++
+	if m.err != nil {
+		return "", m.err
+	}
+	if _, ok := m.calls[callID]; ok {
+		return "", nil // aynı çağrı ikinci kez sayılmaz
+	}
+	var tenantCount, routeCount int
+	for _, c := range m.calls {
+		if c[0] == tenantID {
+			tenantCount++
+		}
+		if c[1] == routeKey {
+			routeCount++
+		}
+	}
+	switch {
+	case tenantLimit > 0 && tenantCount >= tenantLimit:
+		return CapacityScopeTenant, nil
+	case routeLimit > 0 && routeCount >= routeLimit:
+		return CapacityScopeRoute, nil
+	}
+	m.calls[callID] = [2]string{tenantID, routeKey}
+	return "", nil
+}
+
+func (m *memCallTracker) Release(_ context.Context, callID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.calls[callID]
+	delete(m.calls, callID)
+	return ok, nil
+}
+
+func (m *memCallTracker) ActiveCalls(_ context.Context, tenantID string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int64
+	for _, c := range m.calls {
+		if c[0] == tenantID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func newCapacityService(t *testing.T, repo *capacityRepo, tracker CallTracker, routeLimit int32) *Service {
+	t.Helper()
+	resolve := &resolveRepo{
+		Repository: repo,
+		route: &dialplanv1.InboundRoute{
+			PhoneNumber: "902125550000", TenantId: "t1", ActiveDialplanId: toPtr("DP1"),
+			DefaultLanguageCode: "tr", MaxConcurrentCalls: routeLimit,
+		},
+		dialplans: map[string]*dialplanv1.Dialplan{
+			"DP1":                   {Id: "DP1", TenantId: "t1", Action: &dialplanv1.DialplanAction{Action: "PROCESS_GUEST_CALL"}},
+			DialplanSystemLinesBusy: {Id: DialplanSystemLinesBusy, TenantId: "system", Action: &dialplanv1.DialplanAction{Action: ActionPlayAnnouncement}},
+		},
+	}
+	rdb, _ := newRecordingRedis()
+	return NewService(resolve, &userClient{}, cache.NewUserCache(rdb), zerolog.Nop(), WithCallTracker(tracker, 0))
+}
+
+// resolveCall: callID ile (boşsa x-call-id gönderilmeden) çağrıyı çözer; dialplan ID'si ve call_capacity adımının sonucunu döner.
+func resolveCall(t *testing.T, svc *Service, callID string) (string, string) {
+	t.Helper()
+	trace := &DecisionTrace{}
+	resp, err := svc.ResolveDialplan(context.Background(), "905321112233", "902125550000", ResolveOptions{CallID: callID, Trace: trace})
+	if err != nil {
+		t.Fatal(err)
+	}
+	outcome := ""
+	for _, step := range trace.Steps {
+		if step.Step == TraceStepCallCapacity {
+			outcome = step.Outcome
+		}
+	}
+	return resp.DialplanId, outcome
+}
+
+func TestResolveDialplanCallCapacity(t *testing.T) {
+	type call struct {
+		id          string
+		release     bool // çözmek yerine bırak
+		want        string
+		wantOutcome string
+	}
+	tests := []struct {
+		name        string
+		tenantLimit int32
+		routeLimit  int32
+		calls       []call
+	}{
+		{
+			name:        "tenant limit",
+			tenantLimit: 1,
+			calls: []call{
+				{id: "c1", want: "DP1", wantOutcome: "acquired"},
+				{id: "c1", want: "DP1", wantOutcome: "acquired"}, // tekrar çözülen çağrı ikinci kez sayılmaz
+				{id: "c2", want: DialplanSystemLinesBusy, wantOutcome: "full"},
+				{id: "c1", release: true},
+				{id: "c2", want: "DP1", wantOutcome: "acquired"},
+			},
+		},
+		{
+			name:       "route limit",
+			routeLimit: 2,
+			calls: []call{
+				{id: "c1", want: "DP1", wantOutcome: "acquired"},
+				{id: "c2", want: "DP1", wantOutcome: "acquired"},
+				{id: "c3", want: DialplanSystemLinesBusy, wantOutcome: "full"},
+			},
+		},
+		{
+			name:        "no stable call id is not counted",
+			tenantLimit: 1,
+			calls: []call{
+				{id: "", want: "DP1", wantOutcome: "skipped"},
+				{id: "", want: "DP1", wantOutcome: "skipped"},
+				{id: "c1", want: "DP1", wantOutcome: "acquired"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := newMemCallTracker()
+			svc := newCapacityService(t, &capacityRepo{tenantLimit: tt.tenantLimit}, tracker, tt.routeLimit)
+			for i, c := range tt.calls {
+				if c.release {
+					released, err := svc.ReleaseCall(context.Background(), c.id)
+					if err != nil || !released {
+						t.Fatalf("adım %d: ReleaseCall(%s) = %v, %v", i, c.id, released, err)
+					}
+					continue
+				}
+				got, outcome := resolveCall(t, svc, c.id)
+				if got != c.want || outcome != c.wantOutcome {
+					t.Errorf("adım %d (%s): dialplan = %q, call_capacity = %q; want %q, %q", i, c.id, got, outcome, c.want, c.wantOutcome)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveDialplanCallCapacityFailOpen(t *testing.T) {
+	t.Run("tracker unavailable", func(t *testing.T) {
+		tracker := newMemCallTracker()
+		tracker.err = errors.New("redis down")
+		svc := newCapacityService(t, &capacityRepo{tenantLimit: 1}, tracker, 1)
+		for _, id := range []string{"c1", "c2"} {
+			if got, outcome := resolveCall(t, svc, id); got != "DP1" || outcome != "error" {
+				t.Errorf("%s: dialplan = %q, call_capacity = %q; want DP1, error", id, got, outcome)
+			}
+		}
+	})
+	t.Run("tenant limit unavailable", func(t *testing.T) {
+		tracker := newMemCallTracker()
+		svc := newCapacityService(t, &capacityRepo{limitErr: ErrDatabaseUnavailable}, tracker, 0)
+		for _, id := range []string{"c1", "c2"} {
+			if got, outcome := resolveCall(t, svc, id); got != "DP1" || outcome != "acquired" {
+				t.Errorf("%s: dialplan = %q, call_capacity = %q; want DP1, acquired", id, got, outcome)
+			}
+		}
+		if active, _ := tracker.ActiveCalls(context.Background(), "t1"); active != 2 {
+			t.Errorf("aktif çağrı = %d, want 2", active)
+		}
+	})
+}
+
+func TestCallID(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ResolveOptions
+		traceID string
+		want    string
+	}{
+		{"sip call id", ResolveOptions{CallID: " abc@10.0.0.1 "}, "trace-1", "abc@10.0.0.1"},
+		{"trace id", ResolveOptions{}, "trace-1", "trace-1"},
+		{"unknown trace id", ResolveOptions{}, "unknown", ""},
+		{"none", ResolveOptions{}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := callID(tt.opts, tt.traceID); got != tt.want {
+				t.Errorf("callID = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReleaseCall(t *testing.T) {
+	tracker := newMemCallTracker()
+	svc := newCapacityService(t, &capacityRepo{}, tracker, 0)
+
+	if _, err := svc.ReleaseCall(context.Background(), " "); !reflect.DeepEqual(violationFields(t, err), []string{"call_id"}) {
+		t.Errorf("boş call_id doğrulama hatası vermeli: %v", err)
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-trace-id", "trace-1"))
+	if _, err := svc.ResolveDialplan(ctx, "905321112233", "902125550000", ResolveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if released, err := svc.ReleaseCall(context.Background(), "trace-1"); err != nil || !released {
+		t.Errorf("trace_id ile sayılan çağrı bırakılmalı: %v, %v", released, err)
+	}
+	if released, err := svc.ReleaseCall(context.Background(), "trace-1"); err != nil || released {
+		t.Errorf("ikinci bırakma false dönmeli: %v, %v", released, err)
+	}
+}
@@ -0,0 +1,250 @@
+// sentiric-dialplan-service/internal/service/dialplan/caller_list.go
+package dialplan
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+)
+
+// Arayan listesi türleri. "priority" kayıtları engelleme kararına katılmaz; arayanın kuyruk
+// önceliğini (entry.priority) belirler.
+const (
+	CallerListBlock    = "block"
+	CallerListAllow    = "allow"
+	CallerListPriority = "priority"
+)
+
+// Arayan listesi kararları; karar izinde (caller_list_check) sonuç olarak da kullanılır.
+const (
+	callerListNoMatch     = "no_match"
+	callerListAllowlisted = "allowlisted"
+	callerListBlocked     = "blocked"
+	callerListNotAllowed  = "not_allowlisted"
+)
+
+// maxCallerListImport: Tek bir toplu içe aktarma isteğindeki azami kayıt sayısı.
+const maxCallerListImport = 10000
+
+// CallerListFilter: Arayan listesi kayıtlarını listeleme filtresi.
+type CallerListFilter struct {
+	TenantID         string
+	RoutePhoneNumber *string // nil => tüm kapsamlar
+	ListType         string  // boş => tüm türler
+	NotExpiredAt     *time.Time
+}
+
+// evaluateCallerLists: Arayana uyan kayıtlardan kararı verir.
+// Route kapsamlı eşleşmeler tenant genelindekilere üstün gelir (örn: tenant genelinde engelli bir
+// numara tek bir hatta izinli olabilir). Aynı kapsamda engelleme, izne üstün gelir.
+// Öncelik kayıtları yok sayılır.
+func evaluateCallerLists(matches []*dialplanv1.CallerListEntry) (string, *dialplanv1.CallerListEntry) {
+	var best *dialplanv1.CallerListEntry
+	for _, e := range matches {
+		switch {
+		case e.ListType == CallerListPriority:
+			continue
+		case best == nil:
+			best = e
+		case (e.RoutePhoneNumber != nil) != (best.RoutePhoneNumber != nil):
+			if e.RoutePhoneNumber != nil {
+				best = e
+			}
+		case e.ListType == CallerListBlock:
+			best = e
+		}
+	}
+	switch {
+	case best == nil:
+		return callerListNoMatch, nil
+	case best.ListType == CallerListBlock:
+		return callerListBlocked, best
+	default:
+		return callerListAllowlisted, best
+	}
+}
+
+// callerScreen: Arayan taramasının sonucu. matches, arayana uyan tüm kayıtlardır (öncelik kayıtları dahil);
+// kuyruk önceliği listeyi yeniden sorgulamadan bunları kullanır.
+type callerScreen struct {
+	verdict string
+	entry   *dialplanv1.CallerListEntry
+	matches []*dialplanv1.CallerListEntry
+}
+
+// screenCaller: Arayanı route ve tenant listelerine göre değerlendirir. Uyan kayıt yoksa ve kapsamda
+// geçerli bir izin listesi varsa arayan izin listesinde değildir (not_allowlisted). Numarası gizli
+// arayan ("" / "anonymous") hiçbir kayda uyamaz; izin listesi olan hatta bu yüzden izinli sayılmaz.
+func (s *Service) screenCaller(ctx context.Context, route *dialplanv1.InboundRoute, caller string, at time.Time) (callerScreen, error) {
+	var screen callerScreen
+	if caller != "" && caller != "anonymous" {
+		matches, err := s.repo.MatchCallerList(ctx, route.TenantId, route.PhoneNumber, caller, at)
+		if err != nil {
+			return callerScreen{}, err
+		}
+		screen.matches = matches
+		if screen.verdict, screen.entry = evaluateCallerLists(matches); screen.verdict != callerListNoMatch {
+			return screen, nil
+		}
+	}
+	allowList, err := s.repo.HasActiveAllowList(ctx, route.TenantId, route.PhoneNumber, at)
+	if err != nil {
+		return callerScreen{}, err
+	}
+	screen.verdict = callerListNoMatch
+	if allowList {
+		screen.verdict = callerListNotAllowed
+	}
+	return screen, nil
+}
+
+// validateCallerListEntry: Kaydı doğrular ve normalize eder. Numara, route anahtarlarıyla aynı kurallarla
+// tenant'ın ülke planına göre normalize edilir; prefix kayıtları sonda '*' ile yazılır (örn: "90850*").
+func validateCallerListEntry(e *dialplanv1.CallerListEntry, plan NumberingPlan, now time.Time) error {
+	var v violations
+	if e == nil {
+		v.add("entry", "entry zorunludur")
+		return v.err()
+	}
+	if strings.TrimSpace(e.TenantId) == "" {
+		v.add("tenant_id", "tenant_id zorunludur")
+	}
+	switch e.ListType {
+	case CallerListBlock, CallerListAllow:
+		if e.Priority != "" {
+			v.add("priority", "yalnızca %s listelerinde kullanılabilir", CallerListPriority)
+		}
+	case CallerListPriority:
+		if strings.TrimSpace(e.Priority) == "" {
+			v.add("priority", "%s listelerinde priority zorunludur", CallerListPriority)
+		} else {
+			e.Priority = validatePriority("priority", e.Priority, &v)
+		}
+	default:
+		v.add("list_type", "geçersiz liste türü %q (beklenen: %s, %s, %s)", e.ListType, CallerListBlock, CallerListAllow, CallerListPriority)
+	}
+	p, err := ParseRoutePattern(e.Number, plan)
+	switch {
+	case err != nil:
+		v.add("number", "geçersiz numara veya prefix %q (beklenen: numara ya da sonda '*' ile E.164 prefix)", e.Number)
+	case p.Kind == RouteMatchRange:
+		v.add("number", "numara aralıkları desteklenmiyor; exact veya prefix ('*') kullanın")
+	default:
+		e.Number = p.String()
+	}
+	if e.ExpiresAt != "" {
+		expires, err := time.Parse(time.RFC3339, e.ExpiresAt)
+		switch {
+		case err != nil:
+			v.add("expires_at", "geçersiz zaman %q (beklenen RFC3339)", e.ExpiresAt)
+		case !expires.After(now):
+			v.add("expires_at", "expires_at geçmişte olamaz")
+		default:
+			e.ExpiresAt = expires.UTC().Format(time.RFC3339)
+		}
+	}
+	e.Reason = strings.TrimSpace(e.Reason)
+	return v.err()
+}
+
+// prepareCallerListEntry: Kaydı doğrular; route kapsamlıysa route'un varlığını ve tenant'ını kontrol edip
+// route anahtarını kanonik forma getirir.
+func (s *Service) prepareCallerListEntry(ctx context.Context, e *dialplanv1.CallerListEntry) error {
+	if e != nil && e.RoutePhoneNumber != nil && strings.TrimSpace(*e.RoutePhoneNumber) == "" {
+		e.RoutePhoneNumber = nil
+	}
+	var plan NumberingPlan
+	if e != nil {
+		plan = s.numbering.ForTenant(e.TenantId)
+	}
+	if err := validateCallerListEntry(e, plan, s.clock.Now()); err != nil {
+		return err
+	}
+	if e.RoutePhoneNumber == nil {
+		return nil
+	}
+	route, err := s.GetInboundRoute(ctx, *e.RoutePhoneNumber)
+	if err != nil {
+		return err
+	}
+	if route.TenantId != e.TenantId {
+		var v violations
+		v.add("route_phone_number", "route %q başka bir tenant'a ait", route.PhoneNumber)
+		return v.err()
+	}
+	e.RoutePhoneNumber = &route.PhoneNumber
+	return nil
+}
+
+func (s *Service) CreateCallerListEntry(ctx context.Context, req *dialplanv1.CreateCallerListEntryRequest) error {
+	if err := s.prepareCallerListEntry(ctx, req.Entry); err != nil {
+		return err
+	}
+	return resourceErr(s.repo.CreateCallerListEntry(ctx, req.Entry), ResourceCallerList, req.Entry.Number)
+}
+
+func (s *Service) GetCallerListEntry(ctx context.Context, id string) (*dialplanv1.CallerListEntry, error) {
+	e, err := s.repo.GetCallerListEntry(ctx, id)
+	return e, resourceErr(err, ResourceCallerList, id)
+}
+
+func (s *Service) UpdateCallerListEntry(ctx context.Context, req *dialplanv1.UpdateCallerListEntryRequest) error {
+	if err := s.prepareCallerListEntry(ctx, req.Entry); err != nil {
+		return err
+	}
+	rows, err := s.repo.UpdateCallerListEntry(ctx, req.Entry)
+	return affected(rows, err, ResourceCallerList, req.Entry.Id)
+}
+
+func (s *Service) DeleteCallerListEntry(ctx context.Context, id string) error {
+	rows, err := s.repo.DeleteCallerListEntry(ctx, id)
+	return affected(rows, err, ResourceCallerList, id)
+}
+
+func (s *Service) ListCallerListEntries(ctx context.Context, req *dialplanv1.ListCallerListEntriesRequest) (*dialplanv1.ListCallerListEntriesResponse, error) {
+	f := CallerListFilter{TenantID: req.TenantId, RoutePhoneNumber: req.RoutePhoneNumber, ListType: req.ListType}
+	if !req.IncludeExpired {
+		now := s.clock.Now()
+		f.NotExpiredAt = &now
+	}
+	list, err := s.repo.ListCallerListEntries(ctx, f, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	count, _ := s.repo.CountCallerListEntries(ctx, f)
+	return &dialplanv1.ListCallerListEntriesResponse{Entries: list, TotalCount: count}, nil
+}
+
+// ImportCallerListEntries: Kayıtları toplu ekler. Geçersiz kayıtlar atlanıp hata listesinde döner;
+// mevcut bir kaydın aynısı (aynı kapsam, tür ve numara) mükerrer sayılır ve atlanır.
+func (s *Service) ImportCallerListEntries(ctx context.Context, req *dialplanv1.ImportCallerListEntriesRequest) (*dialplanv1.ImportCallerListEntriesResponse, error) {
+	if len(req.Entries) > maxCallerListImport {
+		var v violations
+		v.add("entries", "tek istekte en fazla %d kayıt içe aktarılabilir", maxCallerListImport)
+		return nil, v.err()
+	}
+
+	resp := &dialplanv1.ImportCallerListEntriesResponse{}
+	valid := make([]*dialplanv1.CallerListEntry, 0, len(req.Entries))
+	for i, e := range req.Entries {
+		if err := s.prepareCallerListEntry(ctx, e); err != nil {
+			resp.Errors = append(resp.Errors, fmt.Sprintf("entries[%d]: %v", i, err))
+			continue
+		}
+		valid = append(valid, e)
+	}
+	if len(valid) == 0 {
+		return resp, nil
+	}
+
+	inserted, err := s.repo.ImportCallerListEntries(ctx, valid)
+	if err != nil {
+		return nil, resourceErr(err, ResourceCallerList, "import")
+	}
+	resp.Imported = int32(inserted)
+	resp.Duplicates = int32(len(valid)) - resp.Imported
+	return resp, nil
+}
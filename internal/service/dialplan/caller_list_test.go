@@ -0,0 +1,108 @@
+package dialplan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+)
+
+// callerListRepo: Arayan listesi sorgularını karşılayan sahte repository.
+type callerListRepo struct {
+	Repository
+
+	entries     []*dialplanv1.CallerListEntry // numarası arayanla aynı olan kayıtlar eşleşir
+	allowList   bool
+	matchCalls  int
+	allowChecks int
+}
+
+func (r *callerListRepo) MatchCallerList(_ context.Context, _, _, caller string, _ time.Time) ([]*dialplanv1.CallerListEntry, error) {
+	r.matchCalls++
+	var out []*dialplanv1.CallerListEntry
+	for _, e := range r.entries {
+		if e.Number == caller {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (r *callerListRepo) HasActiveAllowList(context.Context, string, string, time.Time) (bool, error) {
+	r.allowChecks++
+	return r.allowList, nil
+}
+
+func TestScreenCaller(t *testing.T) {
+	route := &dialplanv1.InboundRoute{PhoneNumber: "902125550000", TenantId: "t1"}
+	routeKey := route.PhoneNumber
+
+	tests := []struct {
+		name       string
+		caller     string
+		entries    []*dialplanv1.CallerListEntry
+		allowList  bool
+		want       string
+		wantLookup bool
+	}{
+		{name: "no lists", caller: "905321234567", want: callerListNoMatch, wantLookup: true},
+		{name: "blocked", caller: "905321234567", want: callerListBlocked, wantLookup: true,
+			entries: []*dialplanv1.CallerListEntry{{Id: "b", ListType: CallerListBlock, Number: "905321234567"}}},
+		{name: "allowlisted", caller: "905321234567", allowList: true, want: callerListAllowlisted, wantLookup: true,
+			entries: []*dialplanv1.CallerListEntry{{Id: "a", ListType: CallerListAllow, Number: "905321234567"}}},
+		{name: "route allow beats tenant block", caller: "905321234567", want: callerListAllowlisted, wantLookup: true,
+			entries: []*dialplanv1.CallerListEntry{
+				{Id: "b", ListType: CallerListBlock, Number: "905321234567"},
+				{Id: "a", ListType: CallerListAllow, Number: "905321234567", RoutePhoneNumber: &routeKey},
+			}},
+		{name: "not on allowlist", caller: "905321234567", allowList: true, want: callerListNotAllowed, wantLookup: true},
+		{name: "withheld with allowlist", caller: "anonymous", allowList: true, want: callerListNotAllowed},
+		{name: "empty caller with allowlist", caller: "", allowList: true, want: callerListNotAllowed},
+		{name: "withheld without allowlist", caller: "anonymous", want: callerListNoMatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &callerListRepo{entries: tt.entries, allowList: tt.allowList}
+			svc := NewService(repo, nil, nil, zerolog.Nop())
+			screen, err := svc.screenCaller(context.Background(), route, tt.caller, time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if screen.verdict != tt.want {
+				t.Errorf("verdict = %q, want %q", screen.verdict, tt.want)
+			}
+			if got := repo.matchCalls > 0; got != tt.wantLookup {
+				t.Errorf("MatchCallerList çağrıldı = %v, want %v", got, tt.wantLookup)
+			}
+		})
+	}
+}
+
+func TestCallerPriorityUsesScreenedEntries(t *testing.T) {
+	route := &dialplanv1.InboundRoute{PhoneNumber: "902125550000", TenantId: "t1", QueuePriority: PriorityHigh}
+	action := &dialplanv1.DialplanAction{Action: ActionRouteToQueue, ActionData: map[string]string{ActionDataQueueID: "q1"}}
+
+	repo := &callerListRepo{entries: []*dialplanv1.CallerListEntry{
+		{Id: "p", ListType: CallerListPriority, Number: "905321234567", Priority: PriorityVIP},
+	}}
+	svc := NewService(repo, nil, nil, zerolog.Nop())
+	screen, err := svc.screenCaller(context.Background(), route, "905321234567", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := callerPriority(action, route, screen.matches, nil, false)
+	if d.class != PriorityVIP || d.source != prioritySourceCallerList {
+		t.Errorf("priority = %s/%s, want %s/%s", d.class, d.source, PriorityVIP, prioritySourceCallerList)
+	}
+	if repo.matchCalls != 1 {
+		t.Errorf("MatchCallerList %d kez çağrıldı, want 1", repo.matchCalls)
+	}
+
+	// Tarama başarısızsa liste yok sayılır; route önceliği geçerlidir.
+	if d := callerPriority(action, route, nil, nil, false); d.class != PriorityHigh || d.source != prioritySourceRoute {
+		t.Errorf("priority = %s/%s, want %s/%s", d.class, d.source, PriorityHigh, prioritySourceRoute)
+	}
+}
@@ -3,6 +3,8 @@ package dialplan
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,77 +12,137 @@ import (
 	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
 )
 
+const (
+	minutesPerDay = 24 * 60
+	holidayLayout = "2006-01-02"
+)
+
+// Takvim durumları. Aralıklar "state" ile etiketlenebilir; etiketsiz aralık "open" sayılır.
+// Hiçbir aralığa uymayan anlar için kapalı durum sırasıyla holiday, weekend (gün tanımsız) veya after_hours'tur.
+const (
+	StateOpen       = "open"
+	StateLunchBreak = "lunch_break"
+	StateWeekend    = "weekend"
+	StateHoliday    = "holiday"
+	StateAfterHours = "after_hours"
+)
+
+// weekdayKeys: time.Weekday sırasına göre JSON gün anahtarları.
+var weekdayKeys = [7]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
 // ScheduleDefinition, veritabanındaki JSONB yapısını karşılar.
 type ScheduleDefinition struct {
-	Timezone string                 `json:"timezone"`
-	Days     map[string][]TimeRange `json:"days"`     // "mon": [{"start": "09:00", "end": "18:00"}]
-	Holidays []string               `json:"holidays"` //["2026-01-01"]
+	Timezone     string                 `json:"timezone"`
+	Days         map[string][]TimeRange `json:"days"`                    // "mon": [{"start": "09:00", "end": "18:00"}]
+	Holidays     []string               `json:"holidays"`                //["2026-01-01"] (tüm gün kapalı)
+	HolidayRules []HolidayRule          `json:"holiday_rules,omitempty"` // yıllık, N. hafta günü, yarım gün
+	Calendars    []string               `json:"calendars,omitempty"`     // holiday_calendars.id referansları
 }
 
+// TimeRange: Bir gün içindeki açık zaman aralığı.
+// End < Start ise aralık gece yarısını aşar ve başladığı güne sayılır ("fri" 22:00-06:00 => Cuma 22:00 - Cumartesi 06:00).
+// End "24:00" gün sonunu, AllDay ise günün tamamını ifade eder.
+// State boş değilse aralık o duruma aittir (örn: 12:00-13:00 "lunch_break"); örtüşen aralıklarda etiketli olan kazanır.
 type TimeRange struct {
-	Start string `json:"start"` // HH:MM
-	End   string `json:"end"`   // HH:MM
+	Start  string `json:"start"` // HH:MM
+	End    string `json:"end"`   // HH:MM veya "24:00"
+	AllDay bool   `json:"all_day,omitempty"`
+	State  string `json:"state,omitempty"`
+}
+
+// state: Aralığın durumu (etiketsizse "open").
+func (r TimeRange) state() string {
+	if r.State == "" {
+		return StateOpen
+	}
+	return r.State
+}
+
+// minutes: Aralığı gün başından itibaren dakika cinsine çevirir.
+// Gece yarısını aşan aralıklarda end > 1440 olur.
+func (r TimeRange) minutes() (start, end int, err error) {
+	if r.AllDay {
+		return 0, minutesPerDay, nil
+	}
+	start, err = parseClock(r.Start, false)
+	if err != nil {
+		return 0, 0, fmt.Errorf("start: %w", err)
+	}
+	end, err = parseClock(r.End, true)
+	if err != nil {
+		return 0, 0, fmt.Errorf("end: %w", err)
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("start ve end aynı olamaz (%s); tüm gün için \"all_day\": true kullanın", r.Start)
+	}
+	if end < start {
+		end += minutesPerDay
+	}
+	return start, end, nil
 }
 
-// IsWorkingHour: Verilen zamanın çalışma saatleri içinde olup olmadığını kontrol eder.
+// IsWorkingHour: Verilen zamanın (now) çalışma saatleri içinde olup olmadığını kontrol eder.
+// Yalnızca "open" durumu açık sayılır; geriye dönük ikili (açık/kapalı) karar içindir.
+func IsWorkingHour(scheduleJson string, now time.Time, l zerolog.Logger, calendarRules ...HolidayRule) bool {
+	return EvaluateScheduleState(scheduleJson, now, l, calendarRules...) == StateOpen
+}
+
+// EvaluateScheduleState: Verilen zamandaki takvim durumunu döner (open, lunch_break, weekend, holiday, after_hours...).
+// now, takvimin timezone'una çevrilerek değerlendirilir; servis bunu Clock üzerinden sağlar.
+// calendarRules, takvimin referans verdiği paylaşımlı tatil takvimlerinden çözülmüş kurallardır.
 // [ARCH-COMPLIANCE]: Trace ID barındıran Context Logger parametre olarak geçirildi.
-func IsWorkingHour(scheduleJson string, l zerolog.Logger) bool {
+func EvaluateScheduleState(scheduleJson string, now time.Time, l zerolog.Logger, calendarRules ...HolidayRule) string {
 	if scheduleJson == "" {
-		return true // Takvim yoksa her zaman açık varsay
+		return StateOpen // Takvim yoksa her zaman açık varsay
 	}
 
-	var sched ScheduleDefinition
-	if err := json.Unmarshal([]byte(scheduleJson), &sched); err != nil {
+	sched, err := decodeSchedule(scheduleJson)
+	if err != nil {
 		l.Error().Err(err).Str("event", logger.EventScheduleParseError).Msg("Schedule JSON parse hatası, varsayılan: AÇIK")
-		return true
+		return StateOpen
 	}
 
 	// 1. Timezone Ayarla
-	loc, err := time.LoadLocation(sched.Timezone)
+	loc, err := sched.location()
 	if err != nil {
 		l.Warn().Str("event", logger.EventScheduleParseError).Str("tz", sched.Timezone).Msg("Geçersiz Timezone, UTC kullanılıyor.")
 		loc = time.UTC
 	}
 
-	now := time.Now().In(loc)
-
-	// 2. Tatil Kontrolü (YYYY-MM-DD)
-	todayStr := now.Format("2006-01-02")
-	for _, holiday := range sched.Holidays {
-		if holiday == todayStr {
-			return false // Tatil günü -> KAPALI
-		}
-	}
-
-	// 3. Gün Kontrolü (mon, tue...)
-	weekday := strings.ToLower(now.Weekday().String()[:3]) // "mon", "tue"
-	ranges, ok := sched.Days[weekday]
-	if !ok || len(ranges) == 0 {
-		return false // O gün için tanım yoksa -> KAPALI
-	}
-
-	// 4. Saat Aralığı Kontrolü
-	currentMinutes := now.Hour()*60 + now.Minute()
-
-	for _, rng := range ranges {
-		startMin := parseTimeHeader(rng.Start)
-		endMin := parseTimeHeader(rng.End)
+	return compileSchedule(sched, loc, calendarRules, nil, l).stateAt(now.In(loc))
+}
 
-		if currentMinutes >= startMin && currentMinutes < endMin {
-			return true // Aralıklardan birine uyuyorsa -> AÇIK
-		}
+// decodeSchedule: Takvim JSON'unu çözer.
+func decodeSchedule(scheduleJson string) (*ScheduleDefinition, error) {
+	var sched ScheduleDefinition
+	if err := json.Unmarshal([]byte(scheduleJson), &sched); err != nil {
+		return nil, err
 	}
+	return &sched, nil
+}
 
-	return false // Hiçbir aralığa uymadı -> KAPALI
+// location: Takvimin IANA timezone'unu yükler.
+func (sched *ScheduleDefinition) location() (*time.Location, error) {
+	return time.LoadLocation(sched.Timezone)
 }
 
-// "09:30" formatını günün dakikasına çevirir (9*60 + 30 = 570)
-func parseTimeHeader(hhmm string) int {
-	parts := strings.Split(hhmm, ":")
-	if len(parts) != 2 {
-		return 0
+// parseClock: "09:30" formatını günün dakikasına çevirir (9*60 + 30 = 570).
+// allowEndOfDay true ise "24:00" (gün sonu = 1440) kabul edilir.
+func parseClock(hhmm string, allowEndOfDay bool) (int, error) {
+	h, m, ok := strings.Cut(strings.TrimSpace(hhmm), ":")
+	if !ok || len(h) != 2 || len(m) != 2 || !isDigits(h) || !isDigits(m) {
+		return 0, fmt.Errorf("geçersiz saat %q (beklenen HH:MM)", hhmm)
+	}
+	hour, _ := strconv.Atoi(h)
+	minute, _ := strconv.Atoi(m)
+	if minute > 59 {
+		return 0, fmt.Errorf("geçersiz saat %q (beklenen HH:MM)", hhmm)
+	}
+	if hour == 24 && minute == 0 && allowEndOfDay {
+		return minutesPerDay, nil
+	}
+	if hour > 23 {
+		return 0, fmt.Errorf("geçersiz saat %q (saat 00-23 aralığında olmalı)", hhmm)
 	}
-	h, _ := time.Parse("15", parts[0]) // Basit parsing trick
-	m, _ := time.Parse("04", parts[1])
-	return h.Hour()*60 + m.Minute()
+	return hour*60 + minute, nil
 }
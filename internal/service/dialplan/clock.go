@@ -0,0 +1,67 @@
+// sentiric-dialplan-service/internal/service/dialplan/clock.go
+package dialplan
+
+import "time"
+
+// Clock: Zaman kaynağı soyutlaması. Takvim kararlarının deterministik
+// değerlendirilmesi ve "T anında ne olur" simülasyonu için enjekte edilir.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// WithClock: Servisin kullandığı zaman kaynağını değiştirir.
+func WithClock(c Clock) Option {
+	return func(s *Service) {
+		if c != nil {
+			s.clock = c
+		}
+	}
+}
+
+// ResolveOptions: ResolveDialplan davranışını istek bazında değiştiren seçenekler.
+type ResolveOptions struct {
+	// SimulateAt: Sıfır değilse yönlendirme bu anda değerlendirilir (simülasyon modu).
+	// Simülasyon yan etkisizdir: kullanıcı otomatik oluşturulmaz, cache'e yazılmaz.
+	SimulateAt time.Time
+
+	// DryRun: Tüm yönlendirme mantığı çalışır ancak hiçbir yazma yapılmaz
+	// (kullanıcı oluşturma, cache, karar izi saklama). Oluşturulacak profil Trace.WouldProvision'a yazılır.
+	DryRun bool
+
+	// Trace: nil değilse karar adımları bu ize kaydedilir (explain).
+	Trace *DecisionTrace
+
+	// PersistTrace: İz, trace_id ile sonradan okunabilmesi için TraceStore'a yazılır. İz arayan numarası
+	// gibi kişisel veri içerdiğinden varsayılan kapalıdır; kapalıyken yalnızca örneklenen çağrılar saklanır.
+	PersistTrace bool
+
+	// CallerPrivacy: Gelen INVITE'ın SIP Privacy başlığı (RFC 3323/3325, örn: "id", "user;header").
+	// Kimliği gizleyen bir değer varsa arayan numarası görünse bile gizli arayan sayılır.
+	CallerPrivacy string
+
+	// CallID: SIP Call-ID; eşzamanlı çağrı kapasitesi bu ID ile sayılır ve ReleaseCall'da aynı değer kullanılır.
+	// Boşsa trace_id kullanılır.
+	CallID string
+}
+
+// Simulated: İstek bir simülasyon mu?
+func (o ResolveOptions) Simulated() bool {
+	return !o.SimulateAt.IsZero()
+}
+
+// sideEffectFree: Yazma işlemleri (auto-provisioning, cache) atlanmalı mı?
+func (o ResolveOptions) sideEffectFree() bool {
+	return o.Simulated() || o.DryRun
+}
+
+// evaluationTime: Takvim kararlarının verileceği an.
+func (s *Service) evaluationTime(opts ResolveOptions) time.Time {
+	if opts.Simulated() {
+		return opts.SimulateAt
+	}
+	return s.clock.Now()
+}
@@ -1,7 +1,10 @@
 // sentiric-dialplan-service/internal/service/dialplan/errors.go
 package dialplan
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrNotFound: İstenen kayıt veritabanında bulunamadı.
@@ -13,6 +16,59 @@ var (
 	// ErrDatabase: Beklenmeyen veritabanı hatası.
 	ErrDatabase = errors.New("database internal error")
 
+	// ErrDatabaseUnavailable: Veritabanına ulaşılamıyor (bağlantı hatası, zaman aşımı); istek yeniden denenebilir.
+	ErrDatabaseUnavailable = errors.New("database unavailable")
+
+	// ErrConstraintViolation: Kayıt, benzersizlik ve referans dışındaki bir veritabanı kısıtına takıldı (NOT NULL, CHECK).
+	ErrConstraintViolation = errors.New("database constraint violation")
+
 	// ErrTableMissing: Kritik altyapı hatası (Tablo yok).
 	ErrTableMissing = errors.New("critical: database table missing")
+
+	// ErrInvalidArgument: İstek verisi iş kurallarına uymuyor.
+	ErrInvalidArgument = errors.New("invalid argument")
+
+	// ErrReferenceNotFound: Yazılan kayıt, var olmayan bir kayda başvuruyor (örn: tanımsız dialplan ID).
+	ErrReferenceNotFound = errors.New("referenced record not found")
+
+	// ErrInUse: Kayıt başka kayıtlar tarafından referans edildiği için silinemez.
+	ErrInUse = errors.New("record is still referenced")
+
+	// ErrNotConfigured: İstenen özellik için gerekli bağımlılık (örn: Redis deposu) bu serviste yapılandırılmamış.
+	ErrNotConfigured = errors.New("feature not configured")
 )
+
+// ResourceError: Bir sentinel hatayı, hatanın ait olduğu kaynak bilgisiyle zenginleştirir.
+// gRPC katmanı bu bilgiyi errdetails.ResourceInfo olarak istemciye taşır.
+type ResourceError struct {
+	Err          error
+	ResourceType string
+	ResourceName string
+}
+
+func (e *ResourceError) Error() string {
+	return fmt.Sprintf("%s %q: %v", e.ResourceType, e.ResourceName, e.Err)
+}
+
+func (e *ResourceError) Unwrap() error {
+	return e.Err
+}
+
+// resourceErr: err nil değilse kaynak bilgisiyle sarmalar.
+func resourceErr(err error, resourceType, name string) error {
+	if err == nil {
+		return nil
+	}
+	return &ResourceError{Err: err, ResourceType: resourceType, ResourceName: name}
+}
+
+// affected: Update/Delete sonucunda hiçbir satır etkilenmediyse ErrNotFound döner.
+func affected(rows int64, err error, resourceType, name string) error {
+	if err != nil {
+		return resourceErr(err, resourceType, name)
+	}
+	if rows == 0 {
+		return resourceErr(ErrNotFound, resourceType, name)
+	}
+	return nil
+}
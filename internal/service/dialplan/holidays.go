@@ -0,0 +1,99 @@
+// sentiric-dialplan-service/internal/service/dialplan/holidays.go
+package dialplan
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const annualHolidayLayout = "01-02"
+
+// HolidayRule: Tek bir tatil kuralı. Date, Annual ve NthWeekday alanlarından tam olarak biri dolu olmalıdır.
+// Hours boşsa gün tamamen kapalıdır; doluysa o gün yalnızca bu saatlerde açıktır (örn: 31 Aralık 09:00-13:00).
+type HolidayRule struct {
+	Name       string      `json:"name,omitempty"`
+	Date       string      `json:"date,omitempty"`        // "2026-10-29" (tek seferlik)
+	Annual     string      `json:"annual,omitempty"`      // "10-29" (her yıl)
+	NthWeekday *NthWeekday `json:"nth_weekday,omitempty"` // {"month": 5, "weekday": "mon", "n": -1}
+	Hours      []TimeRange `json:"hours,omitempty"`
+}
+
+// NthWeekday: Ayın N. haftanın günü (örn: Kasım'ın 4. Perşembesi). N = -1 ayın son haftanın gününü ifade eder.
+type NthWeekday struct {
+	Month   int    `json:"month"`   // 1-12
+	Weekday string `json:"weekday"` // mon..sun
+	N       int    `json:"n"`       // 1..5 veya -1
+}
+
+// HolidayCalendarDefinition: holiday_calendars.calendar_data JSONB yapısı.
+// Birden fazla takvim aynı tatil takvimine "calendars" alanından referans verebilir.
+type HolidayCalendarDefinition struct {
+	Rules []HolidayRule `json:"rules"`
+}
+
+// dayKey: Kuralın gün seçicisini karşılaştırılabilir bir anahtar olarak döner (ad ve saatler dahil değildir).
+func (r HolidayRule) dayKey() string {
+	switch {
+	case r.NthWeekday != nil:
+		return fmt.Sprintf("nth:%d:%s:%d", r.NthWeekday.Month, r.NthWeekday.Weekday, r.NthWeekday.N)
+	case r.Annual != "":
+		return "annual:" + r.Annual
+	}
+	return "date:" + r.Date
+}
+
+func (n NthWeekday) matches(t time.Time) bool {
+	if int(t.Month()) != n.Month || weekdayKeys[t.Weekday()] != n.Weekday {
+		return false
+	}
+	if n.N == -1 {
+		// Bir hafta sonrası sonraki aya düşüyorsa bu, ayın son haftanın günüdür.
+		return t.AddDate(0, 0, 7).Month() != t.Month()
+	}
+	return (t.Day()-1)/7+1 == n.N
+}
+
+// validate: Kuralı doğrular; field, ihlallerin raporlanacağı JSON yoludur.
+func (r HolidayRule) validate(field string, v *violations) {
+	selectors := 0
+	if r.Date != "" {
+		selectors++
+		if _, err := time.Parse(holidayLayout, r.Date); err != nil {
+			v.add(field+".date", "geçersiz tarih %q (beklenen YYYY-MM-DD)", r.Date)
+		}
+	}
+	if r.Annual != "" {
+		selectors++
+		// 29 Şubat'ın geçerli sayılması için artık yıl üzerinden doğrulanır.
+		if _, err := time.Parse(holidayLayout, "2000-"+r.Annual); err != nil {
+			v.add(field+".annual", "geçersiz yıllık tarih %q (beklenen MM-DD)", r.Annual)
+		}
+	}
+	if r.NthWeekday != nil {
+		selectors++
+		n := r.NthWeekday
+		if n.Month < 1 || n.Month > 12 {
+			v.add(field+".nth_weekday.month", "ay 1-12 aralığında olmalı")
+		}
+		if !isWeekdayKey(n.Weekday) {
+			v.add(field+".nth_weekday.weekday", "geçersiz gün anahtarı %q (beklenen: %s)", n.Weekday, strings.Join(weekdayKeys[:], ","))
+		}
+		if n.N != -1 && (n.N < 1 || n.N > 5) {
+			v.add(field+".nth_weekday.n", "n 1-5 aralığında veya -1 (son) olmalı")
+		}
+	}
+	if selectors != 1 {
+		v.add(field, "date, annual ve nth_weekday alanlarından tam olarak biri belirtilmelidir")
+	}
+	validateRanges(field+".hours", r.Hours, v)
+}
+
+// Validate: Tatil takvimi tanımını doğrular.
+func (c *HolidayCalendarDefinition) Validate() []FieldViolation {
+	var v violations
+	for i, rule := range c.Rules {
+		rule.validate(fmt.Sprintf("calendar_json.rules[%d]", i), &v)
+	}
+	return v
+}
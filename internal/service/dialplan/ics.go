@@ -0,0 +1,326 @@
+// sentiric-dialplan-service/internal/service/dialplan/ics.go
+package dialplan
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// RFC 5545 (iCalendar) için ağ erişimi gerektirmeyen, yalnızca verilen byte'lar üzerinde çalışan
+// minimal okuyucu/yazıcı. Yalnızca VEVENT bileşenleri ve takvim ithalatı için gereken özellikler desteklenir.
+
+const (
+	icsDateLayout     = "20060102"
+	icsDateTimeLayout = "20060102T150405"
+	icsUTCLayout      = "20060102T150405Z"
+	icsMaxLineOctets  = 75
+	icsProductID      = "-//Sentiric//Dialplan Service//TR"
+	icsUIDDomain      = "dialplan.sentiric.cloud"
+)
+
+// icsWeekdays: RRULE BYDAY gün kısaltmaları -> JSON gün anahtarları.
+var icsWeekdays = map[string]string{
+	"SU": "sun", "MO": "mon", "TU": "tue", "WE": "wed", "TH": "thu", "FR": "fri", "SA": "sat",
+}
+
+var icsDurationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// icsProperty: "NAME;PARAM=V:VALUE" satırı.
+type icsProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// icsEvent: Bir VEVENT'in ithalat için gereken alanları. All-day olaylarda End hariçtir (exclusive).
+type icsEvent struct {
+	UID     string
+	Summary string
+	Start   time.Time
+	End     time.Time
+	AllDay  bool
+	RRule   map[string]string
+}
+
+// parseICS: ICS belgesindeki VEVENT'leri okur. Okunamayan olaylar atlanır ve uyarı olarak döner.
+// TZID'siz (floating) zamanlar defaultLoc'ta yorumlanır.
+func parseICS(data []byte, defaultLoc *time.Location) ([]icsEvent, []string, error) {
+	lines := unfoldICS(data)
+	if len(lines) == 0 || !strings.EqualFold(strings.TrimSpace(lines[0]), "BEGIN:VCALENDAR") {
+		return nil, nil, fmt.Errorf("geçersiz ICS: BEGIN:VCALENDAR ile başlamıyor")
+	}
+
+	var (
+		events   []icsEvent
+		warnings []string
+		current  []icsProperty
+		inEvent  bool
+		depth    int // VEVENT içindeki alt bileşenler (örn: VALARM)
+		index    int
+	)
+	for n, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		prop, err := parseICSProperty(line)
+		if err != nil {
+			return nil, nil, fmt.Errorf("satır %d: %w", n+1, err)
+		}
+		switch {
+		case prop.Name == "BEGIN" && strings.EqualFold(prop.Value, "VEVENT") && !inEvent:
+			inEvent, current = true, nil
+		case prop.Name == "BEGIN" && inEvent:
+			depth++
+		case prop.Name == "END" && inEvent && depth > 0:
+			depth--
+		case prop.Name == "END" && strings.EqualFold(prop.Value, "VEVENT") && inEvent:
+			inEvent = false
+			index++
+			ev, err := buildICSEvent(current, defaultLoc)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("olay %d atlandı: %v", index, err))
+				continue
+			}
+			events = append(events, ev)
+		case inEvent && depth == 0:
+			current = append(current, prop)
+		}
+	}
+	if inEvent {
+		return nil, nil, fmt.Errorf("geçersiz ICS: kapanmamış VEVENT")
+	}
+	return events, warnings, nil
+}
+
+// unfoldICS: Satır sonlarını normalize eder ve katlanmış (boşluk/tab ile devam eden) satırları birleştirir.
+func unfoldICS(data []byte) []string {
+	text := strings.ReplaceAll(string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))), "\r\n", "\n")
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func parseICSProperty(line string) (icsProperty, error) {
+	// Değer ayırıcı ':' tırnak içindeki parametre değerlerinde de geçebilir.
+	colon, quoted := -1, false
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return icsProperty{}, fmt.Errorf("geçersiz içerik satırı %q", line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop := icsProperty{Name: strings.ToUpper(parts[0]), Params: map[string]string{}, Value: line[colon+1:]}
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		prop.Params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return prop, nil
+}
+
+func buildICSEvent(props []icsProperty, defaultLoc *time.Location) (icsEvent, error) {
+	var ev icsEvent
+	var duration *icsProperty
+	hasStart, hasEnd := false, false
+
+	for i, p := range props {
+		var err error
+		switch p.Name {
+		case "UID":
+			ev.UID = p.Value
+		case "SUMMARY":
+			ev.Summary = unescapeICSText(p.Value)
+		case "DTSTART":
+			ev.Start, ev.AllDay, err = parseICSTime(p, defaultLoc)
+			hasStart = true
+		case "DTEND":
+			ev.End, _, err = parseICSTime(p, defaultLoc)
+			hasEnd = true
+		case "DURATION":
+			duration = &props[i]
+		case "RRULE":
+			ev.RRule = parseRRule(p.Value)
+		}
+		if err != nil {
+			return icsEvent{}, fmt.Errorf("%s: %w", p.Name, err)
+		}
+	}
+
+	if !hasStart {
+		return icsEvent{}, fmt.Errorf("DTSTART yok")
+	}
+	switch {
+	case hasEnd:
+	case duration != nil:
+		d, err := parseICSDuration(duration.Value)
+		if err != nil {
+			return icsEvent{}, fmt.Errorf("DURATION: %w", err)
+		}
+		ev.End = ev.Start.Add(d)
+	case ev.AllDay:
+		ev.End = ev.Start.AddDate(0, 0, 1)
+	default:
+		ev.End = ev.Start
+	}
+	if !ev.End.After(ev.Start) {
+		return icsEvent{}, fmt.Errorf("bitiş başlangıçtan sonra olmalı")
+	}
+	return ev, nil
+}
+
+// parseICSTime: DATE ("20260101"), UTC ("20260101T090000Z") veya TZID'li/floating yerel zaman.
+func parseICSTime(p icsProperty, defaultLoc *time.Location) (time.Time, bool, error) {
+	v := strings.TrimSpace(p.Value)
+	if strings.EqualFold(p.Params["VALUE"], "DATE") || len(v) == len(icsDateLayout) {
+		t, err := time.ParseInLocation(icsDateLayout, v, defaultLoc)
+		return t, true, err
+	}
+	if strings.HasSuffix(v, "Z") {
+		t, err := time.Parse(icsUTCLayout, v)
+		return t, false, err
+	}
+	loc := defaultLoc
+	if tzid := p.Params["TZID"]; tzid != "" {
+		l, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("bilinmeyen TZID %q", tzid)
+		}
+		loc = l
+	}
+	t, err := time.ParseInLocation(icsDateTimeLayout, v, loc)
+	return t, false, err
+}
+
+func parseICSDuration(v string) (time.Duration, error) {
+	m := icsDurationPattern.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil || v == "P" || v == "PT" {
+		return 0, fmt.Errorf("geçersiz süre %q", v)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+2] == "" {
+			continue
+		}
+		n, _ := strconv.Atoi(m[i+2])
+		d += time.Duration(n) * unit
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+func parseRRule(v string) map[string]string {
+	rule := map[string]string{}
+	for _, part := range strings.Split(v, ";") {
+		k, val, ok := strings.Cut(part, "=")
+		if ok {
+			rule[strings.ToUpper(k)] = strings.ToUpper(val)
+		}
+	}
+	return rule
+}
+
+// yearlyHolidayRule: FREQ=YEARLY kuralını tatil kuralına çevirir.
+// Desteklenen: BYMONTH+BYMONTHDAY (veya DTSTART'ın ay/günü) ve BYMONTH+BYDAY ("-1MO", "4TH").
+func yearlyHolidayRule(ev icsEvent) (HolidayRule, error) {
+	rr := ev.RRule
+	if rr["FREQ"] != "YEARLY" {
+		return HolidayRule{}, fmt.Errorf("desteklenmeyen RRULE FREQ=%s (yalnızca YEARLY)", rr["FREQ"])
+	}
+	for _, k := range []string{"COUNT", "UNTIL"} {
+		if _, ok := rr[k]; ok {
+			return HolidayRule{}, fmt.Errorf("sınırlı tekrar (%s) desteklenmiyor", k)
+		}
+	}
+	if iv, ok := rr["INTERVAL"]; ok && iv != "1" {
+		return HolidayRule{}, fmt.Errorf("INTERVAL=%s desteklenmiyor", iv)
+	}
+
+	month := int(ev.Start.Month())
+	if bm, ok := rr["BYMONTH"]; ok {
+		n, err := strconv.Atoi(bm)
+		if err != nil {
+			return HolidayRule{}, fmt.Errorf("desteklenmeyen BYMONTH=%s", bm)
+		}
+		month = n
+	}
+
+	if byDay, ok := rr["BYDAY"]; ok {
+		if len(byDay) < 3 {
+			return HolidayRule{}, fmt.Errorf("sıra numarasız BYDAY=%s desteklenmiyor", byDay)
+		}
+		n, err := strconv.Atoi(byDay[:len(byDay)-2])
+		weekday, known := icsWeekdays[byDay[len(byDay)-2:]]
+		if err != nil || !known {
+			return HolidayRule{}, fmt.Errorf("desteklenmeyen BYDAY=%s", byDay)
+		}
+		return HolidayRule{Name: ev.Summary, NthWeekday: &NthWeekday{Month: month, Weekday: weekday, N: n}}, nil
+	}
+
+	day := ev.Start.Day()
+	if bmd, ok := rr["BYMONTHDAY"]; ok {
+		n, err := strconv.Atoi(bmd)
+		if err != nil || n < 1 {
+			return HolidayRule{}, fmt.Errorf("desteklenmeyen BYMONTHDAY=%s", bmd)
+		}
+		day = n
+	}
+	return HolidayRule{Name: ev.Summary, Annual: fmt.Sprintf("%02d-%02d", month, day)}, nil
+}
+
+func unescapeICSText(v string) string {
+	r := strings.NewReplacer(`\\`, `\`, `\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ")
+	return r.Replace(v)
+}
+
+func escapeICSText(v string) string {
+	r := strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\n", `\n`)
+	return r.Replace(v)
+}
+
+// icsWriter: CRLF satır sonları ve 75 octet satır katlaması ile ICS üretir.
+type icsWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *icsWriter) line(name, value string) {
+	line := name + ":" + value
+	limit := icsMaxLineOctets
+	for len(line) > limit {
+		// UTF-8 karakterleri bölünmeden katlanır; devam satırlarındaki baştaki boşluk da sınıra dahildir.
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.buf.WriteString(line[:cut])
+		w.buf.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icsMaxLineOctets - 1
+	}
+	w.buf.WriteString(line)
+	w.buf.WriteString("\r\n")
+}
+
+func (w *icsWriter) bytes() []byte {
+	return w.buf.Bytes()
+}
@@ -0,0 +1,215 @@
+package dialplan
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// icsDoc: Satırları CRLF ile birleştirip VCALENDAR ile sarar.
+func icsDoc(lines ...string) []byte {
+	all := append([]string{"BEGIN:VCALENDAR", "VERSION:2.0"}, lines...)
+	all = append(all, "END:VCALENDAR", "")
+	return []byte(strings.Join(all, "\r\n"))
+}
+
+func TestUnfoldICS(t *testing.T) {
+	data := []byte("\xef\xbb\xbfBEGIN:VCALENDAR\r\nSUMMARY:Cumhuriyet \r\n Bayramı\r\nDESCRIPTION:a\n\tb\r\nEND:VCALENDAR")
+	want := []string{"BEGIN:VCALENDAR", "SUMMARY:Cumhuriyet Bayramı", "DESCRIPTION:ab", "END:VCALENDAR"}
+	if got := unfoldICS(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("unfoldICS = %q, want %q", got, want)
+	}
+}
+
+func TestParseICSProperty(t *testing.T) {
+	prop, err := parseICSProperty(`dtstart;TZID="America/New_York";value=DATE-TIME:20260105T090000`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := icsProperty{
+		Name:   "DTSTART",
+		Params: map[string]string{"TZID": "America/New_York", "VALUE": "DATE-TIME"},
+		Value:  "20260105T090000",
+	}
+	if !reflect.DeepEqual(prop, want) {
+		t.Errorf("parseICSProperty = %+v, want %+v", prop, want)
+	}
+	if _, err := parseICSProperty("SUMMARY"); err == nil {
+		t.Error("':' olmayan satır hata vermeli")
+	}
+}
+
+func TestParseICS(t *testing.T) {
+	istanbul, err := time.LoadLocation("Europe/Istanbul")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := icsDoc(
+		"BEGIN:VEVENT",
+		"UID:all-day",
+		"SUMMARY:Yılbaşı\\, tatil",
+		"DTSTART;VALUE=DATE:20260101",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:multi-day",
+		"SUMMARY:Kurban ",
+		" Bayramı",
+		"DTSTART;VALUE=DATE:20260526",
+		"DTEND;VALUE=DATE:20260530",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:tzid",
+		"DTSTART;TZID=America/New_York:20260105T090000",
+		"DURATION:PT1H30M",
+		"BEGIN:VALARM",
+		"TRIGGER:-PT15M",
+		"DTSTART:20990101T000000Z",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:utc",
+		"DTSTART:20260105T090000Z",
+		"DTEND:20260105T100000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:floating",
+		"DTSTART:20260105T090000",
+		"DTEND:20260105T100000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:yearly",
+		"SUMMARY:Anneler Günü",
+		"DTSTART;VALUE=DATE:20260510",
+		"RRULE:FREQ=YEARLY;BYMONTH=5;BYDAY=2SU",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:no-start",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:bad-tz",
+		"DTSTART;TZID=Mars/Olympus:20260105T090000",
+		"END:VEVENT",
+	)
+
+	events, warnings, err := parseICS(data, istanbul)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, _ := time.LoadLocation("America/New_York")
+	want := []icsEvent{
+		{UID: "all-day", Summary: "Yılbaşı, tatil", AllDay: true,
+			Start: time.Date(2026, 1, 1, 0, 0, 0, 0, istanbul), End: time.Date(2026, 1, 2, 0, 0, 0, 0, istanbul)},
+		{UID: "multi-day", Summary: "Kurban Bayramı", AllDay: true,
+			Start: time.Date(2026, 5, 26, 0, 0, 0, 0, istanbul), End: time.Date(2026, 5, 30, 0, 0, 0, 0, istanbul)},
+		{UID: "tzid",
+			Start: time.Date(2026, 1, 5, 9, 0, 0, 0, newYork), End: time.Date(2026, 1, 5, 10, 30, 0, 0, newYork)},
+		{UID: "utc",
+			Start: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC), End: time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)},
+		{UID: "floating",
+			Start: time.Date(2026, 1, 5, 9, 0, 0, 0, istanbul), End: time.Date(2026, 1, 5, 10, 0, 0, 0, istanbul)},
+		{UID: "yearly", Summary: "Anneler Günü", AllDay: true,
+			Start: time.Date(2026, 5, 10, 0, 0, 0, 0, istanbul), End: time.Date(2026, 5, 11, 0, 0, 0, 0, istanbul),
+			RRule: map[string]string{"FREQ": "YEARLY", "BYMONTH": "5", "BYDAY": "2SU"}},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("%d olay okundu, want %d: %+v", len(events), len(want), events)
+	}
+	for i, ev := range events {
+		w := want[i]
+		if ev.UID != w.UID || ev.Summary != w.Summary || ev.AllDay != w.AllDay ||
+			!ev.Start.Equal(w.Start) || !ev.End.Equal(w.End) || !reflect.DeepEqual(ev.RRule, w.RRule) {
+			t.Errorf("olay %d = %+v, want %+v", i, ev, w)
+		}
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "DTSTART yok") || !strings.Contains(warnings[1], "Mars/Olympus") {
+		t.Errorf("warnings = %q", warnings)
+	}
+}
+
+func TestParseICSInvalid(t *testing.T) {
+	tests := map[string][]byte{
+		"not a calendar": []byte("BEGIN:VEVENT\r\nEND:VEVENT\r\n"),
+		"unclosed event": []byte("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART:20260101T000000Z\r\nEND:VCALENDAR\r\n"),
+		"bad line":       []byte("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nGARBAGE\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := parseICS(data, time.UTC); err == nil {
+				t.Error("hata bekleniyordu")
+			}
+		})
+	}
+}
+
+func TestParseICSDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "PT1H30M", want: 90 * time.Minute},
+		{in: "P1D", want: 24 * time.Hour},
+		{in: "P1W", want: 7 * 24 * time.Hour},
+		{in: "P1DT2H", want: 26 * time.Hour},
+		{in: "-PT15M", want: -15 * time.Minute},
+		{in: "P", wantErr: true},
+		{in: "PT", wantErr: true},
+		{in: "1H", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseICSDuration(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseICSDuration(%q) = %v, %v; want %v, err=%v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestYearlyHolidayRule(t *testing.T) {
+	start := time.Date(2026, 10, 29, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		rrule   string
+		want    HolidayRule
+		wantErr bool
+	}{
+		{name: "dtstart month and day", rrule: "FREQ=YEARLY", want: HolidayRule{Name: "x", Annual: "10-29"}},
+		{name: "bymonth bymonthday", rrule: "FREQ=YEARLY;BYMONTH=4;BYMONTHDAY=23", want: HolidayRule{Name: "x", Annual: "04-23"}},
+		{name: "interval one", rrule: "FREQ=YEARLY;INTERVAL=1", want: HolidayRule{Name: "x", Annual: "10-29"}},
+		{name: "nth weekday", rrule: "FREQ=YEARLY;BYMONTH=11;BYDAY=4TH", want: HolidayRule{Name: "x", NthWeekday: &NthWeekday{Month: 11, Weekday: "thu", N: 4}}},
+		{name: "last weekday", rrule: "FREQ=YEARLY;BYMONTH=5;BYDAY=-1MO", want: HolidayRule{Name: "x", NthWeekday: &NthWeekday{Month: 5, Weekday: "mon", N: -1}}},
+		{name: "monthly", rrule: "FREQ=MONTHLY", wantErr: true},
+		{name: "count", rrule: "FREQ=YEARLY;COUNT=3", wantErr: true},
+		{name: "until", rrule: "FREQ=YEARLY;UNTIL=20300101", wantErr: true},
+		{name: "interval", rrule: "FREQ=YEARLY;INTERVAL=2", wantErr: true},
+		{name: "byday without ordinal", rrule: "FREQ=YEARLY;BYDAY=MO", wantErr: true},
+		{name: "unknown weekday", rrule: "FREQ=YEARLY;BYDAY=1XX", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := yearlyHolidayRule(icsEvent{Summary: "x", Start: start, AllDay: true, RRule: parseRRule(tt.rrule)})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("yearlyHolidayRule = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestICSWriterFolding(t *testing.T) {
+	w := &icsWriter{}
+	summary := strings.Repeat("Çalışma saatleri dışı ", 8)
+	w.line("SUMMARY", summary)
+
+	out := string(w.bytes())
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > icsMaxLineOctets {
+			t.Errorf("satır %d octet (> %d): %q", len(line), icsMaxLineOctets, line)
+		}
+	}
+	if got := unfoldICS([]byte(out)); got[0] != "SUMMARY:"+summary {
+		t.Errorf("katlanan satır geri açılınca %q, want %q", got[0], "SUMMARY:"+summary)
+	}
+}
@@ -0,0 +1,160 @@
+// sentiric-dialplan-service/internal/service/dialplan/numbering.go
+package dialplan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultCountry: Konfigürasyon verilmediğinde kullanılan numaralandırma planı.
+const DefaultCountry = "TR"
+
+// NumberingPlan: Bir ülkenin numara normalizasyon kuralları.
+// Normalize çıktısı '+' içermeyen E.164 formudur (örn: "905321234567").
+type NumberingPlan struct {
+	Country             string // ISO 3166-1 alpha-2
+	CountryCode         string // "90", "49", "1"
+	TrunkPrefix         string // Ulusal arama öneki ("0"); yoksa boş
+	InternationalPrefix string // Uluslararası çıkış kodu ("00", "011")
+	NationalLengths     []int  // Trunk/ülke kodu hariç geçerli ulusal numara uzunlukları
+	// AcceptBareNational: Trunk öneki olmadan yazılmış ulusal numaralar (örn: TR "5321234567")
+	// ülke koduyla tamamlanır. Değişken uzunluklu planlarda (DE) belirsizlik yaratacağı için kapalıdır.
+	AcceptBareNational bool
+}
+
+// numberingPlans: Desteklenen ülke kural setleri.
+var numberingPlans = map[string]NumberingPlan{
+	"TR": {Country: "TR", CountryCode: "90", TrunkPrefix: "0", InternationalPrefix: "00", NationalLengths: []int{10}, AcceptBareNational: true},
+	"DE": {Country: "DE", CountryCode: "49", TrunkPrefix: "0", InternationalPrefix: "00", NationalLengths: []int{6, 7, 8, 9, 10, 11}},
+	"GB": {Country: "GB", CountryCode: "44", TrunkPrefix: "0", InternationalPrefix: "00", NationalLengths: []int{9, 10}},
+	"FR": {Country: "FR", CountryCode: "33", TrunkPrefix: "0", InternationalPrefix: "00", NationalLengths: []int{9}},
+	"NL": {Country: "NL", CountryCode: "31", TrunkPrefix: "0", InternationalPrefix: "00", NationalLengths: []int{9}},
+	"ES": {Country: "ES", CountryCode: "34", InternationalPrefix: "00", NationalLengths: []int{9}, AcceptBareNational: true},
+	// İtalya'da sabit hat numaralarının baştaki 0'ı numaranın parçasıdır (+39 06 ...), trunk öneki yoktur.
+	"IT": {Country: "IT", CountryCode: "39", InternationalPrefix: "00", NationalLengths: []int{9, 10}, AcceptBareNational: true},
+	// NANP (US/CA): Trunk öneki "1", ulusal numara 10 hanedir.
+	"US": {Country: "US", CountryCode: "1", TrunkPrefix: "1", InternationalPrefix: "011", NationalLengths: []int{10}, AcceptBareNational: true},
+	"CA": {Country: "CA", CountryCode: "1", TrunkPrefix: "1", InternationalPrefix: "011", NationalLengths: []int{10}, AcceptBareNational: true},
+}
+
+// LookupNumberingPlan: Ülke koduna göre kural setini döner (büyük/küçük harf duyarsız).
+func LookupNumberingPlan(country string) (NumberingPlan, bool) {
+	p, ok := numberingPlans[strings.ToUpper(strings.TrimSpace(country))]
+	return p, ok
+}
+
+// SupportedCountries: Kayıtlı kural setlerinin sıralı listesi.
+func SupportedCountries() []string {
+	list := make([]string, 0, len(numberingPlans))
+	for c := range numberingPlans {
+		list = append(list, c)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Normalize: Numarayı ülke kurallarına göre E.164 (öneksiz) forma getirir.
+// Sırasıyla: '+' ile E.164, uluslararası çıkış kodu, trunk öneki, ülke kodu ile yazılmış ulusal numara
+// ve çıplak ulusal numara denenir. Hiçbiri uymuyorsa (kısa kod, dahili) yalnızca rakamlar döner.
+func (p NumberingPlan) Normalize(phone string) string {
+	if phone == "anonymous" {
+		return phone
+	}
+	trimmed := strings.TrimSpace(phone)
+	var sb strings.Builder
+	for _, ch := range trimmed {
+		if unicode.IsDigit(ch) {
+			sb.WriteRune(ch)
+		}
+	}
+	digits := sb.String()
+
+	if digits == "" {
+		return phone
+	}
+	if strings.HasPrefix(trimmed, "+") {
+		return digits
+	}
+	if p.InternationalPrefix != "" && strings.HasPrefix(digits, p.InternationalPrefix) && len(digits) > len(p.InternationalPrefix) {
+		return digits[len(p.InternationalPrefix):]
+	}
+	if p.TrunkPrefix != "" && strings.HasPrefix(digits, p.TrunkPrefix) && p.isNationalLength(len(digits)-len(p.TrunkPrefix)) {
+		return p.CountryCode + digits[len(p.TrunkPrefix):]
+	}
+	if strings.HasPrefix(digits, p.CountryCode) && p.isNationalLength(len(digits)-len(p.CountryCode)) {
+		return digits
+	}
+	if p.AcceptBareNational && p.isNationalLength(len(digits)) &&
+		(p.TrunkPrefix == "" || !strings.HasPrefix(digits, p.TrunkPrefix)) {
+		return p.CountryCode + digits
+	}
+	return digits
+}
+
+// NormalizePrefix: Prefix route anahtarını ("0212555", "+90212555", "0090212555") numaralarla aynı
+// E.164 (öneksiz) forma getirir. Uzunluk bilinmediği için ulusal biçim yalnızca trunk önekiyle ya da
+// baştaki 0 ile (hiçbir ülke kodu 0 ile başlamaz) anlaşılır; diğer prefix'ler zaten E.164 kabul edilir.
+// Öneklerden arta kalan boş prefix için false döner.
+func (p NumberingPlan) NormalizePrefix(prefix string) (string, bool) {
+	digits, international := strings.CutPrefix(prefix, "+")
+	if !isDigits(digits) {
+		return "", false
+	}
+	switch {
+	case international:
+	case p.InternationalPrefix != "" && strings.HasPrefix(digits, p.InternationalPrefix):
+		digits = digits[len(p.InternationalPrefix):]
+	case p.TrunkPrefix != "" && strings.HasPrefix(digits, p.TrunkPrefix):
+		digits = p.CountryCode + digits[len(p.TrunkPrefix):]
+	case strings.HasPrefix(digits, "0"):
+		digits = p.CountryCode + digits
+	}
+	return digits, digits != ""
+}
+
+func (p NumberingPlan) isNationalLength(n int) bool {
+	for _, l := range p.NationalLengths {
+		if l == n {
+			return true
+		}
+	}
+	return false
+}
+
+// Numbering: Varsayılan ve tenant bazlı numaralandırma planlarını tutar.
+type Numbering struct {
+	defaultPlan NumberingPlan
+	tenantPlans map[string]NumberingPlan
+}
+
+// NewNumbering: Varsayılan ülke ve tenant->ülke eşlemesinden Numbering oluşturur.
+func NewNumbering(defaultCountry string, tenantCountries map[string]string) (*Numbering, error) {
+	def, ok := LookupNumberingPlan(defaultCountry)
+	if !ok {
+		return nil, fmt.Errorf("desteklenmeyen varsayılan ülke %q (desteklenenler: %s)", defaultCountry, strings.Join(SupportedCountries(), ","))
+	}
+	n := &Numbering{defaultPlan: def, tenantPlans: make(map[string]NumberingPlan, len(tenantCountries))}
+	for tenant, country := range tenantCountries {
+		p, ok := LookupNumberingPlan(country)
+		if !ok {
+			return nil, fmt.Errorf("tenant %q için desteklenmeyen ülke %q", tenant, country)
+		}
+		n.tenantPlans[tenant] = p
+	}
+	return n, nil
+}
+
+// Default: Tenant bilinmeden (örn: route lookup öncesi) kullanılan plan.
+func (n *Numbering) Default() NumberingPlan {
+	return n.defaultPlan
+}
+
+// ForTenant: Tenant'a atanmış plan; atanmamışsa varsayılan plan.
+func (n *Numbering) ForTenant(tenantID string) NumberingPlan {
+	if p, ok := n.tenantPlans[tenantID]; ok {
+		return p
+	}
+	return n.defaultPlan
+}
@@ -0,0 +1,127 @@
+package dialplan
+
+import "testing"
+
+func TestNumberingPlanNormalize(t *testing.T) {
+	tests := map[string][]struct {
+		name, in, want string
+	}{
+		"TR": {
+			{"trunk", "0532 123 45 67", "905321234567"},
+			{"plus", "+90 532 123 45 67", "905321234567"},
+			{"international", "00905321234567", "905321234567"},
+			{"bare national", "5321234567", "905321234567"},
+			{"e164 without plus", "905321234567", "905321234567"},
+			{"short code", "112", "112"},
+			{"anonymous", "anonymous", "anonymous"},
+		},
+		"DE": {
+			{"trunk", "030 1234567", "49301234567"},
+			{"plus", "+49 30 1234567", "49301234567"},
+			{"international", "0049301234567", "49301234567"},
+			{"bare national not accepted", "301234567", "301234567"},
+			{"short code", "110", "110"},
+		},
+		"GB": {
+			{"trunk", "020 7946 0958", "442079460958"},
+			{"plus", "+44 20 7946 0958", "442079460958"},
+			{"international", "00442079460958", "442079460958"},
+			{"short code", "999", "999"},
+		},
+		"FR": {
+			{"trunk", "01 23 45 67 89", "33123456789"},
+			{"plus", "+33 1 23 45 67 89", "33123456789"},
+			{"international", "0033123456789", "33123456789"},
+			{"short code", "15", "15"},
+		},
+		"NL": {
+			{"trunk", "020 123 4567", "31201234567"},
+			{"plus", "+31 20 123 4567", "31201234567"},
+			{"international", "0031201234567", "31201234567"},
+			{"short code", "112", "112"},
+		},
+		"ES": {
+			{"bare national", "912 345 678", "34912345678"},
+			{"plus", "+34 912 345 678", "34912345678"},
+			{"international", "0034912345678", "34912345678"},
+			{"e164 without plus", "34912345678", "34912345678"},
+			{"short code", "112", "112"},
+		},
+		"IT": {
+			{"national with leading zero", "06 1234 5678", "390612345678"},
+			{"plus", "+39 06 1234 5678", "390612345678"},
+			{"international", "00390612345678", "390612345678"},
+			{"mobile", "312 345 6789", "393123456789"},
+			{"short code", "113", "113"},
+		},
+		"US": {
+			{"bare national", "(212) 555-0123", "12125550123"},
+			{"trunk", "1 212 555 0123", "12125550123"},
+			{"plus", "+1 212 555 0123", "12125550123"},
+			{"international", "011 44 20 7946 0958", "442079460958"},
+			{"short code", "911", "911"},
+		},
+		"CA": {
+			{"bare national", "(416) 555-0199", "14165550199"},
+			{"trunk", "1-416-555-0199", "14165550199"},
+			{"plus", "+1 416 555 0199", "14165550199"},
+			{"international", "011 33 1 23 45 67 89", "33123456789"},
+			{"short code", "911", "911"},
+		},
+	}
+	for country, cases := range tests {
+		plan, ok := LookupNumberingPlan(country)
+		if !ok {
+			t.Fatalf("plan %s bulunamadı", country)
+		}
+		for _, tt := range cases {
+			t.Run(country+"/"+tt.name, func(t *testing.T) {
+				if got := plan.Normalize(tt.in); got != tt.want {
+					t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestParseRoutePatternPrefixNormalization(t *testing.T) {
+	tests := []struct {
+		country, in, want string
+		wantErr           bool
+	}{
+		{country: "TR", in: "0212555*", want: "90212555*"},
+		{country: "TR", in: "+90212555*", want: "90212555*"},
+		{country: "TR", in: "0090212555*", want: "90212555*"},
+		{country: "TR", in: "90212555*", want: "90212555*"},
+		{country: "DE", in: "030*", want: "4930*"},
+		{country: "GB", in: "020*", want: "4420*"},
+		{country: "IT", in: "06*", want: "3906*"},
+		{country: "ES", in: "91*", want: "91*"}, // trunk öneki yok: E.164 kabul edilir
+		{country: "US", in: "1212*", want: "1212*"},
+		{country: "US", in: "01144*", want: "44*"},
+		{country: "TR", in: "00*", wantErr: true},
+		{country: "TR", in: "+*", wantErr: true},
+		{country: "TR", in: "02a*", wantErr: true},
+	}
+	for _, tt := range tests {
+		plan, _ := LookupNumberingPlan(tt.country)
+		p, err := ParseRoutePattern(tt.in, plan)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s %q: hata bekleniyordu, %q döndü", tt.country, tt.in, p)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %q: %v", tt.country, tt.in, err)
+			continue
+		}
+		if got := p.String(); got != tt.want {
+			t.Errorf("%s %q = %q, want %q", tt.country, tt.in, got, tt.want)
+		}
+		// Saklanan anahtar tekrar ayrıştırıldığında değişmemeli.
+		if again, err := parseStoredRoutePattern(p.String()); err != nil || again != p {
+			t.Errorf("%s %q: kanonik form kararlı değil: %v %v", tt.country, tt.in, again, err)
+		}
+	}
+}
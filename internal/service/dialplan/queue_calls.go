@@ -0,0 +1,294 @@
+// sentiric-dialplan-service/internal/service/dialplan/queue_calls.go
+package dialplan
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/cache"
+	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
+)
+
+// DefaultServiceLevel: Bu süre içinde cevaplanan çağrılar servis seviyesine dahildir (klasik 80/20 hedefinin "20"si).
+const DefaultServiceLevel = 20 * time.Second
+
+// QueueTracker: Kuyrukta bekleyen çağrılar ve kuyruk istatistikleri deposu (örn: Redis).
+type QueueTracker interface {
+	Enqueue(ctx context.Context, queueID, callID string, boost, maxWait time.Duration) (cache.QueueSlot, error)
+	Position(ctx context.Context, queueID, callID string, maxWait time.Duration) (cache.QueueSlot, bool, error)
+	Leave(ctx context.Context, queueID, callID, agentID, outcome string, serviceLevel time.Duration) (time.Duration, bool, error)
+	CompleteAgentCall(ctx context.Context, agentID string) error
+	Stats(ctx context.Context, queueID string, maxWait time.Duration) (cache.QueueStats, error)
+}
+
+// queueMaxWait: Kuyruğun azami bekleme süresi; 0 sınırsızdır.
+func queueMaxWait(queue *dialplanv1.Queue) time.Duration {
+	return time.Duration(queue.MaxWaitTimeSeconds) * time.Second
+}
+
+// WithQueueTracker: Kuyruk konum takibini ve istatistiklerini etkinleştirir.
+// serviceLevel <= 0 ise DefaultServiceLevel kullanılır.
+func WithQueueTracker(tracker QueueTracker, serviceLevel time.Duration) Option {
+	return func(s *Service) {
+		if serviceLevel <= 0 {
+			serviceLevel = DefaultServiceLevel
+		}
+		s.queueTracker = tracker
+		s.serviceLevel = serviceLevel
+	}
+}
+
+// errQueueTrackerUnavailable: Kuyruk takip deposu olmadan konum ve istatistik tutulamaz; gRPC'de FailedPrecondition döner.
+var errQueueTrackerUnavailable = fmt.Errorf("%w: kuyruk takip deposu", ErrNotConfigured)
+
+// estimatedWait: position'daki çağrı için tahmini bekleme: position * AHT / oturum açmış ajan sayısı.
+// Ortalama görüşme süresi henüz bilinmiyorsa veya oturum açmış ajan yoksa 0 (tahmin yok) döner.
+func estimatedWait(position int, averageHandle time.Duration, agents int) time.Duration {
+	if position <= 0 || averageHandle <= 0 || agents <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(float64(position)*averageHandle.Seconds()/float64(agents))) * time.Second
+}
+
+// loggedInAgents: Kuyrukta oturum açmış (müsait, rezerve veya görüşmede) ajan sayısı.
+func (s *Service) loggedInAgents(ctx context.Context, queueID string) (int, error) {
+	members, err := s.queueCandidates(ctx, queueID)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, m := range members {
+		switch m.state.Status {
+		case AgentStatusAvailable, AgentStatusReserved, AgentStatusBusy:
+			n++
+		}
+	}
+	return n, nil
+}
+
+// queuePosition: Konum yanıtını tahmini bekleme süresiyle birlikte üretir.
+func (s *Service) queuePosition(ctx context.Context, queue *dialplanv1.Queue, callID string, slot cache.QueueSlot) (*dialplanv1.QueuePosition, error) {
+	queueID := queue.Id
+	stats, err := s.queueTracker.Stats(ctx, queueID, queueMaxWait(queue))
+	if err != nil {
+		return nil, err
+	}
+	agents, err := s.loggedInAgents(ctx, queueID)
+	if err != nil {
+		return nil, err
+	}
+	return &dialplanv1.QueuePosition{
+		QueueId:              queueID,
+		CallId:               callID,
+		Position:             int32(slot.Position),
+		Waiting:              int32(slot.Waiting),
+		WaitSeconds:          int32(slot.Waited / time.Second),
+		EstimatedWaitSeconds: int32(estimatedWait(slot.Position, stats.AverageHandle, agents) / time.Second),
+	}, nil
+}
+
+func validateQueueCall(queueID, callID string) error {
+	var v violations
+	if strings.TrimSpace(queueID) == "" {
+		v.add("queue_id", "queue_id zorunludur")
+	}
+	if strings.TrimSpace(callID) == "" {
+		v.add("call_id", "call_id zorunludur")
+	}
+	return v.err()
+}
+
+// EnqueueCall: Çağrıyı priority sınıfıyla (boş => normal; genellikle resolve yanıtındaki "priority") kuyruğa
+// alır ve konumunu döner. Aynı çağrı tekrar eklenirse yeri değişmez.
+func (s *Service) EnqueueCall(ctx context.Context, queueID, callID, priority string) (*dialplanv1.QueuePosition, error) {
+	if err := validateQueueCall(queueID, callID); err != nil {
+		return nil, err
+	}
+	var v violations
+	priority = validatePriority("priority", priority, &v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	if s.queueTracker == nil {
+		return nil, errQueueTrackerUnavailable
+	}
+	queue, err := s.repo.GetQueue(ctx, queueID)
+	if err != nil {
+		return nil, resourceErr(err, ResourceQueue, queueID)
+	}
+
+	// Çağrı kabul edemeyen kuyruğa alınmaz; fallback aksiyonu hemen döner.
+	l := logger.ContextLogger(ctx, s.baseLog)
+	if reason := s.queueUnavailable(ctx, queue, l); reason != "" {
+		return s.overflowPosition(ctx, queue, callID, reason, l), nil
+	}
+
+	slot, err := s.queueTracker.Enqueue(ctx, queueID, callID, priorityBoost(priority), queueMaxWait(queue))
+	if err != nil {
+		return nil, err
+	}
+	l.Info().
+		Str("event", logger.EventQueueCallEnqueued).
+		Str("queue_id", queueID).
+		Str("call_id", callID).
+		Str("priority", priority).
+		Int("position", slot.Position).
+		Msg("Çağrı kuyruğa alındı.")
+	return s.queuePosition(ctx, queue, callID, slot)
+}
+
+// GetQueuePosition: Bekleyen çağrının güncel konumu ve tahmini bekleme süresi. Çağrı max_wait_time_seconds'ı
+// aştıysa ya da kuyruk artık çağrı kabul edemiyorsa çağrı taşmış olarak kuyruktan çıkarılır ve
+// Overflow alanında gideceği aksiyon döner.
+func (s *Service) GetQueuePosition(ctx context.Context, queueID, callID string) (*dialplanv1.QueuePosition, error) {
+	if err := validateQueueCall(queueID, callID); err != nil {
+		return nil, err
+	}
+	if s.queueTracker == nil {
+		return nil, errQueueTrackerUnavailable
+	}
+	queue, err := s.repo.GetQueue(ctx, queueID)
+	if err != nil {
+		return nil, resourceErr(err, ResourceQueue, queueID)
+	}
+	slot, ok, err := s.queueTracker.Position(ctx, queueID, callID, queueMaxWait(queue))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, resourceErr(ErrNotFound, ResourceQueueCall, queueID+"/"+callID)
+	}
+
+	l := logger.ContextLogger(ctx, s.baseLog)
+	reason := ""
+	if maxWait := queueMaxWait(queue); maxWait > 0 && slot.Waited >= maxWait {
+		reason = OverflowReasonMaxWait
+	} else {
+		reason = s.queueUnavailable(ctx, queue, l)
+	}
+	if reason == "" {
+		return s.queuePosition(ctx, queue, callID, slot)
+	}
+
+	waited, _, err := s.queueTracker.Leave(ctx, queueID, callID, "", cache.QueueOutcomeOverflowed, s.serviceLevel)
+	if err != nil {
+		return nil, err
+	}
+	pos := s.overflowPosition(ctx, queue, callID, reason, l)
+	pos.WaitSeconds = int32(waited / time.Second)
+	return pos, nil
+}
+
+// overflowPosition: Taşan çağrının yanıtı; konum 0'dır ve Overflow alanı fallback aksiyonunu taşır.
+func (s *Service) overflowPosition(ctx context.Context, queue *dialplanv1.Queue, callID, reason string, l zerolog.Logger) *dialplanv1.QueuePosition {
+	overflow := s.queueOverflow(ctx, queue, reason, l)
+	l.Info().
+		Str("event", logger.EventQueueOverflow).
+		Str("queue_id", queue.Id).
+		Str("call_id", callID).
+		Str("reason", reason).
+		Strs("chain", overflow.Chain).
+		Str("action", overflow.Action.GetAction()).
+		Msg("Kuyruk taştı, fallback aksiyonu uygulanıyor.")
+	return &dialplanv1.QueuePosition{QueueId: queue.Id, CallId: callID, Overflow: overflow}
+}
+
+// DequeueCall: Çağrı bir ajana bağlandığında kuyruktan çıkarır ve bekleme süresini döner. agentID verilirse
+// görüşme süresi, ajan "busy" durumundan çıktığında ortalama görüşme süresine (AHT) eklenir.
+func (s *Service) DequeueCall(ctx context.Context, queueID, callID, agentID string) (time.Duration, error) {
+	return s.leaveQueue(ctx, queueID, callID, agentID, cache.QueueOutcomeAnswered)
+}
+
+// AbandonCall: Arayan kuyrukta beklerken kapattığında çağrıyı terk edilmiş olarak kuyruktan çıkarır.
+func (s *Service) AbandonCall(ctx context.Context, queueID, callID string) (time.Duration, error) {
+	return s.leaveQueue(ctx, queueID, callID, "", cache.QueueOutcomeAbandoned)
+}
+
+func (s *Service) leaveQueue(ctx context.Context, queueID, callID, agentID, outcome string) (time.Duration, error) {
+	if err := validateQueueCall(queueID, callID); err != nil {
+		return 0, err
+	}
+	if s.queueTracker == nil {
+		return 0, errQueueTrackerUnavailable
+	}
+	waited, ok, err := s.queueTracker.Leave(ctx, queueID, callID, agentID, outcome, s.serviceLevel)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, resourceErr(ErrNotFound, ResourceQueueCall, queueID+"/"+callID)
+	}
+
+	event := logger.EventQueueCallAbandoned
+	if outcome == cache.QueueOutcomeAnswered {
+		event = logger.EventQueueCallAnswered
+	}
+	l := logger.ContextLogger(ctx, s.baseLog)
+	l.Info().
+		Str("event", event).
+		Str("queue_id", queueID).
+		Str("call_id", callID).
+		Str("agent_id", agentID).
+		Dur("waited", waited).
+		Msg("Çağrı kuyruktan çıktı.")
+	return waited, nil
+}
+
+// completeAgentCall: Ajan görüşmeden çıktığında açık kuyruk çağrısının görüşme süresini kaydeder.
+// İstatistik kaydıdır; hata durum değişikliğini engellemez.
+func (s *Service) completeAgentCall(ctx context.Context, agentID string, l zerolog.Logger) {
+	if s.queueTracker == nil {
+		return
+	}
+	if err := s.queueTracker.CompleteAgentCall(ctx, agentID); err != nil {
+		l.Warn().Err(err).
+			Str("event", logger.EventQueueStatsWriteFailed).
+			Str("agent_id", agentID).
+			Msg("Kuyruk görüşme süresi kaydedilemedi.")
+	}
+}
+
+// GetQueueStats: Kuyruğun anlık (bekleyen, en uzun bekleme, tahmini bekleme) ve bugünkü (UTC) sayaçları.
+// Servis seviyesi, kuyruktan çıkan (cevaplanan, terk edilen, taşan) çağrılar içinde eşik süresinde
+// cevaplananların yüzdesidir.
+func (s *Service) GetQueueStats(ctx context.Context, queueID string) (*dialplanv1.QueueStats, error) {
+	if s.queueTracker == nil {
+		return nil, errQueueTrackerUnavailable
+	}
+	queue, err := s.repo.GetQueue(ctx, queueID)
+	if err != nil {
+		return nil, resourceErr(err, ResourceQueue, queueID)
+	}
+	stats, err := s.queueTracker.Stats(ctx, queueID, queueMaxWait(queue))
+	if err != nil {
+		return nil, err
+	}
+	agents, err := s.loggedInAgents(ctx, queueID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dialplanv1.QueueStats{
+		QueueId:                    queueID,
+		Waiting:                    int32(stats.Waiting),
+		LongestWaitSeconds:         int32(stats.LongestWait / time.Second),
+		Answered:                   int32(stats.Answered),
+		AnsweredWithinServiceLevel: int32(stats.AnsweredInSL),
+		Abandoned:                  int32(stats.Abandoned),
+		Overflowed:                 int32(stats.Overflowed),
+		ServiceLevelSeconds:        int32(s.serviceLevel / time.Second),
+		AverageHandleSeconds:       int32(stats.AverageHandle / time.Second),
+		LoggedInAgents:             int32(agents),
+		// Yeni gelen bir çağrının tahmini beklemesi (kuyruğun sonu).
+		EstimatedWaitSeconds: int32(estimatedWait(stats.Waiting+1, stats.AverageHandle, agents) / time.Second),
+	}
+	if offered := stats.Answered + stats.Abandoned + stats.Overflowed; offered > 0 {
+		resp.ServiceLevelPercent = math.Round(float64(stats.AnsweredInSL)/float64(offered)*1000) / 10
+	}
+	return resp, nil
+}
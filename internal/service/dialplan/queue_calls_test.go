@@ -0,0 +1,300 @@
+package dialplan
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/cache"
+)
+
+// queueRepo: Kuyrukları ve üyelerini bellekte tutan sahte repository.
+type queueRepo struct {
+	Repository
+
+	queues    map[string]*dialplanv1.Queue
+	members   map[string][]string // queue_id -> agent_id'ler
+	dialplans map[string]*dialplanv1.Dialplan
+}
+
+func (r *queueRepo) GetQueue(_ context.Context, id string) (*dialplanv1.Queue, error) {
+	if q, ok := r.queues[id]; ok {
+		return q, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (r *queueRepo) FindDialplanByID(_ context.Context, id string) (*dialplanv1.Dialplan, error) {
+	if dp, ok := r.dialplans[id]; ok {
+		return dp, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (r *queueRepo) ListQueueMembers(_ context.Context, queueID string) ([]*dialplanv1.QueueMember, error) {
+	var out []*dialplanv1.QueueMember
+	for _, id := range r.members[queueID] {
+		out = append(out, &dialplanv1.QueueMember{QueueId: queueID, AgentId: id})
+	}
+	return out, nil
+}
+
+// memQueueTracker: Bekleyen çağrıları sırayla bellekte tutar. Her Enqueue saati bir saniye ilerletir; çağrı,
+// geliş zamanı - boost skoruna göre sıraya girer. Bekleme süresi sabit waited'dır; stats'ın günlük
+// sayaçları Leave ile güncellenir.
+type memQueueTracker struct {
+	mu     sync.Mutex
+	calls  map[string][]string // queue_id -> call_id (skor sırasıyla)
+	scores map[string]time.Duration
+	clock  time.Duration
+	waited time.Duration
+	stats  cache.QueueStats
+}
+
+func newMemQueueTracker() *memQueueTracker {
+	return &memQueueTracker{calls: map[string][]string{}, scores: map[string]time.Duration{}}
+}
+
+func (m *memQueueTracker) slot(queueID, callID string) (cache.QueueSlot, bool) {
+	i := slices.Index(m.calls[queueID], callID)
+	if i < 0 {
+		return cache.QueueSlot{}, false
+	}
+	return cache.QueueSlot{Position: i + 1, Waiting: len(m.calls[queueID]), Waited: m.waited}, true
+}
+
+func (m *memQueueTracker) Enqueue(_ context.Context, queueID, callID string, boost, _ time.Duration) (cache.QueueSlot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !slices.Contains(m.calls[queueID], callID) {
+		m.clock += time.Second
+		score := m.clock - boost
+		m.scores[callID] = score
+		i := len(m.calls[queueID])
+		for i > 0 && m.scores[m.calls[queueID][i-1]] > score {
+			i--
+		}
+		m.calls[queueID] = slices.Insert(m.calls[queueID], i, callID)
+	}
+	slot, _ := m.slot(queueID, callID)
+	return slot, nil
+}
+
+func (m *memQueueTracker) Position(_ context.Context, queueID, callID string, _ time.Duration) (cache.QueueSlot, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	slot, ok := m.slot(queueID, callID)
+	return slot, ok, nil
+}
+
+func (m *memQueueTracker) Leave(_ context.Context, queueID, callID, _, outcome string, serviceLevel time.Duration) (time.Duration, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := slices.Index(m.calls[queueID], callID)
+	if i < 0 {
+		return 0, false, nil
+	}
+	m.calls[queueID] = slices.Delete(m.calls[queueID], i, i+1)
+	switch outcome {
+	case cache.QueueOutcomeAnswered:
+		m.stats.Answered++
+		if m.waited <= serviceLevel {
+			m.stats.AnsweredInSL++
+		}
+	case cache.QueueOutcomeAbandoned:
+		m.stats.Abandoned++
+	case cache.QueueOutcomeOverflowed:
+		m.stats.Overflowed++
+	}
+	return m.waited, true, nil
+}
+
+func (m *memQueueTracker) CompleteAgentCall(context.Context, string) error { return nil }
+
+func (m *memQueueTracker) Stats(_ context.Context, queueID string, _ time.Duration) (cache.QueueStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := m.stats
+	st.Waiting = len(m.calls[queueID])
+	return st, nil
+}
+
+func newQueueService(tracker QueueTracker, agents map[string]cache.AgentState) *Service {
+	repo := &queueRepo{
+		queues:  map[string]*dialplanv1.Queue{"q1": {Id: "q1", Name: "Destek", IsActive: true, MaxWaitTimeSeconds: 300}},
+		members: map[string][]string{"q1": {"a1", "a2", "a3"}},
+	}
+	opts := []Option{WithAgentStateStore(&fakeAgentStore{states: agents, taken: map[string]bool{}})}
+	if tracker != nil {
+		opts = append(opts, WithQueueTracker(tracker, 0))
+	}
+	return NewService(repo, nil, nil, zerolog.Nop(), opts...)
+}
+
+func TestEstimatedWait(t *testing.T) {
+	tests := []struct {
+		name     string
+		position int
+		aht      time.Duration
+		agents   int
+		want     time.Duration
+	}{
+		{"first in line", 1, 3 * time.Minute, 2, 90 * time.Second},
+		{"scales with position", 4, 3 * time.Minute, 2, 6 * time.Minute},
+		{"rounds up to seconds", 1, 100 * time.Second, 3, 34 * time.Second},
+		{"no handle time yet", 3, 0, 2, 0},
+		{"no agents", 3, time.Minute, 0, 0},
+		{"not waiting", 0, time.Minute, 2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := estimatedWait(tt.position, tt.aht, tt.agents); got != tt.want {
+				t.Errorf("estimatedWait(%d, %s, %d) = %s, want %s", tt.position, tt.aht, tt.agents, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetQueueStats(t *testing.T) {
+	agents := map[string]cache.AgentState{
+		"a1": {Status: AgentStatusAvailable},
+		"a2": {Status: AgentStatusBusy},
+		"a3": {Status: AgentStatusPaused}, // oturum açmış sayılmaz
+	}
+	tests := []struct {
+		name          string
+		stats         cache.QueueStats
+		waiting       int
+		wantSL        float64
+		wantEstimated int32
+	}{
+		{name: "no calls", wantSL: 0},
+		{name: "abandoned count against service level", stats: cache.QueueStats{Answered: 7, AnsweredInSL: 5, Abandoned: 1, AverageHandle: time.Minute}, waiting: 2, wantSL: 62.5, wantEstimated: 90},
+		{name: "rounded to one decimal", stats: cache.QueueStats{Answered: 3, AnsweredInSL: 2}, wantSL: 66.7},
+		{name: "all within service level", stats: cache.QueueStats{Answered: 4, AnsweredInSL: 4, AverageHandle: 2 * time.Minute}, wantSL: 100, wantEstimated: 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := newMemQueueTracker()
+			tracker.stats = tt.stats
+			for i := 0; i < tt.waiting; i++ {
+				tracker.calls["q1"] = append(tracker.calls["q1"], string(rune('x'+i)))
+			}
+			svc := newQueueService(tracker, agents)
+
+			resp, err := svc.GetQueueStats(context.Background(), "q1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.ServiceLevelPercent != tt.wantSL {
+				t.Errorf("ServiceLevelPercent = %v, want %v", resp.ServiceLevelPercent, tt.wantSL)
+			}
+			if resp.EstimatedWaitSeconds != tt.wantEstimated {
+				t.Errorf("EstimatedWaitSeconds = %d, want %d", resp.EstimatedWaitSeconds, tt.wantEstimated)
+			}
+			if resp.LoggedInAgents != 2 || resp.ServiceLevelSeconds != int32(DefaultServiceLevel/time.Second) {
+				t.Errorf("LoggedInAgents = %d, ServiceLevelSeconds = %d", resp.LoggedInAgents, resp.ServiceLevelSeconds)
+			}
+		})
+	}
+}
+
+func TestQueueCallFlow(t *testing.T) {
+	ctx := context.Background()
+	tracker := newMemQueueTracker()
+	tracker.waited = 5 * time.Second
+	tracker.stats.AverageHandle = 2 * time.Minute
+	svc := newQueueService(tracker, map[string]cache.AgentState{"a1": {Status: AgentStatusAvailable}, "a2": {Status: AgentStatusBusy}})
+
+	for i, callID := range []string{"c1", "c2", "c3", "c2"} {
+		pos, err := svc.EnqueueCall(ctx, "q1", callID, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []int32{1, 2, 3, 2}[i] // aynı çağrı tekrar eklenince yeri değişmez
+		if pos.Position != want || pos.Waiting != int32(min(i+1, 3)) {
+			t.Errorf("EnqueueCall(%s) = konum %d / %d bekleyen, want %d", callID, pos.Position, pos.Waiting, want)
+		}
+	}
+
+	pos, err := svc.GetQueuePosition(ctx, "q1", "c3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 3 * 120s / 2 ajan
+	if pos.Position != 3 || pos.EstimatedWaitSeconds != 180 || pos.WaitSeconds != 5 {
+		t.Errorf("GetQueuePosition(c3) = %+v", pos)
+	}
+
+	if waited, err := svc.DequeueCall(ctx, "q1", "c1", "a1"); err != nil || waited != 5*time.Second {
+		t.Fatalf("DequeueCall = %s, %v", waited, err)
+	}
+	if _, err := svc.AbandonCall(ctx, "q1", "c2"); err != nil {
+		t.Fatal(err)
+	}
+	if pos, err = svc.GetQueuePosition(ctx, "q1", "c3"); err != nil || pos.Position != 1 || pos.EstimatedWaitSeconds != 60 {
+		t.Errorf("öndekiler çıkınca GetQueuePosition(c3) = %+v, %v", pos, err)
+	}
+
+	var resErr *ResourceError
+	if _, err := svc.GetQueuePosition(ctx, "q1", "c2"); !errors.Is(err, ErrNotFound) || !errors.As(err, &resErr) || resErr.ResourceType != ResourceQueueCall {
+		t.Errorf("kuyruktan çıkan çağrı: err = %v, want queue call ErrNotFound", err)
+	}
+	if _, err := svc.AbandonCall(ctx, "q1", "c2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ikinci kez çıkarma: err = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.EnqueueCall(ctx, "missing", "c9", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("tanımsız kuyruk: err = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.EnqueueCall(ctx, " ", "", ""); !reflect.DeepEqual(violationFields(t, err), []string{"queue_id", "call_id"}) {
+		t.Errorf("boş alanlar doğrulama hatası vermeli: %v", err)
+	}
+
+	stats, err := svc.GetQueueStats(ctx, "q1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Answered != 1 || stats.AnsweredWithinServiceLevel != 1 || stats.Abandoned != 1 || stats.Waiting != 1 || stats.ServiceLevelPercent != 50 {
+		t.Errorf("GetQueueStats = %+v", stats)
+	}
+}
+
+func TestEnqueueCallPriority(t *testing.T) {
+	ctx := context.Background()
+	tracker := newMemQueueTracker()
+	svc := newQueueService(tracker, map[string]cache.AgentState{"a1": {Status: AgentStatusAvailable}})
+
+	for _, c := range []struct{ id, priority string }{{"c1", ""}, {"c2", PriorityNormal}, {"c3", " VIP "}, {"c4", PriorityReturning}} {
+		if _, err := svc.EnqueueCall(ctx, "q1", c.id, c.priority); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// VIP ve returning, boost süreleri içinde gelmiş normal çağrıların önüne geçer; VIP returning'in de önündedir.
+	if want := []string{"c3", "c4", "c1", "c2"}; !reflect.DeepEqual(tracker.calls["q1"], want) {
+		t.Errorf("kuyruk sırası = %v, want %v", tracker.calls["q1"], want)
+	}
+	if _, err := svc.EnqueueCall(ctx, "q1", "c5", "gold"); !reflect.DeepEqual(violationFields(t, err), []string{"priority"}) {
+		t.Errorf("geçersiz öncelik doğrulama hatası vermeli: %v", err)
+	}
+}
+
+func TestQueueCallsWithoutTracker(t *testing.T) {
+	svc := newQueueService(nil, nil)
+	ctx := context.Background()
+	checks := map[string]error{}
+	_, checks["enqueue"] = svc.EnqueueCall(ctx, "q1", "c1", "")
+	_, checks["position"] = svc.GetQueuePosition(ctx, "q1", "c1")
+	_, checks["dequeue"] = svc.DequeueCall(ctx, "q1", "c1", "a1")
+	_, checks["stats"] = svc.GetQueueStats(ctx, "q1")
+	for name, err := range checks {
+		if !errors.Is(err, ErrNotConfigured) {
+			t.Errorf("%s: err = %v, want ErrNotConfigured", name, err)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+// sentiric-dialplan-service/internal/service/dialplan/queue_engine.go
+package dialplan
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/cache"
+	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
+)
+
+// Kuyruk yönlendirme stratejileri (queue.routing_strategy).
+const (
+	QueueStrategyRingAll     = "ring_all"     // Müsait tüm ajanlar aynı anda çaldırılır
+	QueueStrategyRoundRobin  = "round_robin"  // Üyeler sırayla; imleç tüm replikalarda ortaktır
+	QueueStrategyLongestIdle = "longest_idle" // En uzun süredir boşta olan ajan önce
+	QueueStrategyFewestCalls = "fewest_calls" // Oturumda en az çağrı alan ajan önce
+	QueueStrategyRandom      = "random"
+)
+
+var queueStrategies = []string{
+	QueueStrategyRingAll, QueueStrategyRoundRobin, QueueStrategyLongestIdle, QueueStrategyFewestCalls, QueueStrategyRandom,
+}
+
+// Ajan durumları. Yalnızca "available" ajanlar aday olur; durum bildirmemiş ajan çevrimdışı sayılır.
+// "reserved" yalnızca seçim sırasında atanır (SetAgentStatus ile bildirilemez); süresi dolan rezervasyon
+// yeniden müsait sayılır.
+const (
+	AgentStatusAvailable = "available"
+	AgentStatusBusy      = "busy"
+	AgentStatusPaused    = "paused"
+	AgentStatusOffline   = "offline"
+	AgentStatusReserved  = "reserved"
+)
+
+var agentStatuses = []string{AgentStatusAvailable, AgentStatusBusy, AgentStatusPaused, AgentStatusOffline}
+
+// AgentStateStore: Ajan durumları ve round-robin imleçleri (örn: Redis). Replikalar arası
+// tutarlılık için durum serviste değil bu depoda tutulur.
+type AgentStateStore interface {
+	SetAgentStatus(ctx context.Context, agentID, status string) (cache.AgentState, error)
+	AgentStates(ctx context.Context, agentIDs []string) (map[string]cache.AgentState, error)
+	NextRoundRobin(ctx context.Context, queueID string) (int64, error)
+	ClaimAgent(ctx context.Context, agentID string, ttl time.Duration) (bool, error)
+}
+
+// errAgentStateUnavailable: Ajan durum deposu olmadan durum bildirilemez; gRPC'de FailedPrecondition döner.
+var errAgentStateUnavailable = fmt.Errorf("%w: ajan durum deposu", ErrNotConfigured)
+
+// WithAgentStateStore: Kuyruk motorunun ajan durum deposunu ayarlar.
+func WithAgentStateStore(store AgentStateStore) Option {
+	return func(s *Service) {
+		s.agentStates = store
+	}
+}
+
+// queueCandidate: Seçim sırasında bir kuyruk üyesi ve anlık durumu.
+type queueCandidate struct {
+	agentID string
+	state   cache.AgentState
+}
+
+func (c queueCandidate) proto() *dialplanv1.AgentState {
+	a := &dialplanv1.AgentState{AgentId: c.agentID, Status: c.state.Status, CallsHandled: int32(c.state.Calls)}
+	if a.Status == "" {
+		a.Status = AgentStatusOffline
+	}
+	if !c.state.IdleSince.IsZero() {
+		a.IdleSince = c.state.IdleSince.UTC().Format(time.RFC3339)
+	}
+	return a
+}
+
+// available: Ajan now anında seçilebilir mi? Süresi dolmuş rezervasyon müsait sayılır.
+func (c queueCandidate) available(now time.Time) bool {
+	switch c.state.Status {
+	case AgentStatusAvailable:
+		return true
+	case AgentStatusReserved:
+		return !c.state.ReservedUntil.After(now)
+	}
+	return false
+}
+
+// orderCandidates: Üyeleri (agent_id sıralı) stratejiye göre sıralar ve now anında müsait olanları döner.
+// Round-robin'de sıra, imlecin gösterdiği üyeden başlayarak tüm üye listesi üzerinde döner;
+// böylece müsaitlik değişse de ajanların sırası kaymaz. cursor yalnızca round-robin'de kullanılır.
+func orderCandidates(strategy string, members []queueCandidate, cursor int64, now time.Time) []queueCandidate {
+	if strategy == QueueStrategyRoundRobin && len(members) > 0 {
+		start := int((cursor - 1) % int64(len(members)))
+		if start < 0 {
+			start += len(members)
+		}
+		members = append(append([]queueCandidate(nil), members[start:]...), members[:start]...)
+	}
+
+	available := make([]queueCandidate, 0, len(members))
+	for _, m := range members {
+		if m.available(now) {
+			available = append(available, m)
+		}
+	}
+
+	switch strategy {
+	case QueueStrategyLongestIdle:
+		sort.SliceStable(available, func(i, j int) bool {
+			return available[i].state.IdleSince.Before(available[j].state.IdleSince)
+		})
+	case QueueStrategyFewestCalls:
+		sort.SliceStable(available, func(i, j int) bool {
+			a, b := available[i].state, available[j].state
+			if a.Calls != b.Calls {
+				return a.Calls < b.Calls
+			}
+			return a.IdleSince.Before(b.IdleSince)
+		})
+	case QueueStrategyRandom:
+		rand.Shuffle(len(available), func(i, j int) { available[i], available[j] = available[j], available[i] })
+	}
+	return available
+}
+
+// normalizeQueueStrategy: "Ring-All" gibi yazımları kanonik ada çevirir; boş strateji ring_all'dur.
+func normalizeQueueStrategy(strategy string) string {
+	strategy = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(strategy)), "-", "_")
+	if strategy == "" {
+		return QueueStrategyRingAll
+	}
+	return strategy
+}
+
+// validateQueue: Kuyruk kaydını doğrular ve stratejiyi kanonik ada çevirir.
+func validateQueue(q *dialplanv1.Queue) error {
+	var v violations
+	if q == nil {
+		v.add("queue", "queue zorunludur")
+		return v.err()
+	}
+	if strings.TrimSpace(q.Name) == "" {
+		v.add("queue.name", "name zorunludur")
+	}
+	q.RoutingStrategy = normalizeQueueStrategy(q.RoutingStrategy)
+	if !slices.Contains(queueStrategies, q.RoutingStrategy) {
+		v.add("queue.routing_strategy", "geçersiz strateji %q (beklenen: %s)", q.RoutingStrategy, strings.Join(queueStrategies, ", "))
+	}
+	if q.MaxWaitTimeSeconds < 0 {
+		v.add("queue.max_wait_time_seconds", "negatif olamaz")
+	}
+	if q.SkillRelaxSeconds < 0 {
+		v.add("queue.skill_relax_seconds", "negatif olamaz")
+	}
+	validateSkillRequirements("queue.skill_requirements", q.SkillRequirements, &v)
+	return v.err()
+}
+
+// queueCandidates: Kuyruğun üyelerini anlık durumlarıyla birlikte (agent_id sıralı) yükler.
+func (s *Service) queueCandidates(ctx context.Context, queueID string) ([]queueCandidate, error) {
+	members, err := s.repo.ListQueueMembers(ctx, queueID)
+	if err != nil {
+		return nil, resourceErr(err, ResourceQueue, queueID)
+	}
+	ids := make([]string, len(members))
+	for i, m := range members {
+		ids[i] = m.AgentId
+	}
+	states := map[string]cache.AgentState{}
+	if s.agentStates != nil && len(ids) > 0 {
+		if states, err = s.agentStates.AgentStates(ctx, ids); err != nil {
+			return nil, err
+		}
+	}
+	out := make([]queueCandidate, len(ids))
+	for i, id := range ids {
+		out[i] = queueCandidate{agentID: id, state: states[id]}
+	}
+	return out, nil
+}
+
+// claimFirst: Adayları sırayla rezerve etmeyi dener ve rezerve edilen ajandan başlayan listeyi döner;
+// başka bir replikanın arada aldığı ajanlar listeden düşer. Hiçbiri alınamazsa liste boştur.
+func (s *Service) claimFirst(ctx context.Context, ordered []queueCandidate) ([]queueCandidate, error) {
+	if s.agentStates == nil {
+		return ordered, nil
+	}
+	for i, c := range ordered {
+		ok, err := s.agentStates.ClaimAgent(ctx, c.agentID, cache.AgentReservationTTL)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			ordered[i].state.Status = AgentStatusReserved
+			return ordered[i:], nil
+		}
+	}
+	return nil, nil
+}
+
+// SelectQueueAgent: Kuyruğun stratejisine göre müsait ajanları sıralı aday listesi olarak döner;
+// AgentId ilk adaydır. ring_all'da tüm adaylar aynı anda çaldırılmalıdır. Müsait ajan yoksa liste boştur.
+//
+// ring_all dışındaki stratejilerde AgentId atomik olarak rezerve edilir; böylece eşzamanlı seçimler
+// (farklı replikalar dahil) aynı ajanı döndürmez. Çağıran taraf bağlantı kurulunca ajanı "busy",
+// kurulamazsa "available" bildirmelidir; bildirilmeyen rezervasyon AgentReservationTTL sonunda düşer.
+// ring_all'da rezervasyon yapılmaz: ilk cevaplayan ajan "busy" bildirir, diğerlerinin çalması kesilir.
+//
+// Kuyruk ve istek beceri gereksinimleri birleştirilir; bekleme süresi arttıkça kuyruğun skill_relax_seconds
+// ayarına göre gevşetilir. preferred_skills (örn: arayanın dili) adayı elemez; sahip olan ajanlar öne alınır.
+// Gereksinim veya tercih varsa adaylar beceri eşleşmesine göre, eşitlikte strateji sırasıyla dizilir.
+func (s *Service) SelectQueueAgent(ctx context.Context, req *dialplanv1.SelectQueueAgentRequest) (*dialplanv1.SelectQueueAgentResponse, error) {
+	l := logger.ContextLogger(ctx, s.baseLog)
+
+	queueID := req.QueueId
+	var v violations
+	validateSkillRequirements("skills", req.Skills, &v)
+	if req.WaitSeconds < 0 {
+		v.add("wait_seconds", "negatif olamaz")
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	queue, err := s.repo.GetQueue(ctx, queueID)
+	if err != nil {
+		return nil, resourceErr(err, ResourceQueue, queueID)
+	}
+	members, err := s.queueCandidates(ctx, queueID)
+	if err != nil {
+		return nil, err
+	}
+
+	strategy := normalizeQueueStrategy(queue.RoutingStrategy)
+	var cursor int64
+	if strategy == QueueStrategyRoundRobin && s.agentStates != nil && len(members) > 0 {
+		if cursor, err = s.agentStates.NextRoundRobin(ctx, queueID); err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &dialplanv1.SelectQueueAgentResponse{QueueId: queueID, Strategy: strategy}
+	ordered := orderCandidates(strategy, members, cursor, s.clock.Now())
+	all := mergeRequirements(queue.SkillRequirements, req.Skills)
+	preferred := normalizeSkills(req.PreferredSkills)
+	if (len(all) > 0 || len(preferred) > 0) && len(ordered) > 0 {
+		skills, err := s.agentSkillMap(ctx, ordered)
+		if err != nil {
+			return nil, resourceErr(err, ResourceAgentSkill, queueID)
+		}
+		resp.AppliedRequirements = relaxRequirements(all, queue.SkillRelaxSeconds, req.WaitSeconds)
+		ordered = rankBySkills(ordered, skills, resp.AppliedRequirements, all, preferred)
+	}
+	if strategy != QueueStrategyRingAll {
+		if ordered, err = s.claimFirst(ctx, ordered); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range ordered {
+		resp.Candidates = append(resp.Candidates, c.proto())
+	}
+	if len(resp.Candidates) > 0 {
+		resp.AgentId = resp.Candidates[0].AgentId
+	}
+
+	l.Debug().
+		Str("event", logger.EventQueueAgentSelected).
+		Str("queue_id", queueID).
+		Str("strategy", strategy).
+		Str("agent_id", resp.AgentId).
+		Int("candidates", len(resp.Candidates)).
+		Int("required_skills", len(resp.AppliedRequirements)).
+		Msg("Kuyruk için ajan seçildi.")
+	return resp, nil
+}
+
+// SetAgentStatus: Ajanın durumunu günceller (available, busy, paused, offline).
+func (s *Service) SetAgentStatus(ctx context.Context, agentID, status string) (*dialplanv1.AgentState, error) {
+	var v violations
+	if strings.TrimSpace(agentID) == "" {
+		v.add("agent_id", "agent_id zorunludur")
+	}
+	status = strings.ToLower(strings.TrimSpace(status))
+	if !slices.Contains(agentStatuses, status) {
+		v.add("status", "geçersiz durum %q (beklenen: %s)", status, strings.Join(agentStatuses, ", "))
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	if s.agentStates == nil {
+		return nil, errAgentStateUnavailable
+	}
+	state, err := s.agentStates.SetAgentStatus(ctx, agentID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	l := logger.ContextLogger(ctx, s.baseLog)
+	if status != AgentStatusBusy {
+		s.completeAgentCall(ctx, agentID, l)
+	}
+	l.Info().
+		Str("event", logger.EventAgentStatusChanged).
+		Str("agent_id", agentID).
+		Str("status", status).
+		Msg("Ajan durumu güncellendi.")
+	return queueCandidate{agentID: agentID, state: state}.proto(), nil
+}
+
+// AddQueueMember: Ajanı kuyruğa üye yapar.
+func (s *Service) AddQueueMember(ctx context.Context, m *dialplanv1.QueueMember) error {
+	var v violations
+	switch {
+	case m == nil:
+		v.add("member", "member zorunludur")
+	default:
+		if strings.TrimSpace(m.QueueId) == "" {
+			v.add("member.queue_id", "queue_id zorunludur")
+		}
+		if strings.TrimSpace(m.AgentId) == "" {
+			v.add("member.agent_id", "agent_id zorunludur")
+		}
+	}
+	if err := v.err(); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetQueue(ctx, m.QueueId); err != nil {
+		return resourceErr(err, ResourceQueue, m.QueueId)
+	}
+	return resourceErr(s.repo.AddQueueMember(ctx, m), ResourceQueueMember, m.QueueId+"/"+m.AgentId)
+}
+
+func (s *Service) RemoveQueueMember(ctx context.Context, queueID, agentID string) error {
+	rows, err := s.repo.RemoveQueueMember(ctx, queueID, agentID)
+	return affected(rows, err, ResourceQueueMember, queueID+"/"+agentID)
+}
+
+// ListQueueMembers: Kuyruk üyelerini anlık durumlarıyla döner.
+func (s *Service) ListQueueMembers(ctx context.Context, queueID string) ([]*dialplanv1.QueueMember, error) {
+	if _, err := s.repo.GetQueue(ctx, queueID); err != nil {
+		return nil, resourceErr(err, ResourceQueue, queueID)
+	}
+	members, err := s.queueCandidates(ctx, queueID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*dialplanv1.QueueMember, len(members))
+	for i, m := range members {
+		out[i] = &dialplanv1.QueueMember{QueueId: queueID, AgentId: m.agentID, State: m.proto()}
+	}
+	return out, nil
+}
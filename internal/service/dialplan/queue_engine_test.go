@@ -0,0 +1,193 @@
+package dialplan
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sentiric/sentiric-dialplan-service/internal/cache"
+)
+
+// fakeAgentStore: Ajan durumlarını bellekte tutar; taken içindeki ajanlar başka bir replikaca
+// rezerve edilmiş sayılır ve ClaimAgent false döner.
+type fakeAgentStore struct {
+	mu       sync.Mutex
+	states   map[string]cache.AgentState
+	taken    map[string]bool
+	claimErr error
+	claims   []string
+	cursor   int64
+}
+
+func (f *fakeAgentStore) SetAgentStatus(_ context.Context, agentID, status string) (cache.AgentState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st := f.states[agentID]
+	st.Status = status
+	f.states[agentID] = st
+	return st, nil
+}
+
+func (f *fakeAgentStore) AgentStates(_ context.Context, agentIDs []string) (map[string]cache.AgentState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make(map[string]cache.AgentState, len(agentIDs))
+	for _, id := range agentIDs {
+		if st, ok := f.states[id]; ok {
+			out[id] = st
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAgentStore) NextRoundRobin(context.Context, string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cursor++
+	return f.cursor, nil
+}
+
+func (f *fakeAgentStore) ClaimAgent(_ context.Context, agentID string, _ time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.claims = append(f.claims, agentID)
+	if f.claimErr != nil {
+		return false, f.claimErr
+	}
+	if f.taken[agentID] {
+		return false, nil
+	}
+	f.taken[agentID] = true
+	return true, nil
+}
+
+func candidateIDs(cs []queueCandidate) []string {
+	ids := make([]string, len(cs))
+	for i, c := range cs {
+		ids[i] = c.agentID
+	}
+	return ids
+}
+
+func TestOrderCandidates(t *testing.T) {
+	now := mustTime(t, "2026-01-05T10:30:00Z")
+	at := func(s string) time.Time { return mustTime(t, "2026-01-05T"+s+":00Z") }
+	members := []queueCandidate{
+		{agentID: "a", state: cache.AgentState{Status: AgentStatusAvailable, IdleSince: at("10:00"), Calls: 5}},
+		{agentID: "b", state: cache.AgentState{Status: AgentStatusBusy, IdleSince: at("08:00")}},
+		{agentID: "c", state: cache.AgentState{Status: AgentStatusAvailable, IdleSince: at("09:00"), Calls: 2}},
+		// Süresi dolmuş rezervasyon yeniden müsait sayılır.
+		{agentID: "d", state: cache.AgentState{Status: AgentStatusReserved, IdleSince: at("09:30"), Calls: 2, ReservedUntil: at("10:29")}},
+		{agentID: "e", state: cache.AgentState{Status: AgentStatusReserved, IdleSince: at("07:00"), ReservedUntil: at("10:31")}},
+		{agentID: "f"}, // durum bildirmemiş => çevrimdışı
+		{agentID: "g", state: cache.AgentState{Status: AgentStatusPaused, IdleSince: at("06:00")}},
+	}
+
+	tests := []struct {
+		name     string
+		strategy string
+		cursor   int64
+		want     []string
+	}{
+		{"ring all keeps member order", QueueStrategyRingAll, 0, []string{"a", "c", "d"}},
+		{"round robin first", QueueStrategyRoundRobin, 1, []string{"a", "c", "d"}},
+		{"round robin rotates over all members", QueueStrategyRoundRobin, 3, []string{"c", "d", "a"}},
+		{"round robin skips unavailable start", QueueStrategyRoundRobin, 2, []string{"c", "d", "a"}},
+		{"round robin wraps", QueueStrategyRoundRobin, 10, []string{"c", "d", "a"}},
+		{"round robin zero cursor", QueueStrategyRoundRobin, 0, []string{"a", "c", "d"}},
+		{"longest idle", QueueStrategyLongestIdle, 0, []string{"c", "d", "a"}},
+		{"fewest calls ties broken by idle", QueueStrategyFewestCalls, 0, []string{"c", "d", "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := slices.Clone(members)
+			got := candidateIDs(orderCandidates(tt.strategy, in, tt.cursor, now))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("orderCandidates(%s, cursor=%d) = %v, want %v", tt.strategy, tt.cursor, got, tt.want)
+			}
+			if !reflect.DeepEqual(in, members) {
+				t.Error("üye listesi yerinde değiştirilmemeli")
+			}
+		})
+	}
+
+	t.Run("random returns available members", func(t *testing.T) {
+		got := candidateIDs(orderCandidates(QueueStrategyRandom, slices.Clone(members), 0, now))
+		slices.Sort(got)
+		if want := []string{"a", "c", "d"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("random = %v, want %v (herhangi bir sırada)", got, want)
+		}
+	})
+	t.Run("no members", func(t *testing.T) {
+		if got := orderCandidates(QueueStrategyRoundRobin, nil, 5, now); len(got) != 0 {
+			t.Errorf("boş üye listesi = %v", got)
+		}
+	})
+}
+
+func TestClaimFirst(t *testing.T) {
+	ordered := []queueCandidate{
+		{agentID: "a", state: cache.AgentState{Status: AgentStatusAvailable}},
+		{agentID: "b", state: cache.AgentState{Status: AgentStatusAvailable}},
+		{agentID: "c", state: cache.AgentState{Status: AgentStatusAvailable}},
+	}
+	tests := []struct {
+		name       string
+		taken      []string
+		claimErr   error
+		want       []string
+		wantClaims []string
+		wantErr    bool
+	}{
+		{name: "first free", want: []string{"a", "b", "c"}, wantClaims: []string{"a"}},
+		{name: "skips agents taken by another replica", taken: []string{"a", "b"}, want: []string{"c"}, wantClaims: []string{"a", "b", "c"}},
+		{name: "all taken", taken: []string{"a", "b", "c"}, want: []string{}, wantClaims: []string{"a", "b", "c"}},
+		{name: "store error", claimErr: errors.New("redis down"), wantErr: true, wantClaims: []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeAgentStore{taken: map[string]bool{}, claimErr: tt.claimErr}
+			for _, id := range tt.taken {
+				store.taken[id] = true
+			}
+			svc := NewService(nil, nil, nil, zerolog.Nop(), WithAgentStateStore(store))
+
+			got, err := svc.claimFirst(context.Background(), slices.Clone(ordered))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(store.claims, tt.wantClaims) {
+				t.Errorf("ClaimAgent çağrıları = %v, want %v", store.claims, tt.wantClaims)
+			}
+			if tt.wantErr {
+				return
+			}
+			if ids := candidateIDs(got); !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("claimFirst = %v, want %v", ids, tt.want)
+			}
+			if len(got) > 0 && got[0].state.Status != AgentStatusReserved {
+				t.Errorf("ilk aday durumu = %q, want %q", got[0].state.Status, AgentStatusReserved)
+			}
+		})
+	}
+
+	t.Run("no store", func(t *testing.T) {
+		svc := NewService(nil, nil, nil, zerolog.Nop())
+		got, err := svc.claimFirst(context.Background(), slices.Clone(ordered))
+		if err != nil || !reflect.DeepEqual(candidateIDs(got), []string{"a", "b", "c"}) {
+			t.Errorf("depo yokken liste olduğu gibi dönmeli: %v, %v", candidateIDs(got), err)
+		}
+	})
+}
+
+func TestSetAgentStatusWithoutStore(t *testing.T) {
+	svc := NewService(nil, nil, nil, zerolog.Nop())
+	if _, err := svc.SetAgentStatus(context.Background(), "a", AgentStatusAvailable); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("err = %v, want ErrNotConfigured", err)
+	}
+}
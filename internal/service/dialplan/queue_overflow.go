@@ -0,0 +1,262 @@
+// sentiric-dialplan-service/internal/service/dialplan/queue_overflow.go
+package dialplan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
+)
+
+// Taşma (overflow) nedenleri.
+const (
+	OverflowReasonMaxWait  = "max_wait_exceeded"
+	OverflowReasonInactive = "queue_inactive"
+	OverflowReasonNoAgents = "no_agents"
+)
+
+// queue.fallback_action hedef türleri: "queue:<queue_id>", "dialplan:<dialplan_id>", "voicemail[:<mailbox>]".
+// Boş fallback_action sistem anonsu ile sonuçlanır.
+const (
+	FallbackQueue     = "queue"
+	FallbackDialplan  = "dialplan"
+	FallbackVoicemail = "voicemail"
+)
+
+// Taşma aksiyonları.
+const (
+	ActionRouteToQueue           = "ROUTE_TO_QUEUE"
+	ActionVoicemail              = "VOICEMAIL"
+	AnnouncementQueueUnavailable = "ANNOUNCE_QUEUE_UNAVAILABLE"
+)
+
+// maxFallbackChain: Bir taşmada izlenecek azami fallback adımı.
+const maxFallbackChain = 8
+
+type fallbackTarget struct {
+	kind string
+	ref  string
+}
+
+// parseFallbackAction: "tür[:referans]" biçimindeki fallback_action'ı çözer; boş değer sıfır hedef döner.
+func parseFallbackAction(raw string) (fallbackTarget, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return fallbackTarget{}, nil
+	}
+	kind, ref, _ := strings.Cut(raw, ":")
+	t := fallbackTarget{kind: strings.ToLower(strings.TrimSpace(kind)), ref: strings.TrimSpace(ref)}
+	switch t.kind {
+	case FallbackQueue, FallbackDialplan:
+		if t.ref == "" {
+			return t, fmt.Errorf("%s hedefi için ID zorunludur (örn: %s:<id>)", t.kind, t.kind)
+		}
+	case FallbackVoicemail:
+	default:
+		return t, fmt.Errorf("geçersiz fallback türü %q (beklenen: %s:<id>, %s:<id>, %s[:<mailbox>])",
+			t.kind, FallbackQueue, FallbackDialplan, FallbackVoicemail)
+	}
+	return t, nil
+}
+
+func queueAction(queueID string) *dialplanv1.DialplanAction {
+	return &dialplanv1.DialplanAction{
+		Action:     ActionRouteToQueue,
+		Type:       MapStringToActionType(ActionRouteToQueue),
+		ActionData: map[string]string{ActionDataQueueID: queueID},
+	}
+}
+
+func queueUnavailableAction() *dialplanv1.DialplanAction {
+	return &dialplanv1.DialplanAction{
+		Action:     ActionPlayAnnouncement,
+		Type:       dialplanv1.ActionType_ACTION_TYPE_PLAY_STATIC_ANNOUNCEMENT,
+		ActionData: map[string]string{"announcement_id": AnnouncementQueueUnavailable},
+	}
+}
+
+// queueUnavailable: Kuyruk çağrı kabul edemiyorsa nedenini, edebiliyorsa "" döner.
+// [ARCH-COMPLIANCE] Ajan durumları okunamazsa kuyruk kullanılabilir sayılır (fail-open).
+func (s *Service) queueUnavailable(ctx context.Context, q *dialplanv1.Queue, l zerolog.Logger) string {
+	if !q.IsActive {
+		return OverflowReasonInactive
+	}
+	agents, err := s.loggedInAgents(ctx, q.Id)
+	if err != nil {
+		l.Warn().Err(err).
+			Str("event", logger.EventQueueAgentStateFailed).
+			Str("queue_id", q.Id).
+			Msg("Kuyruk ajan durumları okunamadı, kuyruk kullanılabilir sayılıyor.")
+		return ""
+	}
+	if agents == 0 {
+		return OverflowReasonNoAgents
+	}
+	return ""
+}
+
+// queueOverflow: Taşan kuyruğun fallback zincirini izler ve çağrının gideceği aksiyonu döner.
+// Zincirdeki kuyruk kullanılabilirse ona yönlendirilir; değilse o kuyruğun fallback'i izlenir.
+// Hedefi kuyruk olan dialplan'lar da zincirin parçası sayılır. Döngü, eksik hedef veya çok uzun zincirde
+// sistem anonsu döner.
+func (s *Service) queueOverflow(ctx context.Context, queue *dialplanv1.Queue, reason string, l zerolog.Logger) *dialplanv1.QueueOverflow {
+	overflow := &dialplanv1.QueueOverflow{Reason: reason, Chain: []string{queue.Id}}
+	visited := map[string]bool{queue.Id: true}
+	current := queue
+
+	fail := func(event, msg string, err error) *dialplanv1.QueueOverflow {
+		l.Warn().Err(err).
+			Str("event", event).
+			Strs("chain", overflow.Chain).
+			Str("fallback_action", current.FallbackAction).
+			Msg(msg)
+		overflow.Action = queueUnavailableAction()
+		return overflow
+	}
+
+	for range maxFallbackChain {
+		target, err := parseFallbackAction(current.FallbackAction)
+		if err != nil {
+			return fail(logger.EventQueueFallbackInvalid, "Geçersiz kuyruk fallback'i, sistem anonsu çalınıyor.", err)
+		}
+
+		var next string
+		var planAction *dialplanv1.DialplanAction
+		switch target.kind {
+		case "":
+			overflow.Action = queueUnavailableAction()
+			return overflow
+		case FallbackVoicemail:
+			overflow.Action = &dialplanv1.DialplanAction{
+				Action:     ActionVoicemail,
+				Type:       MapStringToActionType(ActionVoicemail),
+				ActionData: map[string]string{"mailbox": target.ref, "origin_queue_id": current.Id},
+			}
+			return overflow
+		case FallbackDialplan:
+			plan, err := s.repo.FindDialplanByID(ctx, target.ref)
+			if err != nil {
+				return fail(logger.EventQueueFallbackInvalid, "Kuyruk fallback dialplan'ı yüklenemedi, sistem anonsu çalınıyor.", err)
+			}
+			if next = plan.GetAction().GetActionData()[ActionDataQueueID]; next == "" {
+				overflow.Action = plan.Action
+				return overflow
+			}
+			planAction = plan.Action
+		case FallbackQueue:
+			next = target.ref
+		}
+
+		if visited[next] {
+			overflow.Chain = append(overflow.Chain, next)
+			return fail(logger.EventQueueFallbackCycle, "Kuyruk fallback zincirinde döngü, sistem anonsu çalınıyor.", nil)
+		}
+		visited[next] = true
+		overflow.Chain = append(overflow.Chain, next)
+
+		nextQueue, err := s.repo.GetQueue(ctx, next)
+		if err != nil {
+			return fail(logger.EventQueueFallbackInvalid, "Kuyruk fallback hedefi yüklenemedi, sistem anonsu çalınıyor.", err)
+		}
+		if s.queueUnavailable(ctx, nextQueue, l) == "" {
+			overflow.Action = queueAction(next)
+			if planAction != nil {
+				overflow.Action = planAction
+			}
+			return overflow
+		}
+		current = nextQueue
+	}
+	return fail(logger.EventQueueFallbackCycle, "Kuyruk fallback zinciri çok uzun, sistem anonsu çalınıyor.", nil)
+}
+
+// checkFallbackChain: Kuyruğun fallback_action'ını doğrular: ilk hedef mevcut olmalı ve zincir kuyruğun
+// kendisine geri dönmemelidir.
+func (s *Service) checkFallbackChain(ctx context.Context, q *dialplanv1.Queue) error {
+	var v violations
+	chain := []string{q.Id}
+	visited := map[string]bool{q.Id: true}
+	fallback := q.FallbackAction
+
+	for hop := 0; hop < maxFallbackChain; hop++ {
+		target, err := parseFallbackAction(fallback)
+		if err != nil {
+			if hop == 0 {
+				v.add("queue.fallback_action", "%v", err)
+			}
+			break
+		}
+		next := target.ref
+		if target.kind == FallbackDialplan {
+			plan, err := s.repo.FindDialplanByID(ctx, target.ref)
+			if err != nil {
+				if !errors.Is(err, ErrNotFound) {
+					return resourceErr(err, ResourceDialplan, target.ref)
+				}
+				if hop == 0 {
+					v.add("queue.fallback_action", "dialplan bulunamadı: %s", target.ref)
+				}
+				break
+			}
+			next = plan.GetAction().GetActionData()[ActionDataQueueID]
+		} else if target.kind != FallbackQueue {
+			break
+		}
+		if next == "" {
+			break
+		}
+
+		chain = append(chain, next)
+		if visited[next] {
+			v.add("queue.fallback_action", "fallback zincirinde döngü: %s", strings.Join(chain, " -> "))
+			break
+		}
+		visited[next] = true
+
+		nextQueue, err := s.repo.GetQueue(ctx, next)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				return resourceErr(err, ResourceQueue, next)
+			}
+			if hop == 0 {
+				v.add("queue.fallback_action", "kuyruk bulunamadı: %s", next)
+			}
+			break
+		}
+		fallback = nextQueue.FallbackAction
+	}
+	return v.err()
+}
+
+// applyQueueOverflow: Kuyruğa yönlendiren aksiyonun kuyruğu çağrı kabul edemiyorsa (pasif veya oturum açmış
+// ajan yok) aksiyonu fallback zincirinin sonucuyla değiştirir.
+func (s *Service) applyQueueOverflow(ctx context.Context, action *dialplanv1.DialplanAction, trace *DecisionTrace, l zerolog.Logger) *dialplanv1.DialplanAction {
+	queueID := action.GetActionData()[ActionDataQueueID]
+	if queueID == "" {
+		return action
+	}
+	queue, err := s.repo.GetQueue(ctx, queueID)
+	if err != nil {
+		trace.record(TraceStepQueueCheck, "load_failed", "queue_id", queueID, "error", err.Error())
+		return action
+	}
+	reason := s.queueUnavailable(ctx, queue, l)
+	if reason == "" {
+		trace.record(TraceStepQueueCheck, "available", "queue_id", queueID)
+		return action
+	}
+
+	overflow := s.queueOverflow(ctx, queue, reason, l)
+	trace.record(TraceStepQueueCheck, "overflow", "queue_id", queueID, "reason", reason,
+		"chain", strings.Join(overflow.Chain, ","), "action", overflow.Action.GetAction())
+	l.Info().
+		Str("event", logger.EventQueueOverflow).
+		Str("queue_id", queueID).
+		Str("reason", reason).
+		Msg("Kuyruk çağrı kabul edemiyor, fallback aksiyonu uygulanıyor.")
+	return overflow.Action
+}
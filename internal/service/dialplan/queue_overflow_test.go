@@ -0,0 +1,263 @@
+package dialplan
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/cache"
+)
+
+// newOverflowService: fallbacks (queue_id -> fallback_action) kuyruklarını kurar. available içindeki kuyruklar
+// aktif ve oturum açmış bir ajana sahiptir; diğerleri ajansızdır.
+func newOverflowService(fallbacks map[string]string, available ...string) *Service {
+	repo := &queueRepo{
+		queues:  map[string]*dialplanv1.Queue{},
+		members: map[string][]string{},
+		dialplans: map[string]*dialplanv1.Dialplan{
+			"DP_Q2":    {Id: "DP_Q2", TenantId: "t1", Action: queueAction("q2")},
+			"DP_Q1":    {Id: "DP_Q1", TenantId: "t1", Action: queueAction("q1")},
+			"DP_GREET": {Id: "DP_GREET", TenantId: "t1", Action: &dialplanv1.DialplanAction{Action: ActionPlayAnnouncement}},
+		},
+	}
+	for id, fallback := range fallbacks {
+		repo.queues[id] = &dialplanv1.Queue{Id: id, IsActive: true, FallbackAction: fallback, MaxWaitTimeSeconds: 60}
+	}
+	for _, id := range available {
+		repo.members[id] = []string{"a1"}
+	}
+	store := &fakeAgentStore{states: map[string]cache.AgentState{"a1": {Status: AgentStatusAvailable}}, taken: map[string]bool{}}
+	return NewService(repo, nil, nil, zerolog.Nop(), WithAgentStateStore(store))
+}
+
+// linearChain: n0 -> n1 -> ... -> n<count> zincirini kurar.
+func linearChain(count int) map[string]string {
+	fallbacks := map[string]string{}
+	for i := 0; i < count; i++ {
+		fallbacks[fmt.Sprintf("n%d", i)] = fmt.Sprintf("queue:n%d", i+1)
+	}
+	fallbacks[fmt.Sprintf("n%d", count)] = ""
+	return fallbacks
+}
+
+func TestQueueOverflow(t *testing.T) {
+	tests := []struct {
+		name       string
+		start      string
+		fallbacks  map[string]string
+		available  []string
+		wantAction string
+		wantData   map[string]string
+		wantChain  []string
+	}{
+		{
+			name:       "no fallback",
+			start:      "q1",
+			fallbacks:  map[string]string{"q1": ""},
+			wantAction: ActionPlayAnnouncement,
+			wantData:   map[string]string{"announcement_id": AnnouncementQueueUnavailable},
+			wantChain:  []string{"q1"},
+		},
+		{
+			name:       "next queue available",
+			start:      "q1",
+			fallbacks:  map[string]string{"q1": "queue:q2", "q2": ""},
+			available:  []string{"q2"},
+			wantAction: ActionRouteToQueue,
+			wantData:   map[string]string{ActionDataQueueID: "q2"},
+			wantChain:  []string{"q1", "q2"},
+		},
+		{
+			name:       "chain skips unavailable queue",
+			start:      "q1",
+			fallbacks:  map[string]string{"q1": "queue:q2", "q2": "queue:q3", "q3": ""},
+			available:  []string{"q3"},
+			wantAction: ActionRouteToQueue,
+			wantData:   map[string]string{ActionDataQueueID: "q3"},
+			wantChain:  []string{"q1", "q2", "q3"},
+		},
+		{
+			name:       "chain ends in voicemail",
+			start:      "q1",
+			fallbacks:  map[string]string{"q1": "queue:q2", "q2": "Voicemail: 100"},
+			wantAction: ActionVoicemail,
+			wantData:   map[string]string{"mailbox": "100", "origin_queue_id": "q2"},
+			wantChain:  []string{"q1", "q2"},
+		},
+		{
+			name:       "cycle",
+			start:      "q1",
+			fallbacks:  map[string]string{"q1": "queue:q2", "q2": "queue:q1"},
+			wantAction: ActionPlayAnnouncement,
+			wantData:   map[string]string{"announcement_id": AnnouncementQueueUnavailable},
+			wantChain:  []string{"q1", "q2", "q1"},
+		},
+		{
+			name:       "dialplan fallback to available queue keeps dialplan action",
+			start:      "q1",
+			fallbacks:  map[string]string{"q1": "dialplan:DP_Q2", "q2": ""},
+			available:  []string{"q2"},
+			wantAction: ActionRouteToQueue,
+			wantData:   map[string]string{ActionDataQueueID: "q2"},
+			wantChain:  []string{"q1", "q2"},
+		},
+		{
+			name:       "dialplan fallback to unavailable queue follows its fallback",
+			start:      "q1",
+			fallbacks:  map[string]string{"q1": "dialplan:DP_Q2", "q2": "queue:q3", "q3": ""},
+			available:  []string{"q3"},
+			wantAction: ActionRouteToQueue,
+			wantData:   map[string]string{ActionDataQueueID: "q3"},
+			wantChain:  []string{"q1", "q2", "q3"},
+		},
+		{
+			name:       "dialplan fallback cycles back through queue",
+			start:      "q1",
+			fallbacks:  map[string]string{"q1": "queue:q2", "q2": "dialplan:DP_Q1"},
+			wantAction: ActionPlayAnnouncement,
+			wantData:   map[string]string{"announcement_id": AnnouncementQueueUnavailable},
+			wantChain:  []string{"q1", "q2", "q1"},
+		},
+		{
+			name:       "dialplan fallback without queue",
+			start:      "q1",
+			fallbacks:  map[string]string{"q1": "dialplan:DP_GREET"},
+			wantAction: ActionPlayAnnouncement,
+			wantChain:  []string{"q1"},
+		},
+		{
+			name:       "missing fallback queue",
+			start:      "q1",
+			fallbacks:  map[string]string{"q1": "queue:gone"},
+			wantAction: ActionPlayAnnouncement,
+			wantData:   map[string]string{"announcement_id": AnnouncementQueueUnavailable},
+			wantChain:  []string{"q1", "gone"},
+		},
+		{
+			name:       "invalid fallback",
+			start:      "q1",
+			fallbacks:  map[string]string{"q1": "transfer:100"},
+			wantAction: ActionPlayAnnouncement,
+			wantData:   map[string]string{"announcement_id": AnnouncementQueueUnavailable},
+			wantChain:  []string{"q1"},
+		},
+		{
+			name:       "chain of max length",
+			start:      "n0",
+			fallbacks:  linearChain(maxFallbackChain),
+			available:  []string{fmt.Sprintf("n%d", maxFallbackChain)},
+			wantAction: ActionRouteToQueue,
+			wantData:   map[string]string{ActionDataQueueID: fmt.Sprintf("n%d", maxFallbackChain)},
+			wantChain:  chainIDs(maxFallbackChain),
+		},
+		{
+			name:       "chain longer than max is cut off",
+			start:      "n0",
+			fallbacks:  linearChain(maxFallbackChain + 1),
+			available:  []string{fmt.Sprintf("n%d", maxFallbackChain+1)},
+			wantAction: ActionPlayAnnouncement,
+			wantData:   map[string]string{"announcement_id": AnnouncementQueueUnavailable},
+			wantChain:  chainIDs(maxFallbackChain),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newOverflowService(tt.fallbacks, tt.available...)
+			queue, _ := svc.repo.GetQueue(context.Background(), tt.start)
+
+			got := svc.queueOverflow(context.Background(), queue, OverflowReasonNoAgents, zerolog.Nop())
+			if got.Action == nil {
+				t.Fatal("Action boş")
+			}
+			if got.Reason != OverflowReasonNoAgents {
+				t.Errorf("Reason = %q", got.Reason)
+			}
+			if !reflect.DeepEqual(got.Chain, tt.wantChain) {
+				t.Errorf("Chain = %v, want %v", got.Chain, tt.wantChain)
+			}
+			if got.Action.GetAction() != tt.wantAction {
+				t.Errorf("Action = %q, want %q", got.Action.GetAction(), tt.wantAction)
+			}
+			if tt.wantData != nil && !reflect.DeepEqual(got.Action.GetActionData(), tt.wantData) {
+				t.Errorf("ActionData = %v, want %v", got.Action.GetActionData(), tt.wantData)
+			}
+		})
+	}
+}
+
+// chainIDs: n0..n<hops> kimlikleri.
+func chainIDs(hops int) []string {
+	ids := make([]string, hops+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("n%d", i)
+	}
+	return ids
+}
+
+func TestCheckFallbackChain(t *testing.T) {
+	tests := []struct {
+		name      string
+		queue     *dialplanv1.Queue
+		fallbacks map[string]string
+		wantErr   bool
+	}{
+		{name: "no fallback", queue: &dialplanv1.Queue{Id: "q1"}},
+		{name: "voicemail", queue: &dialplanv1.Queue{Id: "q1", FallbackAction: "voicemail"}},
+		{name: "existing queue", queue: &dialplanv1.Queue{Id: "q1", FallbackAction: "queue:q2"}, fallbacks: map[string]string{"q2": "queue:q3"}},
+		{name: "invalid format", queue: &dialplanv1.Queue{Id: "q1", FallbackAction: "queue:"}, wantErr: true},
+		{name: "missing queue", queue: &dialplanv1.Queue{Id: "q1", FallbackAction: "queue:gone"}, wantErr: true},
+		{name: "missing dialplan", queue: &dialplanv1.Queue{Id: "q1", FallbackAction: "dialplan:DP_GONE"}, wantErr: true},
+		{name: "self reference", queue: &dialplanv1.Queue{Id: "q1", FallbackAction: "queue:q1"}, wantErr: true},
+		{name: "indirect cycle", queue: &dialplanv1.Queue{Id: "q1", FallbackAction: "queue:q2"}, fallbacks: map[string]string{"q2": "queue:q3", "q3": "queue:q1"}, wantErr: true},
+		{name: "cycle through dialplan", queue: &dialplanv1.Queue{Id: "q1", FallbackAction: "queue:q2"}, fallbacks: map[string]string{"q2": "dialplan:DP_Q1"}, wantErr: true},
+		// Zincirin sonraki halkalarındaki eksik hedefler o kuyrukların kaydında doğrulanır.
+		{name: "missing target further down", queue: &dialplanv1.Queue{Id: "q1", FallbackAction: "queue:q2"}, fallbacks: map[string]string{"q2": "queue:gone"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newOverflowService(tt.fallbacks)
+			err := svc.checkFallbackChain(context.Background(), tt.queue)
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+				return
+			}
+			if fields := violationFields(t, err); !reflect.DeepEqual(fields, []string{"queue.fallback_action"}) {
+				t.Errorf("ihlal alanları = %v", fields)
+			}
+		})
+	}
+}
+
+func TestGetQueuePositionOverflow(t *testing.T) {
+	ctx := context.Background()
+	tracker := newMemQueueTracker()
+	svc := newQueueService(tracker, map[string]cache.AgentState{"a1": {Status: AgentStatusAvailable}})
+	if _, err := svc.EnqueueCall(ctx, "q1", "c1", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker.waited = 300 * time.Second // q1 max_wait_time_seconds
+	pos, err := svc.GetQueuePosition(ctx, "q1", "c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos.Position != 0 || pos.WaitSeconds != 300 || pos.Overflow == nil || pos.Overflow.Reason != OverflowReasonMaxWait {
+		t.Fatalf("GetQueuePosition = %+v", pos)
+	}
+	if pos.Overflow.Action.GetActionData()["announcement_id"] != AnnouncementQueueUnavailable {
+		t.Errorf("fallback'siz kuyruk sistem anonsu dönmeli: %v", pos.Overflow.Action)
+	}
+	stats, err := svc.GetQueueStats(ctx, "q1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Waiting != 0 || stats.Overflowed != 1 {
+		t.Errorf("taşan çağrı kuyruktan çıkmalı: Waiting = %d, Overflowed = %d", stats.Waiting, stats.Overflowed)
+	}
+}
@@ -0,0 +1,160 @@
+// sentiric-dialplan-service/internal/service/dialplan/queue_priority.go
+package dialplan
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	userv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/user/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
+)
+
+// Kuyruk öncelik sınıfları.
+const (
+	PriorityNormal    = "normal"
+	PriorityReturning = "returning"
+	PriorityHigh      = "high"
+	PriorityVIP       = "vip"
+)
+
+// priorityClasses: Öncelik sınıfları, düşükten yükseğe. boost, çağrının kuyrukta öne geçebileceği azami
+// süredir: çağrı kendisinden en fazla boost kadar önce gelmiş düşük sınıftaki çağrıların önüne geçer,
+// daha uzun bekleyenler sırasını korur (yaşlanma). Böylece yoğun VIP trafiğinde bile normal bir çağrı
+// en fazla VIP boost'u kadar geride kalır, aç kalmaz.
+var priorityClasses = []struct {
+	name  string
+	boost time.Duration
+}{
+	{PriorityNormal, 0},
+	{PriorityReturning, 30 * time.Second},
+	{PriorityHigh, 90 * time.Second},
+	{PriorityVIP, 3 * time.Minute},
+}
+
+// PriorityTagPrefix: Kullanıcı etiketlerinde öncelik sınıfı "priority:<sınıf>" biçimindedir (örn: "priority:vip").
+const PriorityTagPrefix = "priority:"
+
+// Kuyruğa yönlendiren aksiyonlara resolve sırasında eklenen öncelik verileri.
+const (
+	ActionDataPriority       = "priority"
+	ActionDataPrioritySource = "priority_source"
+)
+
+// Önceliğin kaynağı; karar izinde ve aksiyon verisinde kullanılır.
+const (
+	prioritySourceDefault    = "default"
+	prioritySourceCallerList = "caller_list"
+	prioritySourceRoute      = "route"
+	prioritySourceDialplan   = "dialplan"
+	prioritySourceUserType   = "user_type"
+	prioritySourceUserTag    = "user_tag"
+	prioritySourceReturning  = "returning_caller"
+)
+
+func normalizePriority(raw string) string {
+	return strings.ToLower(strings.TrimSpace(raw))
+}
+
+// priorityRank: Sınıfın sırası (normal = 0); tanımsız sınıfta false döner.
+func priorityRank(class string) (int, bool) {
+	for i, c := range priorityClasses {
+		if c.name == class {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// priorityBoost: Sınıfın kuyruk sırasındaki öne geçme süresi; tanımsız sınıf için 0.
+func priorityBoost(class string) time.Duration {
+	if i, ok := priorityRank(class); ok {
+		return priorityClasses[i].boost
+	}
+	return 0
+}
+
+func priorityClassNames() string {
+	names := make([]string, len(priorityClasses))
+	for i, c := range priorityClasses {
+		names[i] = c.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// validatePriority: Boş değer normal sınıftır; geçerli sınıfın normalize hali döner.
+func validatePriority(field, raw string, v *violations) string {
+	class := normalizePriority(raw)
+	if class == "" {
+		return PriorityNormal
+	}
+	if _, ok := priorityRank(class); !ok {
+		v.add(field, "geçersiz öncelik sınıfı %q (beklenen: %s)", raw, priorityClassNames())
+	}
+	return class
+}
+
+// priorityDecision: Adayların en yüksek sınıflısını tutar; eşitlikte ilk gelen kaynak kalır.
+type priorityDecision struct {
+	class  string
+	source string
+	rank   int
+}
+
+func (d *priorityDecision) consider(raw, source string) {
+	rank, ok := priorityRank(normalizePriority(raw))
+	if ok && rank > d.rank {
+		*d = priorityDecision{class: priorityClasses[rank].name, source: source, rank: rank}
+	}
+}
+
+// callerPriority: Arayanın öncelik sınıfını belirler. Kaynaklar: arayan taramasında uyan "priority" kayıtları
+// (listEntries), route.queue_priority, dialplan aksiyonundaki "priority", kullanıcı tipi, "priority:" etiketleri
+// ve kayıtlı (tekrar arayan) kullanıcı. En yüksek sınıf geçerlidir. Tarama başarısız olduysa listEntries boştur.
+func callerPriority(action *dialplanv1.DialplanAction, route *dialplanv1.InboundRoute, listEntries []*dialplanv1.CallerListEntry, user *userv1.User, returning bool) priorityDecision {
+	d := priorityDecision{class: PriorityNormal, source: prioritySourceDefault}
+
+	for _, e := range listEntries {
+		if e.ListType == CallerListPriority {
+			d.consider(e.Priority, prioritySourceCallerList)
+		}
+	}
+	d.consider(route.QueuePriority, prioritySourceRoute)
+	d.consider(action.GetActionData()[ActionDataPriority], prioritySourceDialplan)
+	d.consider(user.GetUserType(), prioritySourceUserType)
+	for _, tag := range user.GetTags() {
+		if class, ok := strings.CutPrefix(normalizePriority(tag), PriorityTagPrefix); ok {
+			d.consider(class, prioritySourceUserTag)
+		}
+	}
+	if returning {
+		d.consider(PriorityReturning, prioritySourceReturning)
+	}
+	return d
+}
+
+// applyQueuePriority: Kuyruğa yönlendiren aksiyona arayanın öncelik sınıfını ve kaynağını ekler.
+// listEntries, arayan taramasında uyan liste kayıtlarıdır. Kayıtlı plan değiştirilmez; kopya döner.
+func (s *Service) applyQueuePriority(action *dialplanv1.DialplanAction, route *dialplanv1.InboundRoute, listEntries []*dialplanv1.CallerListEntry, user *userv1.User, returning bool, trace *DecisionTrace, l zerolog.Logger) *dialplanv1.DialplanAction {
+	queueID := action.GetActionData()[ActionDataQueueID]
+	if queueID == "" {
+		return action
+	}
+	d := callerPriority(action, route, listEntries, user, returning)
+	trace.record(TraceStepQueuePriority, d.class, "queue_id", queueID, "source", d.source)
+	l.Debug().
+		Str("event", logger.EventQueuePriorityAssigned).
+		Str("queue_id", queueID).
+		Str("priority", d.class).
+		Str("source", d.source).
+		Msg("Kuyruk önceliği belirlendi.")
+
+	data := make(map[string]string, len(action.ActionData)+2)
+	for k, v := range action.ActionData {
+		data[k] = v
+	}
+	data[ActionDataPriority] = d.class
+	data[ActionDataPrioritySource] = d.source
+	return &dialplanv1.DialplanAction{Action: action.Action, Type: action.Type, ActionData: data}
+}
@@ -0,0 +1,265 @@
+// sentiric-dialplan-service/internal/service/dialplan/queue_skills.go
+package dialplan
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	userv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/user/v1"
+)
+
+// Beceri seviyeleri. Gereksinimde seviye 0 (veya boş), beceriye herhangi bir seviyede sahip olmayı ifade eder.
+const (
+	MinSkillLevel = 1
+	MaxSkillLevel = 10
+)
+
+// LanguageSkillPrefix: Dil becerileri "lang:<birincil dil kodu>" biçimindedir (örn: "lang:tr").
+const LanguageSkillPrefix = "lang:"
+
+// Kuyruğa yönlendiren dialplan aksiyonlarının veri anahtarları.
+const (
+	ActionDataQueueID = "queue_id"
+	// ActionDataRequiredSkills: Virgülle ayrılmış beceri adları; SelectQueueAgent isteğine MinLevel 1 ile taşınır.
+	ActionDataRequiredSkills = "required_skills"
+	// ActionDataPreferredSkills: Virgülle ayrılmış tercih edilen beceriler (örn: arayanın dili);
+	// SelectQueueAgent isteğinin preferred_skills alanına taşınır. Adayı elemez, yalnızca öne alır.
+	ActionDataPreferredSkills = "preferred_skills"
+)
+
+func normalizeSkill(skill string) string {
+	return strings.ToLower(strings.TrimSpace(skill))
+}
+
+// languageSkill: "tr-TR" gibi bir dil kodunun beceri adı ("lang:tr"); kod boşsa "".
+func languageSkill(code string) string {
+	code = normalizeSkill(code)
+	if i := strings.IndexAny(code, "-_"); i != -1 {
+		code = code[:i]
+	}
+	if code == "" {
+		return ""
+	}
+	return LanguageSkillPrefix + code
+}
+
+// withQueueSkills: Kuyruğa yönlendiren aksiyonlara arayanın dil becerisini tercih olarak ekler; dili konuşan
+// ajan yoksa çağrı yine de diğer ajanlara gider. Dil, eşleşen kullanıcının tercihinden, yoksa route'un
+// varsayılan dilinden türetilir. Zorunlu dil kuyruk (veya plan) gereksinimiyle tanımlanır; plan dili zaten
+// belirlemişse tercih eklenmez. Kayıtlı plan değiştirilmez; kopya döner.
+func withQueueSkills(action *dialplanv1.DialplanAction, user *userv1.User, route *dialplanv1.InboundRoute) *dialplanv1.DialplanAction {
+	if action == nil || action.ActionData[ActionDataQueueID] == "" {
+		return action
+	}
+	lang := user.GetPreferredLanguageCode()
+	if lang == "" && route != nil {
+		lang = route.DefaultLanguageCode
+	}
+	skill := languageSkill(lang)
+	if skill == "" {
+		return action
+	}
+
+	planned := append(splitSkills(action.ActionData[ActionDataRequiredSkills]), splitSkills(action.ActionData[ActionDataPreferredSkills])...)
+	for _, s := range planned {
+		if strings.HasPrefix(s, LanguageSkillPrefix) {
+			return action // Plan dili zaten belirlemiş
+		}
+	}
+	data := make(map[string]string, len(action.ActionData)+1)
+	for k, v := range action.ActionData {
+		data[k] = v
+	}
+	data[ActionDataPreferredSkills] = strings.Join(append(splitSkills(action.ActionData[ActionDataPreferredSkills]), skill), ",")
+	return &dialplanv1.DialplanAction{Action: action.Action, Type: action.Type, ActionData: data}
+}
+
+func splitSkills(raw string) []string {
+	var out []string
+	for _, s := range strings.Split(raw, ",") {
+		if s = normalizeSkill(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// validateSkillRequirements: Gereksinimleri doğrular; adları normalize eder ve boş seviyeyi MinSkillLevel yapar.
+func validateSkillRequirements(field string, reqs []*dialplanv1.SkillRequirement, v *violations) {
+	for i, r := range reqs {
+		path := fmt.Sprintf("%s[%d]", field, i)
+		if r == nil {
+			v.add(path, "boş gereksinim olamaz")
+			continue
+		}
+		r.Skill = normalizeSkill(r.Skill)
+		if r.Skill == "" {
+			v.add(path+".skill", "skill zorunludur")
+		}
+		if r.MinLevel <= 0 {
+			r.MinLevel = MinSkillLevel
+		}
+		if r.MinLevel > MaxSkillLevel {
+			v.add(path+".min_level", "en fazla %d olabilir", MaxSkillLevel)
+		}
+	}
+}
+
+// mergeRequirements: Kuyruk ve istek gereksinimlerini birleştirir; aynı beceride yüksek seviye geçerlidir.
+func mergeRequirements(sets ...[]*dialplanv1.SkillRequirement) []*dialplanv1.SkillRequirement {
+	var out []*dialplanv1.SkillRequirement
+	index := map[string]int{}
+	for _, set := range sets {
+		for _, r := range set {
+			if i, ok := index[r.Skill]; ok {
+				if r.MinLevel > out[i].MinLevel {
+					out[i].MinLevel = r.MinLevel
+				}
+				continue
+			}
+			index[r.Skill] = len(out)
+			out = append(out, &dialplanv1.SkillRequirement{Skill: r.Skill, MinLevel: r.MinLevel})
+		}
+	}
+	return out
+}
+
+// relaxRequirements: Bekleme süresine göre gevşetilmiş (zorunlu) gereksinimleri döner. Her relaxSeconds'lık
+// bekleme tüm seviyeleri bir düşürür; seviyesi sıfıra inen gereksinim zorunlu olmaktan çıkar (yalnızca
+// sıralamada puan olarak kalır). relaxSeconds 0 ise gereksinimler gevşetilmez.
+func relaxRequirements(reqs []*dialplanv1.SkillRequirement, relaxSeconds, waitSeconds int32) []*dialplanv1.SkillRequirement {
+	var steps int32
+	if relaxSeconds > 0 && waitSeconds > 0 {
+		steps = waitSeconds / relaxSeconds
+	}
+	out := make([]*dialplanv1.SkillRequirement, 0, len(reqs))
+	for _, r := range reqs {
+		if level := r.MinLevel - steps; level > 0 {
+			out = append(out, &dialplanv1.SkillRequirement{Skill: r.Skill, MinLevel: level})
+		}
+	}
+	return out
+}
+
+// normalizeSkills: Beceri adlarını normalize eder; boş ve tekrarlanan adlar düşer.
+func normalizeSkills(raw []string) []string {
+	var out []string
+	for _, s := range raw {
+		if s = normalizeSkill(s); s != "" && !slices.Contains(out, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// rankBySkills: Zorunlu gereksinimleri karşılamayan adayları eler; kalanları önce sahip oldukları tercih
+// edilen beceri sayısına, sonra tüm gereksinimlerdeki seviyelerinin toplamına göre (en iyi eşleşen önce)
+// sıralar. Tercih edilen beceriler adayı elemez. Eşit puanda strateji sırası korunur.
+func rankBySkills(candidates []queueCandidate, skills map[string]map[string]int32, required, all []*dialplanv1.SkillRequirement, preferred []string) []queueCandidate {
+	type scored struct {
+		c         queueCandidate
+		preferred int
+		score     int32
+	}
+	var matched []scored
+	for _, c := range candidates {
+		agent := skills[c.agentID]
+		ok := true
+		for _, r := range required {
+			if agent[r.Skill] < r.MinLevel {
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		var hits int
+		for _, p := range preferred {
+			if agent[p] > 0 {
+				hits++
+			}
+		}
+		var score int32
+		for _, r := range all {
+			score += agent[r.Skill]
+		}
+		matched = append(matched, scored{c, hits, score})
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].preferred != matched[j].preferred {
+			return matched[i].preferred > matched[j].preferred
+		}
+		return matched[i].score > matched[j].score
+	})
+
+	out := make([]queueCandidate, len(matched))
+	for i, m := range matched {
+		out[i] = m.c
+	}
+	return out
+}
+
+// agentSkillMap: Adayların becerilerini agent_id => beceri => seviye olarak yükler.
+func (s *Service) agentSkillMap(ctx context.Context, candidates []queueCandidate) (map[string]map[string]int32, error) {
+	ids := make([]string, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.agentID
+	}
+	list, err := s.repo.ListAgentSkills(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]map[string]int32, len(ids))
+	for _, sk := range list {
+		if out[sk.AgentId] == nil {
+			out[sk.AgentId] = map[string]int32{}
+		}
+		out[sk.AgentId][sk.Skill] = sk.Level
+	}
+	return out, nil
+}
+
+// SetAgentSkills: Ajanın beceri profilini verilen liste ile değiştirir; boş liste profili siler.
+func (s *Service) SetAgentSkills(ctx context.Context, agentID string, skills []*dialplanv1.AgentSkill) ([]*dialplanv1.AgentSkill, error) {
+	var v violations
+	if strings.TrimSpace(agentID) == "" {
+		v.add("agent_id", "agent_id zorunludur")
+	}
+	seen := map[string]bool{}
+	for i, sk := range skills {
+		field := fmt.Sprintf("skills[%d]", i)
+		if sk == nil {
+			v.add(field, "boş beceri olamaz")
+			continue
+		}
+		sk.AgentId = agentID
+		sk.Skill = normalizeSkill(sk.Skill)
+		switch {
+		case sk.Skill == "":
+			v.add(field+".skill", "skill zorunludur")
+		case seen[sk.Skill]:
+			v.add(field+".skill", "%q birden fazla kez tanımlanmış", sk.Skill)
+		}
+		seen[sk.Skill] = true
+		if sk.Level < MinSkillLevel || sk.Level > MaxSkillLevel {
+			v.add(field+".level", "seviye %d-%d aralığında olmalı", MinSkillLevel, MaxSkillLevel)
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceAgentSkills(ctx, agentID, skills); err != nil {
+		return nil, resourceErr(err, ResourceAgentSkill, agentID)
+	}
+	return skills, nil
+}
+
+func (s *Service) ListAgentSkills(ctx context.Context, agentID string) ([]*dialplanv1.AgentSkill, error) {
+	list, err := s.repo.ListAgentSkills(ctx, []string{agentID})
+	return list, resourceErr(err, ResourceAgentSkill, agentID)
+}
@@ -0,0 +1,61 @@
+package dialplan
+
+import (
+	"slices"
+	"testing"
+
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	userv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/user/v1"
+)
+
+func TestWithQueueSkillsAddsLanguageAsPreference(t *testing.T) {
+	route := &dialplanv1.InboundRoute{DefaultLanguageCode: "tr-TR"}
+	queued := func(data map[string]string) *dialplanv1.DialplanAction {
+		data[ActionDataQueueID] = "q1"
+		return &dialplanv1.DialplanAction{Action: "ROUTE_TO_QUEUE", ActionData: data}
+	}
+
+	got := withQueueSkills(queued(map[string]string{ActionDataRequiredSkills: "billing"}), nil, route)
+	if got.ActionData[ActionDataRequiredSkills] != "billing" {
+		t.Errorf("required_skills = %q, dil zorunlu gereksinime eklenmemeli", got.ActionData[ActionDataRequiredSkills])
+	}
+	if got.ActionData[ActionDataPreferredSkills] != "lang:tr" {
+		t.Errorf("preferred_skills = %q, want lang:tr", got.ActionData[ActionDataPreferredSkills])
+	}
+
+	de := "de"
+	got = withQueueSkills(queued(map[string]string{}), &userv1.User{PreferredLanguageCode: &de}, route)
+	if got.ActionData[ActionDataPreferredSkills] != "lang:de" {
+		t.Errorf("preferred_skills = %q, kullanıcı tercihi route dilinden önce gelmeli", got.ActionData[ActionDataPreferredSkills])
+	}
+
+	planned := queued(map[string]string{ActionDataRequiredSkills: "lang:en"})
+	if got = withQueueSkills(planned, nil, route); got != planned {
+		t.Errorf("plan dili belirlemişken aksiyon değiştirilmemeli: %v", got.ActionData)
+	}
+}
+
+func TestRankBySkillsPreferenceDoesNotFilter(t *testing.T) {
+	candidates := []queueCandidate{{agentID: "a1"}, {agentID: "a2"}, {agentID: "a3"}}
+	skills := map[string]map[string]int32{
+		"a1": {"billing": 9},
+		"a2": {"billing": 3, "lang:tr": 5},
+		"a3": {"lang:tr": 8},
+	}
+	required := []*dialplanv1.SkillRequirement{{Skill: "billing", MinLevel: 1}}
+
+	got := rankBySkills(candidates, skills, required, required, []string{"lang:tr"})
+	var ids []string
+	for _, c := range got {
+		ids = append(ids, c.agentID)
+	}
+	// a3 zorunlu beceriyi karşılamadığı için elenir; dili konuşan a2, daha yetkin a1'in önüne geçer.
+	if want := []string{"a2", "a1"}; !slices.Equal(ids, want) {
+		t.Errorf("rankBySkills = %v, want %v", ids, want)
+	}
+
+	got = rankBySkills(candidates, skills, nil, nil, []string{"lang:de"})
+	if len(got) != len(candidates) {
+		t.Errorf("tercih edilen beceriyi kimse karşılamıyorsa tüm adaylar kalmalı, got %d", len(got))
+	}
+}
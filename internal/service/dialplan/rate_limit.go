@@ -0,0 +1,105 @@
+// sentiric-dialplan-service/internal/service/dialplan/rate_limit.go
+package dialplan
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
+	"github.com/sentiric/sentiric-dialplan-service/internal/metrics"
+)
+
+// Rate limit kapsamları; sayaç anahtarında, metrik etiketinde ve karar izinde kullanılır.
+const (
+	RateLimitScopeCaller      = "caller"
+	RateLimitScopeDestination = "destination"
+	RateLimitScopeTenant      = "tenant"
+)
+
+// RateLimiter: Kayan pencere sayaçları (örn: Redis). key için window içinde limit'ten az çağrı varsa
+// çağrıyı sayar ve true döner.
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
+}
+
+// RateLimit: Window içinde izin verilen azami çağrı sayısı. Limit veya Window sıfırsa kapalıdır.
+type RateLimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+func (r RateLimit) enabled() bool {
+	return r.Limit > 0 && r.Window > 0
+}
+
+// defaultRateLimitCheckTimeout: RateLimits.CheckTimeout verilmediğinde tek bir sayaç kontrolünün azami süresi.
+const defaultRateLimitCheckTimeout = 250 * time.Millisecond
+
+// RateLimits: Kapsam bazlı limitler ve aşımda uygulanacak dialplan (boşsa DialplanSystemThrottled).
+// PerCaller sayacı "<tenant>:<arayan>" anahtarıyla tutulur.
+type RateLimits struct {
+	PerCaller          RateLimit
+	PerDestination     RateLimit
+	PerTenant          RateLimit
+	ThrottleDialplanID string
+	// CheckTimeout: Yavaş bir sayaç deposu çağrı kurulumunu bekletmesin diye her Allow çağrısına uygulanır.
+	CheckTimeout time.Duration
+}
+
+// WithRateLimiter: ResolveDialplan için flood korumasını etkinleştirir.
+func WithRateLimiter(limiter RateLimiter, limits RateLimits) Option {
+	return func(s *Service) {
+		s.rateLimiter = limiter
+		s.rateLimits = limits
+	}
+}
+
+// rateLimited: subject için kapsamın limitini uygular; aşıldıysa true döner.
+// [ARCH-COMPLIANCE] Sayaç deposuna erişilemezse veya CheckTimeout içinde yanıt vermezse çağrı sınırlanmaz (fail-open).
+func (s *Service) rateLimited(ctx context.Context, scope, subject string, limit RateLimit, trace *DecisionTrace, l zerolog.Logger) bool {
+	if s.rateLimiter == nil || !limit.enabled() || subject == "" {
+		return false
+	}
+	timeout := s.rateLimits.CheckTimeout
+	if timeout <= 0 {
+		timeout = defaultRateLimitCheckTimeout
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	allowed, err := s.rateLimiter.Allow(checkCtx, scope+":"+subject, limit.Limit, limit.Window)
+	if err != nil {
+		trace.record(TraceStepRateLimit, "error", "scope", scope, "error", err.Error())
+		l.Warn().Err(err).
+			Str("event", logger.EventRateLimitCheckFailed).
+			Str("scope", scope).
+			Msg("Rate limit kontrol edilemedi, çağrı sınırlanmadan devam ediliyor.")
+		return false
+	}
+	if allowed {
+		return false
+	}
+
+	metrics.RateLimitedCalls.WithLabelValues(scope).Inc()
+	trace.record(TraceStepRateLimit, "exceeded",
+		"scope", scope, "subject", subject, "limit", strconv.Itoa(limit.Limit), "window", limit.Window.String())
+	l.Warn().
+		Str("event", logger.EventRateLimitExceeded).
+		Str("scope", scope).
+		Str("subject", subject).
+		Int("limit", limit.Limit).
+		Dur("window", limit.Window).
+		Msg("🚦 Rate limit aşıldı, throttle akışına yönlendiriliyor.")
+	return true
+}
+
+// throttleResponse: Limit aşımında yapılandırılmış throttle dialplan'ını döner.
+func (s *Service) throttleResponse(ctx context.Context, l zerolog.Logger, trace *DecisionTrace, route *dialplanv1.InboundRoute) (*dialplanv1.ResolveDialplanResponse, error) {
+	planID := s.rateLimits.ThrottleDialplanID
+	if planID == "" {
+		planID = DialplanSystemThrottled
+	}
+	return s.buildFallbackResponse(ctx, l, trace, planID, AnnouncementThrottled, nil, nil, route)
+}
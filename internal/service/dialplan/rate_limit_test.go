@@ -0,0 +1,145 @@
+package dialplan
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/cache"
+)
+
+// fakeLimiter: Anahtar öneki (kapsam) reject ile eşleşen sayaçları aşılmış sayar; err veya slow verilirse
+// sayaç deposunun hatasını / yanıt vermemesini taklit eder.
+type fakeLimiter struct {
+	mu     sync.Mutex
+	keys   []string
+	reject string
+	err    error
+	slow   bool
+}
+
+func (f *fakeLimiter) Allow(ctx context.Context, key string, _ int, _ time.Duration) (bool, error) {
+	f.mu.Lock()
+	f.keys = append(f.keys, key)
+	f.mu.Unlock()
+	if f.slow {
+		<-ctx.Done()
+		return false, ctx.Err()
+	}
+	if f.err != nil {
+		return false, f.err
+	}
+	return f.reject == "" || !strings.HasPrefix(key, f.reject+":"), nil
+}
+
+func TestResolveDialplanRateLimit(t *testing.T) {
+	enabled := RateLimit{Limit: 1, Window: time.Minute}
+	all := RateLimits{PerCaller: enabled, PerDestination: enabled, PerTenant: enabled, CheckTimeout: 20 * time.Millisecond}
+
+	tests := []struct {
+		name        string
+		limiter     *fakeLimiter
+		limits      RateLimits
+		throttleID  string
+		dryRun      bool
+		want        string
+		wantKeys    []string
+		wantOutcome string
+	}{
+		{
+			name:    "disabled",
+			limiter: &fakeLimiter{reject: RateLimitScopeCaller},
+			limits:  RateLimits{},
+			want:    "DP1",
+		},
+		{
+			name:        "caller exceeded",
+			limiter:     &fakeLimiter{reject: RateLimitScopeCaller},
+			limits:      all,
+			throttleID:  "DP_THROTTLE",
+			want:        "DP_THROTTLE",
+			wantKeys:    []string{"destination:902125550000", "tenant:t1", "caller:t1:905321112233"},
+			wantOutcome: "exceeded",
+		},
+		{
+			name:        "destination exceeded uses system throttle",
+			limiter:     &fakeLimiter{reject: RateLimitScopeDestination},
+			limits:      all,
+			want:        DialplanSystemThrottled,
+			wantKeys:    []string{"destination:902125550000"},
+			wantOutcome: "exceeded",
+		},
+		{
+			name:        "store error fails open",
+			limiter:     &fakeLimiter{err: errors.New("redis down")},
+			limits:      all,
+			want:        "DP1",
+			wantKeys:    []string{"destination:902125550000", "tenant:t1", "caller:t1:905321112233"},
+			wantOutcome: "error",
+		},
+		{
+			name:        "slow store times out and fails open",
+			limiter:     &fakeLimiter{slow: true},
+			limits:      all,
+			want:        "DP1",
+			wantKeys:    []string{"destination:902125550000", "tenant:t1", "caller:t1:905321112233"},
+			wantOutcome: "error",
+		},
+		{
+			name:    "dry run does not count",
+			limiter: &fakeLimiter{reject: RateLimitScopeCaller},
+			limits:  all,
+			dryRun:  true,
+			want:    "DP1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &resolveRepo{
+				Repository: &callerListRepo{},
+				route: &dialplanv1.InboundRoute{
+					PhoneNumber: "902125550000", TenantId: "t1", ActiveDialplanId: toPtr("DP1"), DefaultLanguageCode: "tr",
+				},
+				dialplans: map[string]*dialplanv1.Dialplan{
+					"DP1":                   {Id: "DP1", TenantId: "t1", Action: &dialplanv1.DialplanAction{Action: "PROCESS_GUEST_CALL"}},
+					"DP_THROTTLE":           {Id: "DP_THROTTLE", TenantId: "t1", Action: &dialplanv1.DialplanAction{Action: ActionPlayAnnouncement}},
+					DialplanSystemThrottled: {Id: DialplanSystemThrottled, TenantId: "system", Action: &dialplanv1.DialplanAction{Action: ActionPlayAnnouncement}},
+				},
+			}
+			limits := tt.limits
+			limits.ThrottleDialplanID = tt.throttleID
+			rdb, _ := newRecordingRedis()
+			svc := NewService(repo, &userClient{}, cache.NewUserCache(rdb), zerolog.Nop(), WithRateLimiter(tt.limiter, limits))
+
+			trace := &DecisionTrace{}
+			start := time.Now()
+			resp, err := svc.ResolveDialplan(context.Background(), "905321112233", "902125550000", ResolveOptions{DryRun: tt.dryRun, Trace: trace})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("ResolveDialplan %s sürdü; sayaç kontrolü zaman aşımına uğramalıydı", elapsed)
+			}
+			if resp.DialplanId != tt.want {
+				t.Errorf("DialplanId = %q, want %q", resp.DialplanId, tt.want)
+			}
+			if strings.Join(tt.limiter.keys, ",") != strings.Join(tt.wantKeys, ",") {
+				t.Errorf("sayaç anahtarları = %q, want %q", tt.limiter.keys, tt.wantKeys)
+			}
+			var outcome string
+			for _, step := range trace.Steps {
+				if step.Step == TraceStepRateLimit {
+					outcome = step.Outcome
+				}
+			}
+			if outcome != tt.wantOutcome {
+				t.Errorf("rate_limit adımı = %q, want %q", outcome, tt.wantOutcome)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package dialplan
 
 import (
 	"context"
+	"time"
 
 	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
 )
@@ -12,6 +13,10 @@ import (
 type Repository interface {
 	// --- Inbound Routes ---
 	FindInboundRouteByPhone(ctx context.Context, phoneNumber string) (*dialplanv1.InboundRoute, error)
+	// FindPatternRoutesForNumber: Numarayı kapsayan tüm prefix ("90212555*") ve range route'larını döner.
+	FindPatternRoutesForNumber(ctx context.Context, phoneNumber string) ([]*dialplanv1.InboundRoute, error)
+	// CreateInboundRoute / UpdateInboundRoute: Anahtarın mevcut route'larla çakışması (RoutePattern.Conflicts)
+	// yazma ile aynı transaction'da, route yazımlarını sıralayan bir kilit altında denetlenir; çakışmada ErrConflict döner.
 	CreateInboundRoute(ctx context.Context, route *dialplanv1.InboundRoute) error
 	UpdateInboundRoute(ctx context.Context, route *dialplanv1.InboundRoute) (int64, error)
 	DeleteInboundRoute(ctx context.Context, phoneNumber string) (int64, error)
@@ -33,8 +38,59 @@ type Repository interface {
 	DeleteQueue(ctx context.Context, id string) (int64, error)
 	ListQueues(ctx context.Context, tenantID string, pageSize, offset int32) ([]*dialplanv1.Queue, error)
 	CountQueues(ctx context.Context, tenantID string) (int32, error)
+	AddQueueMember(ctx context.Context, m *dialplanv1.QueueMember) error
+	RemoveQueueMember(ctx context.Context, queueID, agentID string) (int64, error)
+	ListQueueMembers(ctx context.Context, queueID string) ([]*dialplanv1.QueueMember, error)
+	ReplaceAgentSkills(ctx context.Context, agentID string, skills []*dialplanv1.AgentSkill) error
+	ListAgentSkills(ctx context.Context, agentIDs []string) ([]*dialplanv1.AgentSkill, error)
 
 	// --- [YENİ] Schedules (Mesai Saatleri) ---
 	CreateSchedule(ctx context.Context, s *dialplanv1.Schedule) error
 	GetSchedule(ctx context.Context, id string) (*dialplanv1.Schedule, error)
+	UpdateSchedule(ctx context.Context, s *dialplanv1.Schedule) (int64, error)
+	DeleteSchedule(ctx context.Context, id string) (int64, error)
+	ListSchedules(ctx context.Context, tenantID string, pageSize, offset int32) ([]*dialplanv1.Schedule, error)
+	CountSchedules(ctx context.Context, tenantID string) (int32, error)
+	CountRoutesUsingSchedule(ctx context.Context, scheduleID string) (int32, error)
+
+	// --- Schedule Overrides (Takvim İstisnaları) ---
+	// CreateScheduleOverride: Kayıt ID'sini veritabanı üretir ve o.Id'ye yazar.
+	CreateScheduleOverride(ctx context.Context, o *dialplanv1.ScheduleOverride) error
+	// ListScheduleOverrides: notExpiredAt nil değilse yalnızca o andan sonra bitenleri döner.
+	ListScheduleOverrides(ctx context.Context, scheduleID string, notExpiredAt *time.Time) ([]*dialplanv1.ScheduleOverride, error)
+	DeleteScheduleOverride(ctx context.Context, id string) (int64, error)
+	DeleteExpiredScheduleOverrides(ctx context.Context, before time.Time) (int64, error)
+	// ImportSchedule: ICS içe aktarımını tek transaction'da uygular. schedule nil değilse tanım güncellenir
+	// (kayıt yoksa ErrNotFound); override'lardan aynı (schedule_id, starts_at, expires_at, reason) ile
+	// kayıtlı olanlar atlanır. Eklenen override sayısını döner.
+	ImportSchedule(ctx context.Context, schedule *dialplanv1.Schedule, overrides []*dialplanv1.ScheduleOverride) (int32, error)
+
+	// --- Holiday Calendars (Paylaşımlı Tatil Takvimleri) ---
+	CreateHolidayCalendar(ctx context.Context, c *dialplanv1.HolidayCalendar) error
+	GetHolidayCalendar(ctx context.Context, id string) (*dialplanv1.HolidayCalendar, error)
+	UpdateHolidayCalendar(ctx context.Context, c *dialplanv1.HolidayCalendar) (int64, error)
+	DeleteHolidayCalendar(ctx context.Context, id string) (int64, error)
+	ListHolidayCalendars(ctx context.Context, tenantID string, pageSize, offset int32) ([]*dialplanv1.HolidayCalendar, error)
+	CountHolidayCalendars(ctx context.Context, tenantID string) (int32, error)
+	// CountSchedulesUsingCalendar: schedule_data.calendars içinde takvime referans veren schedule sayısı.
+	CountSchedulesUsingCalendar(ctx context.Context, calendarID string) (int32, error)
+
+	// --- Caller Lists (Arayan Engelleme / İzin Listeleri) ---
+	// CreateCallerListEntry: Kayıt ID'sini veritabanı üretir ve e.Id'ye yazar.
+	CreateCallerListEntry(ctx context.Context, e *dialplanv1.CallerListEntry) error
+	GetCallerListEntry(ctx context.Context, id string) (*dialplanv1.CallerListEntry, error)
+	UpdateCallerListEntry(ctx context.Context, e *dialplanv1.CallerListEntry) (int64, error)
+	DeleteCallerListEntry(ctx context.Context, id string) (int64, error)
+	ListCallerListEntries(ctx context.Context, f CallerListFilter, pageSize, offset int32) ([]*dialplanv1.CallerListEntry, error)
+	CountCallerListEntries(ctx context.Context, f CallerListFilter) (int32, error)
+	// ImportCallerListEntries: Mükerrer kayıtları atlayarak toplu ekler; eklenen kayıt sayısını döner.
+	ImportCallerListEntries(ctx context.Context, entries []*dialplanv1.CallerListEntry) (int64, error)
+	// MatchCallerList: Arayana uyan (exact veya prefix), at anında geçerli kayıtları döner.
+	MatchCallerList(ctx context.Context, tenantID, routePhoneNumber, caller string, at time.Time) ([]*dialplanv1.CallerListEntry, error)
+	HasActiveAllowList(ctx context.Context, tenantID, routePhoneNumber string, at time.Time) (bool, error)
+
+	// --- Tenant Call Limits (Eşzamanlı Çağrı Kapasitesi) ---
+	GetTenantCallLimit(ctx context.Context, tenantID string) (*dialplanv1.TenantCallLimit, error)
+	UpsertTenantCallLimit(ctx context.Context, l *dialplanv1.TenantCallLimit) error
+	DeleteTenantCallLimit(ctx context.Context, tenantID string) (int64, error)
 }
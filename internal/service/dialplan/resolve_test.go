@@ -0,0 +1,188 @@
+package dialplan
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	userv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/user/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/cache"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// resolveRepo: Tek route ve dialplan'lar ile ResolveDialplan akışını karşılayan sahte repository.
+type resolveRepo struct {
+	Repository
+
+	route     *dialplanv1.InboundRoute
+	dialplans map[string]*dialplanv1.Dialplan
+}
+
+func (r *resolveRepo) FindInboundRouteByPhone(_ context.Context, _ string) (*dialplanv1.InboundRoute, error) {
+	if r.route == nil {
+		return nil, ErrNotFound
+	}
+	return r.route, nil
+}
+
+func (r *resolveRepo) FindPatternRoutesForNumber(context.Context, string) ([]*dialplanv1.InboundRoute, error) {
+	return nil, nil
+}
+
+func (r *resolveRepo) FindDialplanByID(_ context.Context, id string) (*dialplanv1.Dialplan, error) {
+	if dp, ok := r.dialplans[id]; ok {
+		return dp, nil
+	}
+	return nil, ErrNotFound
+}
+
+// userClient: Kullanıcı bulunamayan, CreateUser çağrılarını sayan sahte User Service istemcisi.
+type userClient struct {
+	userv1.UserServiceClient
+
+	mu      sync.Mutex
+	creates int
+}
+
+func (c *userClient) FindUserByContact(context.Context, *userv1.FindUserByContactRequest, ...grpc.CallOption) (*userv1.FindUserByContactResponse, error) {
+	return nil, status.Error(codes.NotFound, "user not found")
+}
+
+func (c *userClient) CreateUser(_ context.Context, req *userv1.CreateUserRequest, _ ...grpc.CallOption) (*userv1.CreateUserResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.creates++
+	return &userv1.CreateUserResponse{User: &userv1.User{Id: "u1", TenantId: req.TenantId, Name: req.Name}}, nil
+}
+
+// redisRecorder: Redis komutlarını sunucuya gitmeden kaydeder; GET her zaman boş döner.
+type redisRecorder struct {
+	mu   sync.Mutex
+	cmds []string
+}
+
+func (r *redisRecorder) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (r *redisRecorder) ProcessHook(redis.ProcessHook) redis.ProcessHook {
+	return func(_ context.Context, cmd redis.Cmder) error {
+		r.mu.Lock()
+		r.cmds = append(r.cmds, cmd.Name())
+		r.mu.Unlock()
+		if cmd.Name() == "get" {
+			cmd.SetErr(redis.Nil)
+			return redis.Nil
+		}
+		return nil
+	}
+}
+
+func (r *redisRecorder) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func (r *redisRecorder) count(name string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, c := range r.cmds {
+		if c == name {
+			n++
+		}
+	}
+	return n
+}
+
+func newRecordingRedis() (*redis.Client, *redisRecorder) {
+	rec := &redisRecorder{}
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	client.AddHook(rec)
+	return client, rec
+}
+
+// memTraceStore: Saklanan izleri sayan sahte TraceStore.
+type memTraceStore struct {
+	mu    sync.Mutex
+	saved int
+}
+
+func (s *memTraceStore) SaveTrace(context.Context, string, []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved++
+	return nil
+}
+
+func (s *memTraceStore) GetTrace(context.Context, string) ([]byte, error) {
+	return nil, ErrNotFound
+}
+
+func TestResolveDialplanDryRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		dryRun     bool
+		wantWrites int
+	}{
+		{name: "dry run", dryRun: true, wantWrites: 0},
+		{name: "live", dryRun: false, wantWrites: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &resolveRepo{
+				Repository: &callerListRepo{},
+				route: &dialplanv1.InboundRoute{
+					PhoneNumber: "902125550000", TenantId: "t1", ActiveDialplanId: toPtr("DP1"), DefaultLanguageCode: "tr",
+				},
+				dialplans: map[string]*dialplanv1.Dialplan{
+					"DP1": {Id: "DP1", TenantId: "t1", Action: &dialplanv1.DialplanAction{Action: "PROCESS_GUEST_CALL"}},
+				},
+			}
+			users := &userClient{}
+			rdb, rec := newRecordingRedis()
+			traces := &memTraceStore{}
+			svc := NewService(repo, users, cache.NewUserCache(rdb), zerolog.Nop(),
+				WithTraceStore(traces), WithTraceSampleRate(1))
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-trace-id", "trace-1"))
+			trace := &DecisionTrace{}
+			resp, err := svc.ResolveDialplan(ctx, "905321112233", "902125550000", ResolveOptions{DryRun: tt.dryRun, Trace: trace})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.DialplanId != "DP1" {
+				t.Errorf("DialplanId = %q, want DP1", resp.DialplanId)
+			}
+			if users.creates != tt.wantWrites {
+				t.Errorf("CreateUser çağrısı = %d, want %d", users.creates, tt.wantWrites)
+			}
+			if got := rec.count("set"); got != tt.wantWrites {
+				t.Errorf("cache yazımı = %d, want %d", got, tt.wantWrites)
+			}
+			if traces.saved != tt.wantWrites {
+				t.Errorf("saklanan iz = %d, want %d", traces.saved, tt.wantWrites)
+			}
+
+			if !tt.dryRun {
+				if trace.WouldProvision != nil {
+					t.Errorf("WouldProvision = %+v, want nil", trace.WouldProvision)
+				}
+				return
+			}
+			if resp.MatchedUser.GetId() != NilUUID {
+				t.Errorf("MatchedUser.Id = %q, want %q", resp.MatchedUser.GetId(), NilUUID)
+			}
+			want := ProvisionPreview{
+				TenantID: "t1", UserType: "guest", Name: "Guest_905321112233",
+				ContactType: "phone", ContactValue: "905321112233", PreferredLanguageCode: "tr",
+			}
+			if trace.WouldProvision == nil || *trace.WouldProvision != want {
+				t.Errorf("WouldProvision = %+v, want %+v", trace.WouldProvision, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+// sentiric-dialplan-service/internal/service/dialplan/route_pattern.go
+package dialplan
+
+import (
+	"fmt"
+	"strings"
+
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+)
+
+// RouteMatchKind: Bir inbound route anahtarının (phone_number) eşleşme türü.
+type RouteMatchKind int
+
+const (
+	RouteMatchExact  RouteMatchKind = iota // "902125550000"
+	RouteMatchPrefix                       // "90212555*"
+	RouteMatchRange                        // "902125550000-902125559999"
+)
+
+const (
+	routePrefixWildcard = "*"
+	routeRangeSeparator = "-"
+	minRangeDigits      = 7
+)
+
+// RoutePattern: DID blokları için exact / prefix / range eşleşme kuralı.
+// Veritabanında phone_number kolonunda kanonik String() formunda saklanır.
+type RoutePattern struct {
+	Kind  RouteMatchKind
+	Value string // Exact numara veya prefix (wildcard hariç)
+	Start string // Range alt sınırı (dahil)
+	End   string // Range üst sınırı (dahil)
+}
+
+// ParseRoutePattern: Admin API'den gelen route anahtarını ayrıştırır ve verilen
+// numaralandırma planına göre normalize eder. Prefix'ler de plana göre E.164 forma getirilir (NormalizePrefix).
+func ParseRoutePattern(raw string, plan NumberingPlan) (RoutePattern, error) {
+	raw = strings.TrimSpace(raw)
+	switch {
+	case raw == "":
+		return RoutePattern{}, fmt.Errorf("%w: phone_number boş olamaz", ErrInvalidArgument)
+
+	case strings.HasSuffix(raw, routePrefixWildcard):
+		prefix, ok := plan.NormalizePrefix(strings.TrimSuffix(raw, routePrefixWildcard))
+		if !ok {
+			return RoutePattern{}, fmt.Errorf("%w: geçersiz prefix route %q (yalnızca rakam, isteğe bağlı '+' ve sonda '*')", ErrInvalidArgument, raw)
+		}
+		return RoutePattern{Kind: RouteMatchPrefix, Value: prefix}, nil
+
+	case isRangeExpr(raw):
+		parts := strings.Split(raw, routeRangeSeparator)
+		start := plan.Normalize(strings.TrimSpace(parts[0]))
+		end := plan.Normalize(strings.TrimSpace(parts[1]))
+		if len(start) != len(end) {
+			return RoutePattern{}, fmt.Errorf("%w: range sınırları normalize edildikten sonra aynı uzunlukta olmalı: %q", ErrInvalidArgument, raw)
+		}
+		if start > end {
+			return RoutePattern{}, fmt.Errorf("%w: range başlangıcı bitişten büyük: %q", ErrInvalidArgument, raw)
+		}
+		if start == end {
+			return RoutePattern{Kind: RouteMatchExact, Value: start}, nil
+		}
+		return RoutePattern{Kind: RouteMatchRange, Start: start, End: end}, nil
+
+	case strings.Contains(raw, routePrefixWildcard):
+		return RoutePattern{}, fmt.Errorf("%w: '*' yalnızca route anahtarının sonunda kullanılabilir: %q", ErrInvalidArgument, raw)
+
+	default:
+		number := plan.Normalize(raw)
+		if !isDigits(number) {
+			return RoutePattern{}, fmt.Errorf("%w: geçersiz numara %q", ErrInvalidArgument, raw)
+		}
+		return RoutePattern{Kind: RouteMatchExact, Value: number}, nil
+	}
+}
+
+// isRangeExpr: "başlangıç-bitiş" ifadesini "0212-555-0000" gibi biçimlendirilmiş
+// tekil numaralardan ayırır. Range sınırları aynı uzunlukta, yeterince uzun rakam dizileri olmalıdır.
+func isRangeExpr(raw string) bool {
+	parts := strings.Split(raw, routeRangeSeparator)
+	if len(parts) != 2 {
+		return false
+	}
+	start, end := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	return isDigits(start) && isDigits(end) && len(start) == len(end) && len(start) >= minRangeDigits
+}
+
+// String: Veritabanında saklanan kanonik form.
+func (p RoutePattern) String() string {
+	switch p.Kind {
+	case RouteMatchPrefix:
+		return p.Value + routePrefixWildcard
+	case RouteMatchRange:
+		return p.Start + routeRangeSeparator + p.End
+	default:
+		return p.Value
+	}
+}
+
+// Matches: Normalize edilmiş numaranın bu kurala uyup uymadığını döner.
+func (p RoutePattern) Matches(number string) bool {
+	switch p.Kind {
+	case RouteMatchPrefix:
+		return strings.HasPrefix(number, p.Value)
+	case RouteMatchRange:
+		return len(number) == len(p.Start) && number >= p.Start && number <= p.End
+	default:
+		return number == p.Value
+	}
+}
+
+// Conflicts: İki route anahtarının aynı numarayı kapsayıp kapsamadığını döner. Range'ler; kesişen range'lerle,
+// içlerine düşen exact numaralarla ve range'deki bir numaraya uyan prefix'lerle çakışır. Prefix ve exact
+// kurallar kendi aralarında iç içe olabilir (en uzun prefix, exact her zaman kazanır).
+func (p RoutePattern) Conflicts(o RoutePattern) bool {
+	switch {
+	case p.Kind == RouteMatchRange && o.Kind == RouteMatchRange:
+		return len(p.Start) == len(o.Start) && p.Start <= o.End && o.Start <= p.End
+	case p.Kind == RouteMatchRange:
+		return o.intersectsRange(p)
+	case o.Kind == RouteMatchRange:
+		return p.intersectsRange(o)
+	}
+	return false
+}
+
+// intersectsRange: Exact veya prefix kuralının r range'indeki en az bir numaraya uyup uymadığı.
+func (p RoutePattern) intersectsRange(r RoutePattern) bool {
+	n := len(r.Start)
+	if len(p.Value) > n || (p.Kind == RouteMatchExact && len(p.Value) != n) {
+		return false
+	}
+	lo := p.Value + strings.Repeat("0", n-len(p.Value))
+	hi := p.Value + strings.Repeat("9", n-len(p.Value))
+	return lo <= r.End && r.Start <= hi
+}
+
+// specificity: Longest-prefix seçimi için sabit rakam sayısı.
+// Range için alt ve üst sınırın ortak prefix uzunluğu kullanılır.
+func (p RoutePattern) specificity() int {
+	switch p.Kind {
+	case RouteMatchPrefix:
+		return len(p.Value)
+	case RouteMatchRange:
+		n := 0
+		for n < len(p.Start) && p.Start[n] == p.End[n] {
+			n++
+		}
+		return n
+	default:
+		return len(p.Value) + 1 // Exact her zaman kazanır
+	}
+}
+
+// moreSpecificThan: Eşit sabit rakam sayısında range (daha dar tanım) prefix'e tercih edilir.
+func (p RoutePattern) moreSpecificThan(o RoutePattern) bool {
+	if p.specificity() != o.specificity() {
+		return p.specificity() > o.specificity()
+	}
+	return p.Kind == RouteMatchRange && o.Kind == RouteMatchPrefix
+}
+
+// selectMostSpecificRoute: Aday route'lar arasından numaraya uyan en spesifik olanı seçer.
+func selectMostSpecificRoute(number string, candidates []*dialplanv1.InboundRoute) *dialplanv1.InboundRoute {
+	var best *dialplanv1.InboundRoute
+	var bestPattern RoutePattern
+	for _, c := range candidates {
+		p, err := parseStoredRoutePattern(c.PhoneNumber)
+		if err != nil || !p.Matches(number) {
+			continue
+		}
+		if best == nil || p.moreSpecificThan(bestPattern) {
+			best, bestPattern = c, p
+		}
+	}
+	return best
+}
+
+// parseStoredRoutePattern: Veritabanındaki kanonik anahtarı ayrıştırır; saklanan
+// değerler zaten normalize edildiği için ülke kuralı uygulanmaz.
+func parseStoredRoutePattern(raw string) (RoutePattern, error) {
+	return ParseRoutePattern(raw, NumberingPlan{})
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
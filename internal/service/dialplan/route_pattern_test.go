@@ -0,0 +1,39 @@
+package dialplan
+
+import "testing"
+
+func TestRoutePatternConflicts(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"902125550000-902125559999", "902125555000-902125560000", true},
+		{"902125550000-902125554999", "902125555000-902125559999", false},
+		{"902125550000-902125559999", "9021255500000-9021255599999", false}, // farklı uzunluk
+		{"902125550000-902125559999", "902125551234", true},
+		{"902125550000-902125559999", "902125561234", false},
+		{"902125550000-902125559999", "90212555123", false}, // kısa exact range'e düşmez
+		{"902125550000-902125559999", "90212555*", true},
+		{"902125550000-902125559999", "9021255512*", true},
+		{"902125550000-902125559999", "90212556*", false},
+		{"902125550000-902125559999", "9021255500001*", false}, // prefix range'den uzun
+		{"90212555*", "902125551234", false},                   // exact prefix'e göre önceliklidir
+		{"90212555*", "9021255*", false},                       // en uzun prefix kazanır
+	}
+	for _, tt := range tests {
+		a, err := ParseRoutePattern(tt.a, NumberingPlan{})
+		if err != nil {
+			t.Fatalf("ParseRoutePattern(%q): %v", tt.a, err)
+		}
+		b, err := ParseRoutePattern(tt.b, NumberingPlan{})
+		if err != nil {
+			t.Fatalf("ParseRoutePattern(%q): %v", tt.b, err)
+		}
+		if got := a.Conflicts(b); got != tt.want {
+			t.Errorf("%s vs %s: Conflicts = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if got := b.Conflicts(a); got != tt.want {
+			t.Errorf("%s vs %s: Conflicts = %v, want %v", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
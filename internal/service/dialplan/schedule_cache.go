@@ -0,0 +1,78 @@
+// sentiric-dialplan-service/internal/service/dialplan/schedule_cache.go
+package dialplan
+
+import (
+	"sync"
+	"time"
+
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+)
+
+// DefaultScheduleCacheTTL: Derlenmiş takvimlerin süreç içi önbellekte kalma süresi.
+// Yazma işlemleri bu replikadaki kaydı anında geçersiz kılar; diğer replikalar en geç TTL sonunda güncellenir.
+const DefaultScheduleCacheTTL = 30 * time.Second
+
+// scheduleCacheEntry: Takvim kaydı ve derlenmiş hali.
+// Override'lar overridesFrom anından sonra bitenlerle yüklendiği için daha önceki anların
+// değerlendirmesinde (geçmişe dönük simülasyon) kullanılamaz.
+type scheduleCacheEntry struct {
+	schedule      *dialplanv1.Schedule
+	compiled      *compiledSchedule
+	overridesFrom time.Time
+	expiresAt     time.Time
+}
+
+// scheduleCache: Takvim ID'sine göre derlenmiş takvim önbelleği. ttl <= 0 ise devre dışıdır.
+type scheduleCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]scheduleCacheEntry
+}
+
+func newScheduleCache(ttl time.Duration) *scheduleCache {
+	return &scheduleCache{ttl: ttl, entries: make(map[string]scheduleCacheEntry)}
+}
+
+// WithScheduleCacheTTL: Derlenmiş takvim önbelleğinin TTL'ini ayarlar (0 => önbellek kapalı).
+func WithScheduleCacheTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		s.schedules = newScheduleCache(ttl)
+	}
+}
+
+func (c *scheduleCache) get(id string, at, now time.Time) (scheduleCacheEntry, bool) {
+	if c.ttl <= 0 {
+		return scheduleCacheEntry{}, false
+	}
+	c.mu.RLock()
+	e, ok := c.entries[id]
+	c.mu.RUnlock()
+	if !ok || !now.Before(e.expiresAt) || at.Before(e.overridesFrom) {
+		return scheduleCacheEntry{}, false
+	}
+	return e, true
+}
+
+func (c *scheduleCache) put(id string, e scheduleCacheEntry, now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+	e.expiresAt = now.Add(c.ttl)
+	c.mu.Lock()
+	c.entries[id] = e
+	c.mu.Unlock()
+}
+
+// invalidate: Tek bir takvimi önbellekten düşürür.
+func (c *scheduleCache) invalidate(id string) {
+	c.mu.Lock()
+	delete(c.entries, id)
+	c.mu.Unlock()
+}
+
+// purge: Tüm önbelleği temizler (paylaşımlı tatil takvimi gibi birden çok takvimi etkileyen değişikliklerde).
+func (c *scheduleCache) purge() {
+	c.mu.Lock()
+	c.entries = make(map[string]scheduleCacheEntry)
+	c.mu.Unlock()
+}
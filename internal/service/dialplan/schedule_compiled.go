@@ -0,0 +1,231 @@
+// sentiric-dialplan-service/internal/service/dialplan/schedule_compiled.go
+package dialplan
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
+)
+
+// compiledRange: Dakikaya çevrilmiş zaman aralığı. Gece yarısını aşan aralıklarda end > 1440 olur.
+type compiledRange struct {
+	start, end int
+	state      string
+}
+
+// compiledHoliday: Gün seçicisi sayısal anahtara çevrilmiş tatil kuralı.
+// Yalnızca biri doludur: date (yyyymmdd), annual (mmdd) veya nth.
+type compiledHoliday struct {
+	name   string
+	date   int
+	annual int
+	nth    *NthWeekday
+	hours  []compiledRange
+	closed bool // Hours tanımsız: tam gün kapalı
+}
+
+func (h *compiledHoliday) matches(t time.Time) bool {
+	y, m, d := t.Date()
+	switch {
+	case h.date != 0:
+		return h.date == dateKey(y, m, d)
+	case h.annual != 0:
+		return h.annual == int(m)*100+d
+	case h.nth != nil:
+		return h.nth.matches(t)
+	}
+	return false
+}
+
+// compiledSchedule: ScheduleDefinition'ın çağrı anında tekrar parse edilmeden değerlendirilebilen,
+// değişmez (immutable) formu. Birden fazla goroutine tarafından eşzamanlı okunabilir.
+type compiledSchedule struct {
+	loc         *time.Location
+	week        [7][]compiledRange // time.Weekday sırasıyla
+	workdays    [7]bool            // Tanımda aralığı olan günler (aralıkları hatalı olsa bile hafta sonu sayılmaz)
+	closedDates map[int]struct{}   // "holidays" listesi (yyyymmdd)
+	holidays    []compiledHoliday  // holiday_rules + paylaşımlı tatil takvimleri
+	overrides   []scheduleOverride
+	tzFallback  string // Yüklenemeyip yerine UTC kullanılan timezone; geçerliyse boş
+}
+
+// compileSchedule: Tanımı; paylaşımlı tatil takvimi kuralları ve override'larla birlikte derler.
+// Hatalı aralık ve tarihler gece yarısı varsayılmaz; derleme sırasında bir kez raporlanıp atlanır.
+func compileSchedule(sched *ScheduleDefinition, loc *time.Location, calendarRules []HolidayRule, overrides []scheduleOverride, l zerolog.Logger) *compiledSchedule {
+	c := &compiledSchedule{
+		loc:         loc,
+		closedDates: make(map[int]struct{}, len(sched.Holidays)),
+		overrides:   overrides,
+	}
+	for day, key := range weekdayKeys {
+		c.week[day] = compileRanges(sched.Days[key], key, l)
+		c.workdays[day] = len(sched.Days[key]) > 0
+	}
+	for _, raw := range sched.Holidays {
+		t, err := time.Parse(holidayLayout, raw)
+		if err != nil {
+			l.Error().Err(err).Str("event", logger.EventScheduleParseError).Str("holiday", raw).Msg("Geçersiz tatil tarihi atlandı.")
+			continue
+		}
+		c.closedDates[dateKey(t.Date())] = struct{}{}
+	}
+	for _, rules := range [][]HolidayRule{sched.HolidayRules, calendarRules} {
+		for _, rule := range rules {
+			h, err := compileHoliday(rule, l)
+			if err != nil {
+				l.Error().Err(err).Str("event", logger.EventScheduleParseError).Str("holiday", rule.Name).Msg("Geçersiz tatil kuralı atlandı.")
+				continue
+			}
+			c.holidays = append(c.holidays, h)
+		}
+	}
+	return c
+}
+
+func compileHoliday(rule HolidayRule, l zerolog.Logger) (compiledHoliday, error) {
+	h := compiledHoliday{name: rule.Name, closed: len(rule.Hours) == 0}
+	switch {
+	case rule.Date != "":
+		t, err := time.Parse(holidayLayout, rule.Date)
+		if err != nil {
+			return h, err
+		}
+		h.date = dateKey(t.Date())
+	case rule.Annual != "":
+		t, err := time.Parse(holidayLayout, "2000-"+rule.Annual)
+		if err != nil {
+			return h, err
+		}
+		h.annual = int(t.Month())*100 + t.Day()
+	case rule.NthWeekday != nil:
+		nth := *rule.NthWeekday
+		h.nth = &nth
+	default:
+		return h, fmt.Errorf("gün seçicisi yok")
+	}
+	h.hours = compileRanges(rule.Hours, "holiday:"+rule.Name, l)
+	return h, nil
+}
+
+func compileRanges(ranges []TimeRange, label string, l zerolog.Logger) []compiledRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+	out := make([]compiledRange, 0, len(ranges))
+	for i, rng := range ranges {
+		start, end, err := rng.minutes()
+		if err != nil {
+			l.Error().Err(err).
+				Str("event", logger.EventScheduleParseError).
+				Str("day", label).
+				Int("range_index", i).
+				Msg("Geçersiz zaman aralığı atlandı.")
+			continue
+		}
+		out = append(out, compiledRange{start: start, end: end, state: rng.state()})
+	}
+	return out
+}
+
+func dateKey(y int, m time.Month, d int) int {
+	return y*10000 + int(m)*100 + d
+}
+
+// stateAt: now (takvimin timezone'unda) için durumu belirler.
+// Bugün başlayan aralıklar ve dünden taşan (gece yarısını aşan) aralıklar birlikte değerlendirilir.
+// Tatil günü, o gün BAŞLAYAN aralıkların yerine tatilin saatlerini (yoksa hiçbirini) koyar.
+// Aktif bir override (zorla açık/kapalı) her şeyin önüne geçer.
+func (c *compiledSchedule) stateAt(now time.Time) string {
+	if state, ok := c.overrideState(now); ok {
+		return state
+	}
+
+	currentMinutes := now.Hour()*60 + now.Minute()
+	yesterday := now.AddDate(0, 0, -1)
+
+	// 2. Bugün başlayan aralıklar
+	todayRanges, todayHoliday := c.rangesFor(now)
+	state, ok := rangesState(todayRanges, currentMinutes)
+
+	// 3. Dünden taşan aralıklar (örn: dün 22:00 - bugün 06:00)
+	if !ok || state == StateOpen {
+		yesterdayRanges, _ := c.rangesFor(yesterday)
+		if spill, spillOK := rangesState(yesterdayRanges, currentMinutes+minutesPerDay); spillOK && (!ok || spill != StateOpen) {
+			state, ok = spill, true
+		}
+	}
+	if ok {
+		return state // Aralıklardan birine uyuyor
+	}
+
+	// 4. Hiçbir aralığa uymadı -> kapalı durumun türü
+	switch {
+	case todayHoliday:
+		return StateHoliday
+	case !c.workdays[now.Weekday()]:
+		return StateWeekend
+	default:
+		return StateAfterHours
+	}
+}
+
+// rangesFor: Verilen günde başlayan aralıklar. Tatil günlerinde haftalık plan yerine
+// tatilin saatleri döner (tam gün tatilde boş); holiday bu durumu belirtir.
+func (c *compiledSchedule) rangesFor(day time.Time) (ranges []compiledRange, holiday bool) {
+	if hours, ok := c.holidayHours(day); ok {
+		return hours, true
+	}
+	return c.week[day.Weekday()], false
+}
+
+// holidayHours: Günün tatil olup olmadığını ve tatilse açık kalınan saatleri döner.
+// Aynı güne denk gelen kurallardan biri tam gün kapalıysa gün kapalıdır; aksi halde saatler birleştirilir.
+// Tek kural eşleştiğinde (olağan durum) derlenmiş dilim kopyalanmadan döner.
+func (c *compiledSchedule) holidayHours(day time.Time) ([]compiledRange, bool) {
+	if c.isHoliday(day) {
+		return nil, true
+	}
+	var hours []compiledRange
+	matches := 0
+	for i := range c.holidays {
+		h := &c.holidays[i]
+		if !h.matches(day) {
+			continue
+		}
+		if h.closed {
+			return nil, true
+		}
+		if matches++; matches == 1 {
+			hours = h.hours
+		} else {
+			hours = append(append([]compiledRange(nil), hours...), h.hours...)
+		}
+	}
+	return hours, matches > 0
+}
+
+// isHoliday: Tam gün tatil kontrolü ("holidays" listesi)
+func (c *compiledSchedule) isHoliday(t time.Time) bool {
+	if len(c.closedDates) == 0 {
+		return false
+	}
+	_, ok := c.closedDates[dateKey(t.Date())]
+	return ok
+}
+
+// rangesState: minuteOfDay'i kapsayan aralıkların durumunu döner. Etiketli (open dışı) aralık,
+// örtüştüğü etiketsiz aralığa üstün gelir (örn: 09:00-18:00 içindeki 12:00-13:00 öğle arası).
+// minuteOfDay, bir önceki günden taşan aralıklar için 1440'tan büyük olabilir.
+func rangesState(ranges []compiledRange, minuteOfDay int) (string, bool) {
+	state, matched := "", false
+	for _, rng := range ranges {
+		if minuteOfDay >= rng.start && minuteOfDay < rng.end {
+			if !matched || (state == StateOpen && rng.state != StateOpen) {
+				state, matched = rng.state, true
+			}
+		}
+	}
+	return state, matched
+}
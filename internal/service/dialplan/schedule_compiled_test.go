@@ -0,0 +1,75 @@
+package dialplan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const benchScheduleJSON = `{
+	"timezone":"Europe/Istanbul",
+	"days":{
+		"mon":[{"start":"09:00","end":"18:00"},{"start":"12:00","end":"13:00","state":"lunch_break"}],
+		"tue":[{"start":"09:00","end":"18:00"},{"start":"12:00","end":"13:00","state":"lunch_break"}],
+		"wed":[{"start":"09:00","end":"18:00"},{"start":"12:00","end":"13:00","state":"lunch_break"}],
+		"thu":[{"start":"09:00","end":"18:00"},{"start":"12:00","end":"13:00","state":"lunch_break"}],
+		"fri":[{"start":"09:00","end":"18:00"},{"start":"22:00","end":"06:00"}]
+	},
+	"holidays":["2026-01-01","2026-04-23","2026-05-01","2026-05-19"],
+	"holiday_rules":[
+		{"name":"Cumhuriyet Bayramı","annual":"10-29"},
+		{"name":"Yılbaşı arifesi","date":"2026-12-31","hours":[{"start":"09:00","end":"13:00"}]}
+	]
+}`
+
+// BenchmarkResolveSchedule: Çağrı başına takvim kararının maliyeti. "decode" eski yol (her çağrıda JSON
+// çözümü + derleme, IsWorkingHour), "compiled" önbellekteki derlenmiş takvimin stateAt'idir.
+func BenchmarkResolveSchedule(b *testing.B) {
+	at := time.Date(2026, 1, 7, 11, 30, 0, 0, time.UTC)
+	l := zerolog.Nop()
+
+	b.Run("decode", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = IsWorkingHour(benchScheduleJSON, at, l)
+		}
+	})
+
+	b.Run("compiled", func(b *testing.B) {
+		sched, err := decodeSchedule(benchScheduleJSON)
+		if err != nil {
+			b.Fatal(err)
+		}
+		loc, err := sched.location()
+		if err != nil {
+			b.Fatal(err)
+		}
+		c := compileSchedule(sched, loc, nil, nil, l)
+		local := at.In(loc)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = c.stateAt(local) == StateOpen
+		}
+	})
+}
+
+// BenchmarkScheduleStatus: Sonraki açılış/kapanış hesaplamasının (transitionCandidates taraması) maliyeti.
+func BenchmarkScheduleStatus(b *testing.B) {
+	sched, err := decodeSchedule(benchScheduleJSON)
+	if err != nil {
+		b.Fatal(err)
+	}
+	loc, err := sched.location()
+	if err != nil {
+		b.Fatal(err)
+	}
+	c := compileSchedule(sched, loc, nil, nil, zerolog.Nop())
+	at := time.Date(2026, 1, 7, 11, 30, 0, 0, time.UTC)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = c.status(at)
+	}
+}
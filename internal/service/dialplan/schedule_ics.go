@@ -0,0 +1,244 @@
+// sentiric-dialplan-service/internal/service/dialplan/schedule_ics.go
+package dialplan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
+)
+
+const (
+	// icsDefaultExportDays / icsMaxExportDays: Dışa aktarılan "kapalı" takviminin varsayılan ve azami penceresi.
+	// icsMaxExportDays, içe aktarılan çok günlü tüm gün olayların gün gün açılımını da sınırlar.
+	icsDefaultExportDays = 90
+	icsMaxExportDays     = 366
+)
+
+// ImportScheduleIcs: Yüklenen ICS belgesini takvime işler.
+//   - Tüm gün olaylar tatil kuralı olur (FREQ=YEARLY tekrarlar yıllık / N. hafta günü kuralına çevrilir).
+//     icsMaxExportDays'den uzun olaylar ilk icsMaxExportDays günüyle kırpılır ve uyarı döner.
+//   - Saatli olaylar "forced_closed" override olur; bitmiş olanlar ve aynı aralık/açıklamayla kayıtlı olanlar atlanır.
+//
+// Tatiller ve override'lar tek transaction'da yazılır.
+//
+// Desteklenmeyen veya okunamayan olaylar atlanır ve uyarı olarak döner. Belge yalnızca verilen
+// byte'lar üzerinden işlenir; harici takvim/URL çözümlenmez.
+func (s *Service) ImportScheduleIcs(ctx context.Context, req *dialplanv1.ImportScheduleIcsRequest) (*dialplanv1.ImportScheduleIcsResponse, error) {
+	l := logger.ContextLogger(ctx, s.baseLog)
+
+	schedule, err := s.repo.GetSchedule(ctx, req.ScheduleId)
+	if err != nil {
+		return nil, resourceErr(err, ResourceSchedule, req.ScheduleId)
+	}
+	sched, err := decodeSchedule(schedule.ScheduleJson)
+	if err != nil {
+		return nil, resourceErr(fmt.Errorf("kayıtlı takvim çözülemedi: %w", err), ResourceSchedule, req.ScheduleId)
+	}
+	loc, err := sched.location()
+	if err != nil {
+		return nil, resourceErr(fmt.Errorf("kayıtlı takvim timezone'u yüklenemedi: %w", err), ResourceSchedule, req.ScheduleId)
+	}
+
+	events, warnings, err := parseICS(req.IcsData, loc)
+	if err != nil {
+		var v violations
+		v.add("ics_data", "%v", err)
+		return nil, v.err()
+	}
+
+	now := s.clock.Now()
+	resp := &dialplanv1.ImportScheduleIcsResponse{}
+	known := sched.holidaySet()
+	var overrides []*dialplanv1.ScheduleOverride
+
+	for _, ev := range events {
+		label := ev.Summary
+		if label == "" {
+			label = ev.UID
+		}
+
+		if !ev.AllDay {
+			if ev.RRule != nil {
+				warnings = append(warnings, fmt.Sprintf("%q atlandı: tekrarlayan saatli olaylar desteklenmiyor", label))
+				continue
+			}
+			if !ev.End.After(now) {
+				continue // Geçmiş kapanış
+			}
+			overrides = append(overrides, &dialplanv1.ScheduleOverride{
+				ScheduleId: req.ScheduleId,
+				Mode:       OverrideForcedClosed,
+				Reason:     "ICS: " + label,
+				StartsAt:   ev.Start.UTC().Format(time.RFC3339),
+				ExpiresAt:  ev.End.UTC().Format(time.RFC3339),
+			})
+			continue
+		}
+
+		if ev.RRule != nil {
+			rule, err := yearlyHolidayRule(ev)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%q atlandı: %v", label, err))
+				continue
+			}
+			if sched.addHolidayRule(rule, known) {
+				resp.ImportedHolidays++
+			}
+			continue
+		}
+
+		// Çok günlü tatiller gün gün eklenir (End hariç).
+		end := ev.End
+		if limit := ev.Start.AddDate(0, 0, icsMaxExportDays); end.After(limit) {
+			warnings = append(warnings, fmt.Sprintf("%q yalnızca ilk %d günüyle içe aktarıldı", label, icsMaxExportDays))
+			end = limit
+		}
+		for day := ev.Start; day.Before(end); day = day.AddDate(0, 0, 1) {
+			if sched.addHolidayRule(HolidayRule{Name: ev.Summary, Date: day.Format(holidayLayout)}, known) {
+				resp.ImportedHolidays++
+			}
+		}
+	}
+
+	// Tatiller ve override'lar tek transaction'da yazılır: hata durumunda takvim yarım kalmaz.
+	var updated *dialplanv1.Schedule
+	if resp.ImportedHolidays > 0 {
+		if err := encodeScheduleDefinition(schedule, sched, l); err != nil {
+			return nil, err
+		}
+		updated = schedule
+	}
+	valid := overrides[:0]
+	for _, o := range overrides {
+		if err := validateScheduleOverride(o, now); err != nil {
+			warnings = append(warnings, fmt.Sprintf("%q atlandı: %v", o.Reason, err))
+			continue
+		}
+		valid = append(valid, o)
+	}
+	if updated != nil || len(valid) > 0 {
+		inserted, err := s.repo.ImportSchedule(ctx, updated, valid)
+		s.schedules.invalidate(req.ScheduleId)
+		if err != nil {
+			return nil, resourceErr(err, ResourceSchedule, req.ScheduleId)
+		}
+		resp.ImportedOverrides = inserted
+		if dup := len(valid) - int(inserted); dup > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d saatli olay aynı aralık ve açıklamayla zaten kayıtlı olduğu için atlandı", dup))
+		}
+	}
+
+	resp.Warnings = warnings
+	return resp, nil
+}
+
+// ExportScheduleIcs: Takvimin [from, to) aralığındaki etkin kapalı dilimlerini (haftalık plan, tatiller
+// ve override'lar birlikte değerlendirilerek) ICS olarak üretir. Boş From şimdiki zaman, boş To
+// From + 90 gündür.
+func (s *Service) ExportScheduleIcs(ctx context.Context, req *dialplanv1.ExportScheduleIcsRequest) (*dialplanv1.ExportScheduleIcsResponse, error) {
+	l := logger.ContextLogger(ctx, s.baseLog)
+
+	var v violations
+	from := s.clock.Now()
+	if req.From != "" {
+		parsed, err := time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			v.add("from", "geçersiz zaman %q (beklenen RFC3339)", req.From)
+		}
+		from = parsed
+	}
+	to := from.AddDate(0, 0, icsDefaultExportDays)
+	if req.To != "" {
+		parsed, err := time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			v.add("to", "geçersiz zaman %q (beklenen RFC3339)", req.To)
+		}
+		to = parsed
+	}
+	switch {
+	case !to.After(from):
+		v.add("to", "to, from'dan sonra olmalı")
+	case to.Sub(from) > icsMaxExportDays*24*time.Hour:
+		v.add("to", "en fazla %d günlük aralık dışa aktarılabilir", icsMaxExportDays)
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	schedule, compiled, err := s.loadSchedule(ctx, req.ScheduleId, from, l)
+	if err != nil {
+		return nil, err
+	}
+	loc := compiled.loc
+
+	w := &icsWriter{}
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", icsProductID)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	w.line("X-WR-CALNAME", escapeICSText(schedule.Name+" - Kapalı"))
+	w.line("X-WR-TIMEZONE", loc.String())
+
+	stamp := s.clock.Now().UTC().Format(icsUTCLayout)
+	for _, seg := range compiled.closedSegments(from.In(loc), to.In(loc)) {
+		summary := "Kapalı: " + seg.State
+		if seg.State == StateHoliday {
+			if name, _ := compiled.holidayName(seg.Start); name != "" {
+				summary = "Kapalı: " + name
+			}
+		}
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", fmt.Sprintf("%s-%d@%s", schedule.Id, seg.Start.Unix(), icsUIDDomain))
+		w.line("DTSTAMP", stamp)
+		w.line("DTSTART", seg.Start.UTC().Format(icsUTCLayout))
+		w.line("DTEND", seg.End.UTC().Format(icsUTCLayout))
+		w.line("SUMMARY", escapeICSText(summary))
+		w.line("TRANSP", "TRANSPARENT")
+		w.line("END", "VEVENT")
+	}
+	w.line("END", "VCALENDAR")
+
+	return &dialplanv1.ExportScheduleIcsResponse{IcsData: w.bytes()}, nil
+}
+
+// holidaySet: Takvimde tanımlı tatil günü seçicileri (HolidayRule.dayKey); ithalatta tekrarlar tek aramayla ayıklanır.
+type holidaySet map[string]struct{}
+
+func (sched *ScheduleDefinition) holidaySet() holidaySet {
+	set := make(holidaySet, len(sched.Holidays)+len(sched.HolidayRules))
+	for _, d := range sched.Holidays {
+		set[HolidayRule{Date: d}.dayKey()] = struct{}{}
+	}
+	for _, r := range sched.HolidayRules {
+		set[r.dayKey()] = struct{}{}
+	}
+	return set
+}
+
+// addHolidayRule: Aynı günü seçen bir kural yoksa ekler ve known'a işler; eklendiyse true döner.
+func (sched *ScheduleDefinition) addHolidayRule(rule HolidayRule, known holidaySet) bool {
+	key := rule.dayKey()
+	if _, ok := known[key]; ok {
+		return false
+	}
+	known[key] = struct{}{}
+	sched.HolidayRules = append(sched.HolidayRules, rule)
+	return true
+}
+
+// encodeScheduleDefinition: Güncellenmiş tanımı takvim kaydına yazıp doğrular.
+func encodeScheduleDefinition(schedule *dialplanv1.Schedule, sched *ScheduleDefinition, l zerolog.Logger) error {
+	data, err := json.Marshal(sched)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArgument, err)
+	}
+	schedule.ScheduleJson = string(data)
+	_, err = validateSchedule(schedule, l)
+	return err
+}
@@ -0,0 +1,165 @@
+package dialplan
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+)
+
+// icsRepo: ICS ithalatının okuduğu takvimi ve tek transaction'da yazdıklarını tutan sahte repository.
+type icsRepo struct {
+	Repository
+
+	schedule  *dialplanv1.Schedule
+	imported  *dialplanv1.Schedule
+	overrides []*dialplanv1.ScheduleOverride
+	imports   int
+	existing  int32 // zaten kayıtlı sayılacak override sayısı
+}
+
+func (r *icsRepo) GetSchedule(_ context.Context, id string) (*dialplanv1.Schedule, error) {
+	if r.schedule == nil || r.schedule.Id != id {
+		return nil, ErrNotFound
+	}
+	clone := *r.schedule
+	return &clone, nil
+}
+
+func (r *icsRepo) ImportSchedule(_ context.Context, schedule *dialplanv1.Schedule, overrides []*dialplanv1.ScheduleOverride) (int32, error) {
+	r.imports++
+	r.imported = schedule
+	r.overrides = overrides
+	return int32(len(overrides)) - r.existing, nil
+}
+
+func TestImportScheduleIcs(t *testing.T) {
+	const base = `{"timezone":"Europe/Istanbul","days":{"mon":[{"start":"09:00","end":"18:00"}]},"holidays":["2026-01-01"]}`
+	now := mustTime(t, "2026-01-05T10:00:00Z")
+
+	tests := []struct {
+		name          string
+		ics           []byte
+		existing      int32
+		wantHolidays  int32
+		wantOverrides int32
+		wantRules     []HolidayRule
+		wantReasons   []string
+		wantWarnings  []string
+		wantImport    bool
+	}{
+		{
+			name: "all day single, multi day and yearly",
+			ics: icsDoc(
+				"BEGIN:VEVENT", "SUMMARY:Yılbaşı", "DTSTART;VALUE=DATE:20260101", "END:VEVENT", // düz listede zaten var
+				"BEGIN:VEVENT", "SUMMARY:Bayram", "DTSTART;VALUE=DATE:20260526", "DTEND;VALUE=DATE:20260528", "END:VEVENT",
+				"BEGIN:VEVENT", "SUMMARY:Cumhuriyet", "DTSTART;VALUE=DATE:20261029", "RRULE:FREQ=YEARLY", "END:VEVENT",
+				"BEGIN:VEVENT", "SUMMARY:Şükran", "DTSTART;VALUE=DATE:20261126", "RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=4TH", "END:VEVENT",
+				"BEGIN:VEVENT", "SUMMARY:Cumhuriyet tekrar", "DTSTART;VALUE=DATE:20271029", "RRULE:FREQ=YEARLY", "END:VEVENT",
+			),
+			wantHolidays: 4,
+			wantRules: []HolidayRule{
+				{Name: "Bayram", Date: "2026-05-26"},
+				{Name: "Bayram", Date: "2026-05-27"},
+				{Name: "Cumhuriyet", Annual: "10-29"},
+				{Name: "Şükran", NthWeekday: &NthWeekday{Month: 11, Weekday: "thu", N: 4}},
+			},
+			wantImport: true,
+		},
+		{
+			name: "timed events become overrides",
+			ics: icsDoc(
+				"BEGIN:VEVENT", "SUMMARY:Tatbikat", "DTSTART:20260106T110000Z", "DTEND:20260106T120000Z", "END:VEVENT",
+				"BEGIN:VEVENT", "UID:gecmis", "DTSTART:20260101T110000Z", "DTEND:20260101T120000Z", "END:VEVENT",
+				"BEGIN:VEVENT", "SUMMARY:Haftalık", "DTSTART:20260107T110000Z", "DTEND:20260107T120000Z", "RRULE:FREQ=WEEKLY", "END:VEVENT",
+				"BEGIN:VEVENT", "UID:kayitli", "DTSTART:20260108T110000Z", "DTEND:20260108T120000Z", "END:VEVENT",
+			),
+			existing:      1,
+			wantOverrides: 1,
+			wantReasons:   []string{"ICS: Tatbikat", "ICS: kayitli"},
+			wantWarnings: []string{
+				`"Haftalık" atlandı: tekrarlayan saatli olaylar desteklenmiyor`,
+				"1 saatli olay aynı aralık ve açıklamayla zaten kayıtlı olduğu için atlandı",
+			},
+			wantImport: true,
+		},
+		{
+			name: "long all day event is capped",
+			ics: icsDoc(
+				"BEGIN:VEVENT", "SUMMARY:Uzun", "DTSTART;VALUE=DATE:20260201", "DTEND;VALUE=DATE:20300201", "END:VEVENT",
+			),
+			wantHolidays: icsMaxExportDays,
+			wantWarnings: []string{`"Uzun" yalnızca ilk 366 günüyle içe aktarıldı`},
+			wantImport:   true,
+		},
+		{
+			name: "unsupported recurrence only",
+			ics: icsDoc(
+				"BEGIN:VEVENT", "SUMMARY:Aylık", "DTSTART;VALUE=DATE:20260115", "RRULE:FREQ=MONTHLY", "END:VEVENT",
+			),
+			wantWarnings: []string{`"Aylık" atlandı: desteklenmeyen RRULE FREQ=MONTHLY (yalnızca YEARLY)`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &icsRepo{schedule: &dialplanv1.Schedule{Id: "s1", Name: "Ofis", ScheduleJson: base}, existing: tt.existing}
+			svc := NewService(repo, nil, nil, zerolog.Nop(), WithClock(fixedClock{now}))
+
+			resp, err := svc.ImportScheduleIcs(context.Background(), &dialplanv1.ImportScheduleIcsRequest{ScheduleId: "s1", IcsData: tt.ics})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.ImportedHolidays != tt.wantHolidays || resp.ImportedOverrides != tt.wantOverrides {
+				t.Errorf("imported holidays=%d overrides=%d, want %d/%d", resp.ImportedHolidays, resp.ImportedOverrides, tt.wantHolidays, tt.wantOverrides)
+			}
+			if !reflect.DeepEqual(resp.Warnings, tt.wantWarnings) {
+				t.Errorf("warnings = %q, want %q", resp.Warnings, tt.wantWarnings)
+			}
+			if got := repo.imports == 1; got != tt.wantImport {
+				t.Fatalf("ImportSchedule çağrısı = %d, want import %v", repo.imports, tt.wantImport)
+			}
+			if tt.wantRules != nil {
+				sched, err := decodeSchedule(repo.imported.ScheduleJson)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(sched.HolidayRules, tt.wantRules) {
+					t.Errorf("holiday_rules = %+v, want %+v", sched.HolidayRules, tt.wantRules)
+				}
+			}
+			var reasons []string
+			for _, o := range repo.overrides {
+				reasons = append(reasons, o.Reason)
+				if o.Mode != OverrideForcedClosed || o.ScheduleId != "s1" {
+					t.Errorf("override = %+v", o)
+				}
+			}
+			if !reflect.DeepEqual(reasons, tt.wantReasons) {
+				t.Errorf("override reasons = %q, want %q", reasons, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestImportScheduleIcsErrors(t *testing.T) {
+	repo := &icsRepo{schedule: &dialplanv1.Schedule{Id: "s1", Name: "Ofis", ScheduleJson: `{"timezone":"UTC","days":{}}`}}
+	svc := NewService(repo, nil, nil, zerolog.Nop())
+
+	_, err := svc.ImportScheduleIcs(context.Background(), &dialplanv1.ImportScheduleIcsRequest{ScheduleId: "s1", IcsData: []byte("not ics")})
+	if got := violationFields(t, err); !reflect.DeepEqual(got, []string{"ics_data"}) {
+		t.Errorf("violations = %v, want [ics_data]", got)
+	}
+
+	_, err = svc.ImportScheduleIcs(context.Background(), &dialplanv1.ImportScheduleIcsRequest{ScheduleId: "missing", IcsData: icsDoc()})
+	var resErr *ResourceError
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &resErr) || resErr.ResourceType != ResourceSchedule {
+		t.Errorf("err = %v, want schedule ErrNotFound", err)
+	}
+	if repo.imports != 0 || strings.Contains(repo.schedule.ScheduleJson, "holiday_rules") {
+		t.Error("hatalı ithalat yazmamalı")
+	}
+}
@@ -0,0 +1,175 @@
+// sentiric-dialplan-service/internal/service/dialplan/schedule_override.go
+package dialplan
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
+)
+
+// Override modları. Zorla kapatma, aynı anda geçerli bir zorla açmaya üstün gelir.
+const (
+	OverrideForcedOpen   = "forced_open"
+	OverrideForcedClosed = "forced_closed"
+)
+
+// StateForcedClosed: Aktif bir "forced_closed" override'ının ürettiği durum.
+// route.state_dialplan_ids içinde eşlenmemişse OffHoursDialplanId kullanılır.
+const StateForcedClosed = "forced_closed"
+
+// scheduleOverride: Takvim değerlendirmesinde kullanılan, [Start, End) aralığında geçerli istisna.
+type scheduleOverride struct {
+	Mode   string
+	Reason string
+	Start  time.Time
+	End    time.Time
+}
+
+func (o scheduleOverride) activeAt(t time.Time) bool {
+	return !t.Before(o.Start) && t.Before(o.End)
+}
+
+// overrideState: t anında aktif override varsa ürettiği durumu döner.
+func (c *compiledSchedule) overrideState(t time.Time) (string, bool) {
+	forcedOpen := false
+	for _, o := range c.overrides {
+		if !o.activeAt(t) {
+			continue
+		}
+		if o.Mode == OverrideForcedClosed {
+			return StateForcedClosed, true
+		}
+		forcedOpen = true
+	}
+	if forcedOpen {
+		return StateOpen, true
+	}
+	return "", false
+}
+
+// toScheduleOverride: Kayıtlı override'ı değerlendirme formuna çevirir.
+func toScheduleOverride(o *dialplanv1.ScheduleOverride) (scheduleOverride, error) {
+	start, err := time.Parse(time.RFC3339, o.StartsAt)
+	if err != nil {
+		return scheduleOverride{}, fmt.Errorf("starts_at: %w", err)
+	}
+	end, err := time.Parse(time.RFC3339, o.ExpiresAt)
+	if err != nil {
+		return scheduleOverride{}, fmt.Errorf("expires_at: %w", err)
+	}
+	return scheduleOverride{Mode: o.Mode, Reason: o.Reason, Start: start, End: end}, nil
+}
+
+// validateScheduleOverride: Override kaydını doğrular; starts_at boşsa now atanır.
+func validateScheduleOverride(o *dialplanv1.ScheduleOverride, now time.Time) error {
+	var v violations
+	if o == nil {
+		v.add("override", "override zorunludur")
+		return v.err()
+	}
+	if strings.TrimSpace(o.ScheduleId) == "" {
+		v.add("schedule_id", "schedule_id zorunludur")
+	}
+	if o.Mode != OverrideForcedOpen && o.Mode != OverrideForcedClosed {
+		v.add("mode", "geçersiz mod %q (beklenen: %s, %s)", o.Mode, OverrideForcedOpen, OverrideForcedClosed)
+	}
+	if strings.TrimSpace(o.Reason) == "" {
+		v.add("reason", "reason zorunludur")
+	}
+
+	start := now
+	if o.StartsAt != "" {
+		parsed, err := time.Parse(time.RFC3339, o.StartsAt)
+		if err != nil {
+			v.add("starts_at", "geçersiz zaman %q (beklenen RFC3339)", o.StartsAt)
+		}
+		start = parsed
+	}
+	end, err := time.Parse(time.RFC3339, o.ExpiresAt)
+	switch {
+	case err != nil:
+		v.add("expires_at", "geçersiz zaman %q (beklenen RFC3339)", o.ExpiresAt)
+	case !end.After(start):
+		v.add("expires_at", "expires_at, starts_at'ten sonra olmalı")
+	case !end.After(now):
+		v.add("expires_at", "expires_at geçmişte olamaz")
+	}
+	if err := v.err(); err != nil {
+		return err
+	}
+
+	o.StartsAt = start.UTC().Format(time.RFC3339)
+	o.ExpiresAt = end.UTC().Format(time.RFC3339)
+	return nil
+}
+
+// loadOverrides: at anında veya sonrasında geçerli olan override'ları yükler.
+// Yüklenemezlerse loglanır, takvim override'sız değerlendirilir ve ok false döner.
+func (s *Service) loadOverrides(ctx context.Context, scheduleID string, at time.Time, l zerolog.Logger) (overrides []scheduleOverride, ok bool) {
+	list, err := s.repo.ListScheduleOverrides(ctx, scheduleID, &at)
+	if err != nil {
+		l.Warn().Err(err).
+			Str("event", logger.EventScheduleOverrideLoadFailed).
+			Str("schedule_id", scheduleID).
+			Msg("Takvim istisnaları yüklenemedi, normal takvim uygulanıyor.")
+		return nil, false
+	}
+	overrides = make([]scheduleOverride, 0, len(list))
+	for _, o := range list {
+		so, err := toScheduleOverride(o)
+		if err != nil {
+			l.Error().Err(err).
+				Str("event", logger.EventScheduleOverrideLoadFailed).
+				Str("override_id", o.Id).
+				Msg("Geçersiz takvim istisnası atlandı.")
+			continue
+		}
+		overrides = append(overrides, so)
+	}
+	return overrides, true
+}
+
+func (s *Service) CreateScheduleOverride(ctx context.Context, req *dialplanv1.CreateScheduleOverrideRequest) error {
+	if err := validateScheduleOverride(req.Override, s.clock.Now()); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetSchedule(ctx, req.Override.ScheduleId); err != nil {
+		return resourceErr(err, ResourceSchedule, req.Override.ScheduleId)
+	}
+	if err := s.repo.CreateScheduleOverride(ctx, req.Override); err != nil {
+		return resourceErr(err, ResourceScheduleOverride, req.Override.ScheduleId)
+	}
+	s.schedules.invalidate(req.Override.ScheduleId)
+	return nil
+}
+
+func (s *Service) ListScheduleOverrides(ctx context.Context, req *dialplanv1.ListScheduleOverridesRequest) (*dialplanv1.ListScheduleOverridesResponse, error) {
+	var notExpiredAt *time.Time
+	if !req.IncludeExpired {
+		now := s.clock.Now()
+		notExpiredAt = &now
+	}
+	list, err := s.repo.ListScheduleOverrides(ctx, req.ScheduleId, notExpiredAt)
+	if err != nil {
+		return nil, resourceErr(err, ResourceSchedule, req.ScheduleId)
+	}
+	return &dialplanv1.ListScheduleOverridesResponse{Overrides: list}, nil
+}
+
+func (s *Service) DeleteScheduleOverride(ctx context.Context, id string) error {
+	rows, err := s.repo.DeleteScheduleOverride(ctx, id)
+	// Override'ın hangi takvime ait olduğu bilinmediğinden tüm önbellek düşürülür.
+	s.schedules.purge()
+	return affected(rows, err, ResourceScheduleOverride, id)
+}
+
+// PurgeExpiredOverrides: Süresi dolmuş override'ları siler. Değerlendirme süresi dolanları zaten
+// yok saydığından bu yalnızca tablo temizliğidir; app katmanında periyodik çalıştırılır.
+func (s *Service) PurgeExpiredOverrides(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpiredScheduleOverrides(ctx, s.clock.Now())
+}
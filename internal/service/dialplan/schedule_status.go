@@ -0,0 +1,260 @@
+// sentiric-dialplan-service/internal/service/dialplan/schedule_status.go
+package dialplan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
+)
+
+// transitionHorizonDays: Sonraki açılış/kapanış ve yaklaşan tatil için taranan gün sayısı.
+// Bu süre içinde hiç açılmayan bir takvim için NextOpenAt boş döner.
+const transitionHorizonDays = 366
+
+// ScheduleStatus: Bir takvimin verilen andaki durumu ve sonraki geçişleri (takvimin timezone'unda).
+type ScheduleStatus struct {
+	State       string
+	IsOpen      bool
+	NextOpen    time.Time // IsZero: ufuk içinde açılış yok
+	NextClose   time.Time // IsZero: ufuk içinde kapanış yok
+	Holiday     time.Time // IsZero: ufuk içinde tatil yok
+	HolidayName string
+}
+
+// Status: now anındaki durumu ve sonraki açılış/kapanış anlarını hesaplar.
+// Durum yalnızca aralık sınırlarında ve gün başlarında (tatil geçişleri) değişebildiği için
+// yalnızca bu aday anlar değerlendirilir; her aday stateAt ile aynı kurallarla yorumlanır.
+func (c *compiledSchedule) status(now time.Time) ScheduleStatus {
+	now = now.In(c.loc).Truncate(time.Minute)
+	st := ScheduleStatus{State: c.stateAt(now)}
+	st.IsOpen = st.State == StateOpen
+
+	for _, at := range c.transitionCandidates(now, transitionHorizonDays) {
+		open := c.stateAt(at) == StateOpen
+		switch {
+		case open && st.NextOpen.IsZero() && (!st.IsOpen || !st.NextClose.IsZero()):
+			st.NextOpen = at
+		case !open && st.NextClose.IsZero() && (st.IsOpen || !st.NextOpen.IsZero()):
+			st.NextClose = at
+		}
+		if !st.NextOpen.IsZero() && !st.NextClose.IsZero() {
+			break
+		}
+	}
+
+	st.Holiday, st.HolidayName = c.upcomingHoliday(now, transitionHorizonDays)
+	return st
+}
+
+// transitionCandidates: now'dan sonraki olası durum değişim anları (sıralı, tekrarsız).
+func (c *compiledSchedule) transitionCandidates(now time.Time, days int) []time.Time {
+	seen := make(map[int64]struct{})
+	var out []time.Time
+	add := func(t time.Time) {
+		if !t.After(now) {
+			return
+		}
+		if _, ok := seen[t.Unix()]; ok {
+			return
+		}
+		seen[t.Unix()] = struct{}{}
+		out = append(out, t)
+	}
+
+	// Dün başlayıp bugüne taşan aralıkların bitişi de aday olduğundan tarama bir gün önceden başlar.
+	for i := -1; i <= days; i++ {
+		day := now.AddDate(0, 0, i)
+		add(atMinute(day, 0))
+		ranges, _ := c.rangesFor(day)
+		for _, rng := range ranges {
+			add(atMinute(day, rng.start))
+			add(atMinute(day, rng.end))
+		}
+	}
+	horizon := atMinute(now.AddDate(0, 0, days), 0)
+	for _, o := range c.overrides {
+		for _, t := range []time.Time{o.Start, o.End} {
+			if t.Before(horizon) {
+				add(t.In(now.Location()))
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Before(out[j]) })
+	return out
+}
+
+// scheduleSegment: Takvimin tek bir durumda kaldığı [Start, End) dilimi.
+type scheduleSegment struct {
+	State string
+	Start time.Time
+	End   time.Time
+}
+
+// closedSegments: [from, to) aralığındaki kapalı dilimler. Kapalı durum türü değiştiğinde
+// (örn: after_hours -> weekend) dilim bölünür.
+func (c *compiledSchedule) closedSegments(from, to time.Time) []scheduleSegment {
+	from = from.Truncate(time.Minute)
+	days := int(to.Sub(from)/(24*time.Hour)) + 1
+
+	var out []scheduleSegment
+	current, segStart := c.stateAt(from), from
+	flush := func(end time.Time) {
+		if current != StateOpen {
+			out = append(out, scheduleSegment{State: current, Start: segStart, End: end})
+		}
+	}
+	for _, at := range c.transitionCandidates(from, days) {
+		if !at.Before(to) {
+			break
+		}
+		state := c.stateAt(at)
+		if state == current {
+			continue
+		}
+		flush(at)
+		current, segStart = state, at
+	}
+	flush(to)
+	return out
+}
+
+// upcomingHoliday: from gününden (dahil) itibaren ilk tatil günü ve adı.
+func (c *compiledSchedule) upcomingHoliday(from time.Time, days int) (time.Time, string) {
+	for i := 0; i <= days; i++ {
+		day := from.AddDate(0, 0, i)
+		if name, ok := c.holidayName(day); ok {
+			return atMinute(day, 0), name
+		}
+	}
+	return time.Time{}, ""
+}
+
+// holidayName: Gün tatilse eşleşen ilk kuralın adını döner (düz tarih listesinde ad yoktur).
+func (c *compiledSchedule) holidayName(day time.Time) (string, bool) {
+	if c.isHoliday(day) {
+		return "", true
+	}
+	for i := range c.holidays {
+		if c.holidays[i].matches(day) {
+			return c.holidays[i].name, true
+		}
+	}
+	return "", false
+}
+
+// atMinute: day gününün başından itibaren minute dakika sonrası (1440 üzeri ertesi güne taşar).
+func atMinute(day time.Time, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, minute, 0, 0, day.Location())
+}
+
+// GetScheduleStatus: Takvimin (doğrudan ID veya route numarası üzerinden) verilen andaki durumunu
+// ve sonraki açılış/kapanış zamanlarını döner. At boşsa servis saati kullanılır.
+func (s *Service) GetScheduleStatus(ctx context.Context, req *dialplanv1.GetScheduleStatusRequest) (*dialplanv1.GetScheduleStatusResponse, error) {
+	l := logger.ContextLogger(ctx, s.baseLog)
+
+	var v violations
+	at := s.clock.Now()
+	if req.At != "" {
+		parsed, err := time.Parse(time.RFC3339, req.At)
+		if err != nil {
+			v.add("at", "geçersiz zaman %q (beklenen RFC3339)", req.At)
+		}
+		at = parsed
+	}
+	if (req.ScheduleId == "") == (req.PhoneNumber == "") {
+		v.add("schedule_id", "schedule_id veya phone_number alanlarından tam olarak biri belirtilmelidir")
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	scheduleID := req.ScheduleId
+	if req.PhoneNumber != "" {
+		raw := extractUserPart(req.PhoneNumber)
+		route, _, err := s.findInboundRoute(ctx, s.numbering.Default().Normalize(raw), strings.TrimPrefix(raw, "+"))
+		if err != nil {
+			return nil, resourceErr(err, ResourceInboundRoute, req.PhoneNumber)
+		}
+		if route.ScheduleId == nil || *route.ScheduleId == "" {
+			return nil, resourceErr(fmt.Errorf("%w: route'a bağlı takvim yok", ErrNotFound), ResourceSchedule, route.PhoneNumber)
+		}
+		scheduleID = *route.ScheduleId
+	}
+
+	_, compiled, err := s.loadSchedule(ctx, scheduleID, at, l)
+	if err != nil {
+		return nil, err
+	}
+
+	st := compiled.status(at)
+	resp := &dialplanv1.GetScheduleStatusResponse{
+		ScheduleId:          scheduleID,
+		Timezone:            compiled.loc.String(),
+		State:               st.State,
+		IsOpen:              st.IsOpen,
+		NextOpenAt:          formatInstant(st.NextOpen),
+		NextCloseAt:         formatInstant(st.NextClose),
+		UpcomingHolidayName: st.HolidayName,
+	}
+	if !st.Holiday.IsZero() {
+		resp.UpcomingHolidayDate = st.Holiday.Format(holidayLayout)
+	}
+	return resp, nil
+}
+
+// loadSchedule: Takvimi; referans verdiği tatil takvimleri ve at anında veya sonrasında geçerli
+// override'larıyla birlikte derlenmiş olarak döner. Sonuç süreç içi önbellekten gelebilir; bağımlı
+// kaynaklardan biri okunamadığında derleme yine döner ancak önbelleğe alınmaz.
+func (s *Service) loadSchedule(ctx context.Context, scheduleID string, at time.Time, l zerolog.Logger) (*dialplanv1.Schedule, *compiledSchedule, error) {
+	now := s.clock.Now()
+	if e, ok := s.schedules.get(scheduleID, at, now); ok {
+		return e.schedule, e.compiled, nil
+	}
+
+	schedule, err := s.repo.GetSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, nil, resourceErr(err, ResourceSchedule, scheduleID)
+	}
+	sched, err := decodeSchedule(schedule.ScheduleJson)
+	if err != nil {
+		return nil, nil, resourceErr(fmt.Errorf("kayıtlı takvim çözülemedi: %w", err), ResourceSchedule, scheduleID)
+	}
+	// Geçersiz timezone çağrıyı düşürmez: EvaluateScheduleState ile aynı şekilde UTC'ye düşülür
+	// ve durum derlenmiş takvimde (tzFallback) işaretlenerek karar izine yansıtılır.
+	loc, err := sched.location()
+	tzFallback := ""
+	if err != nil {
+		l.Warn().Err(err).Str("event", logger.EventScheduleParseError).Str("schedule_id", scheduleID).Str("tz", sched.Timezone).Msg("Geçersiz Timezone, UTC kullanılıyor.")
+		loc, tzFallback = time.UTC, sched.Timezone
+	}
+
+	// Override'lar önbellekte yeniden kullanılabilmesi için en erken "şimdi"den itibaren yüklenir.
+	overridesFrom := at
+	if now.Before(overridesFrom) {
+		overridesFrom = now
+	}
+	calendarRules, calendarsOK := s.loadCalendarRules(ctx, sched.Calendars, l)
+	overrides, overridesOK := s.loadOverrides(ctx, scheduleID, overridesFrom, l)
+	compiled := compileSchedule(sched, loc, calendarRules, overrides, l)
+	compiled.tzFallback = tzFallback
+	// Tatil takvimi veya override'lar okunamadıysa eksik (degraded) derleme önbelleğe alınmaz;
+	// bir sonraki çağrı kaynakları yeniden dener.
+	if calendarsOK && overridesOK {
+		s.schedules.put(scheduleID, scheduleCacheEntry{schedule: schedule, compiled: compiled, overridesFrom: overridesFrom}, now)
+	}
+	return schedule, compiled, nil
+}
+
+// formatInstant: Sıfır zaman boş string olarak döner.
+func formatInstant(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
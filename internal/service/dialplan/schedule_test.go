@@ -0,0 +1,446 @@
+package dialplan
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+)
+
+// fixedClock: Testlerde servisin "şimdi"sini sabitler.
+type fixedClock struct{ t time.Time }
+
+func (c fixedClock) Now() time.Time { return c.t }
+
+// scheduleRepo: Yalnızca takvim okumalarını karşılayan sahte repository.
+type scheduleRepo struct {
+	Repository
+
+	mu           sync.Mutex
+	schedules    map[string]string // id -> schedule_json
+	calendars    map[string]string // id -> calendar_json
+	overrides    []*dialplanv1.ScheduleOverride
+	overrideErr  error
+	calendarErr  error
+	scheduleHits int
+}
+
+func (r *scheduleRepo) GetSchedule(_ context.Context, id string) (*dialplanv1.Schedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.scheduleHits++
+	js, ok := r.schedules[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &dialplanv1.Schedule{Id: id, ScheduleJson: js}, nil
+}
+
+func (r *scheduleRepo) ListScheduleOverrides(_ context.Context, scheduleID string, notExpiredAt *time.Time) ([]*dialplanv1.ScheduleOverride, error) {
+	if r.overrideErr != nil {
+		return nil, r.overrideErr
+	}
+	var out []*dialplanv1.ScheduleOverride
+	for _, o := range r.overrides {
+		end, _ := time.Parse(time.RFC3339, o.ExpiresAt)
+		if o.ScheduleId == scheduleID && (notExpiredAt == nil || end.After(*notExpiredAt)) {
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+func (r *scheduleRepo) GetHolidayCalendar(_ context.Context, id string) (*dialplanv1.HolidayCalendar, error) {
+	if r.calendarErr != nil {
+		return nil, r.calendarErr
+	}
+	js, ok := r.calendars[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &dialplanv1.HolidayCalendar{Id: id, CalendarJson: js}, nil
+}
+
+func mustTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	at, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return at
+}
+
+func TestEvaluateScheduleState(t *testing.T) {
+	const (
+		overnight = `{"timezone":"Europe/Istanbul","days":{"fri":[{"start":"22:00","end":"06:00"}]}}`
+		endOfDay  = `{"timezone":"UTC","days":{"mon":[{"start":"18:00","end":"24:00"}],"tue":[{"start":"09:00","end":"17:00"}]}}`
+		allDay    = `{"timezone":"UTC","days":{"sat":[{"all_day":true}]}}`
+		malformed = `{"timezone":"UTC","days":{"mon":[{"start":"9:00","end":"18:00"},{"start":"10:00","end":"25:00"}]}}`
+		lunch     = `{"timezone":"UTC","days":{"mon":[{"start":"09:00","end":"18:00"},{"start":"12:00","end":"13:00","state":"lunch_break"}]}}`
+		holidays  = `{"timezone":"Europe/Istanbul",
+			"days":{"thu":[{"start":"09:00","end":"18:00"}],"fri":[{"start":"09:00","end":"18:00"}]},
+			"holidays":["2026-01-01"],
+			"holiday_rules":[
+				{"name":"Yılbaşı arifesi","date":"2026-12-31","hours":[{"start":"09:00","end":"13:00"}]},
+				{"name":"Cumhuriyet Bayramı","annual":"10-29"},
+				{"name":"Şükran Günü","nth_weekday":{"month":11,"weekday":"thu","n":4},"hours":[{"start":"10:00","end":"12:00"}]},
+				{"name":"Mayıs son cuma","nth_weekday":{"month":5,"weekday":"fri","n":-1}}
+			]}`
+		holidaySpill = `{"timezone":"Europe/Istanbul","days":{"fri":[{"start":"22:00","end":"06:00"}]},"holidays":["2026-01-02"]}`
+		berlinSpring = `{"timezone":"Europe/Berlin","days":{"sun":[{"start":"01:00","end":"04:00"}],"mon":[{"start":"09:00","end":"17:00"}]}}`
+		berlinFall   = `{"timezone":"Europe/Berlin","days":{"sun":[{"start":"00:00","end":"02:30"}]}}`
+		newYork      = `{"timezone":"America/New_York","days":{"sat":[{"start":"22:00","end":"06:00"}]}}`
+	)
+
+	tests := []struct {
+		name     string
+		schedule string
+		at       string
+		want     string
+	}{
+		{"no schedule always open", "", "2026-01-01T00:00:00Z", StateOpen},
+
+		{"overnight before start", overnight, "2026-01-02T21:59:00+03:00", StateAfterHours},
+		{"overnight start", overnight, "2026-01-02T22:00:00+03:00", StateOpen},
+		{"overnight spill into saturday", overnight, "2026-01-03T05:59:00+03:00", StateOpen},
+		{"overnight end exclusive", overnight, "2026-01-03T06:00:00+03:00", StateWeekend},
+
+		{"24:00 last minute", endOfDay, "2026-01-05T23:59:00Z", StateOpen},
+		{"24:00 next day midnight", endOfDay, "2026-01-06T00:00:00Z", StateAfterHours},
+		{"24:00 before start", endOfDay, "2026-01-05T17:59:00Z", StateAfterHours},
+
+		{"all day start", allDay, "2026-01-03T00:00:00Z", StateOpen},
+		{"all day end", allDay, "2026-01-03T23:59:00Z", StateOpen},
+		{"all day next day", allDay, "2026-01-04T00:00:00Z", StateWeekend},
+
+		// Hatalı aralıklar gece yarısı sayılmaz, atlanır.
+		{"malformed not midnight", malformed, "2026-01-05T00:00:00Z", StateAfterHours},
+		{"malformed skipped", malformed, "2026-01-05T10:30:00Z", StateAfterHours},
+
+		{"lunch break wins over open", lunch, "2026-01-05T12:30:00Z", StateLunchBreak},
+		{"lunch break end", lunch, "2026-01-05T13:00:00Z", StateOpen},
+
+		{"full day holiday", holidays, "2026-01-01T10:00:00+03:00", StateHoliday},
+		{"partial holiday open hours", holidays, "2026-12-31T10:00:00+03:00", StateOpen},
+		{"partial holiday after hours", holidays, "2026-12-31T13:00:00+03:00", StateHoliday},
+		{"annual holiday", holidays, "2026-10-29T10:00:00+03:00", StateHoliday},
+		{"annual holiday next year", holidays, "2027-10-29T10:00:00+03:00", StateHoliday},
+		{"nth weekday holiday open", holidays, "2026-11-26T11:00:00+03:00", StateOpen},
+		{"nth weekday holiday closed", holidays, "2026-11-26T09:30:00+03:00", StateHoliday},
+		{"last weekday of month", holidays, "2026-05-29T10:00:00+03:00", StateHoliday},
+		{"not last weekday of month", holidays, "2026-05-22T10:00:00+03:00", StateOpen},
+		{"regular day unaffected", holidays, "2026-11-19T09:30:00+03:00", StateOpen},
+		{"holiday drops overnight spill", holidaySpill, "2026-01-03T03:00:00+03:00", StateWeekend},
+
+		// Europe/Berlin 2026-03-29 02:00 CET -> 03:00 CEST
+		{"spring forward before gap", berlinSpring, "2026-03-29T00:30:00Z", StateOpen}, // 01:30 CET
+		{"spring forward after gap", berlinSpring, "2026-03-29T01:30:00Z", StateOpen},  // 03:30 CEST
+		{"spring forward end", berlinSpring, "2026-03-29T02:00:00Z", StateAfterHours},  // 04:00 CEST
+		{"summer time opening", berlinSpring, "2026-03-30T07:00:00Z", StateOpen},       // 09:00 CEST
+		{"summer time before opening", berlinSpring, "2026-03-30T06:59:00Z", StateAfterHours},
+
+		// Europe/Berlin 2026-10-25 03:00 CEST -> 02:00 CET: 02:15 iki kez yaşanır.
+		{"fall back first 02:15", berlinFall, "2026-10-25T00:15:00Z", StateOpen},
+		{"fall back second 02:15", berlinFall, "2026-10-25T01:15:00Z", StateOpen},
+		{"fall back 02:45 CET", berlinFall, "2026-10-25T01:45:00Z", StateAfterHours},
+
+		// America/New_York 2026-03-08 02:00 EST -> 03:00 EDT; kapanış duvar saatine göre 06:00 EDT.
+		{"overnight across spring forward", newYork, "2026-03-08T09:59:00Z", StateOpen},
+		{"overnight ends at wall clock", newYork, "2026-03-08T10:00:00Z", StateWeekend},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EvaluateScheduleState(tt.schedule, mustTime(t, tt.at), zerolog.Nop()); got != tt.want {
+				t.Errorf("EvaluateScheduleState(%s) = %q, want %q", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsWorkingHourCalendarRules(t *testing.T) {
+	const schedule = `{"timezone":"Europe/Istanbul","days":{"thu":[{"start":"09:00","end":"18:00"},{"start":"12:00","end":"13:00","state":"lunch_break"}]}}`
+	calendar := []HolidayRule{{Name: "Ulusal Egemenlik", Annual: "04-23"}}
+
+	tests := []struct {
+		name string
+		at   string
+		want bool
+	}{
+		{"open", "2026-04-16T10:00:00+03:00", true},
+		{"named state is closed", "2026-04-16T12:30:00+03:00", false},
+		{"shared calendar holiday", "2026-04-23T10:00:00+03:00", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsWorkingHour(schedule, mustTime(t, tt.at), zerolog.Nop(), calendar...); got != tt.want {
+				t.Errorf("IsWorkingHour(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialplanForState(t *testing.T) {
+	full := &dialplanv1.InboundRoute{
+		ActiveDialplanId:   toPtr("DP_OPEN"),
+		OffHoursDialplanId: toPtr("DP_CLOSED"),
+		StateDialplanIds:   map[string]string{StateLunchBreak: "DP_LUNCH", StateHoliday: "DP_HOLIDAY", "empty": ""},
+	}
+	binary := &dialplanv1.InboundRoute{ActiveDialplanId: toPtr("DP_OPEN"), OffHoursDialplanId: toPtr("DP_CLOSED")}
+	activeOnly := &dialplanv1.InboundRoute{ActiveDialplanId: toPtr("DP_OPEN"), OffHoursDialplanId: toPtr("")}
+
+	tests := []struct {
+		name  string
+		route *dialplanv1.InboundRoute
+		state string
+		want  string
+	}{
+		{"mapped state", full, StateLunchBreak, "DP_LUNCH"},
+		{"mapped holiday", full, StateHoliday, "DP_HOLIDAY"},
+		{"unmapped closed state falls back to off hours", full, StateWeekend, "DP_CLOSED"},
+		{"empty mapping ignored", full, "empty", "DP_CLOSED"},
+		{"open without mapping", full, StateOpen, "DP_OPEN"},
+		{"binary open", binary, StateOpen, "DP_OPEN"},
+		{"binary after hours", binary, StateAfterHours, "DP_CLOSED"},
+		{"binary custom state", binary, StateLunchBreak, "DP_CLOSED"},
+		{"no off hours dialplan", activeOnly, StateAfterHours, "DP_OPEN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := safeString(dialplanForState(tt.route, tt.state)); got != tt.want {
+				t.Errorf("dialplanForState(%s) = %q, want %q", tt.state, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleOverrides(t *testing.T) {
+	sched, err := decodeSchedule(`{"timezone":"UTC","days":{"mon":[{"start":"09:00","end":"17:00"}]}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	overrides := []scheduleOverride{
+		{Mode: OverrideForcedClosed, Start: mustTime(t, "2026-01-05T10:00:00Z"), End: mustTime(t, "2026-01-05T11:00:00Z")},
+		{Mode: OverrideForcedOpen, Start: mustTime(t, "2026-01-05T10:30:00Z"), End: mustTime(t, "2026-01-05T12:00:00Z")},
+		{Mode: OverrideForcedOpen, Start: mustTime(t, "2026-01-10T10:00:00Z"), End: mustTime(t, "2026-01-10T12:00:00Z")},
+	}
+	c := compileSchedule(sched, time.UTC, nil, overrides, zerolog.Nop())
+
+	tests := []struct {
+		at   string
+		want string
+	}{
+		{"2026-01-05T09:59:00Z", StateOpen},
+		{"2026-01-05T10:00:00Z", StateForcedClosed},
+		{"2026-01-05T10:45:00Z", StateForcedClosed}, // zorla kapatma, örtüşen zorla açmaya üstün gelir
+		{"2026-01-05T11:00:00Z", StateOpen},
+		{"2026-01-10T11:00:00Z", StateOpen}, // cumartesi, zorla açık
+		{"2026-01-10T12:00:00Z", StateWeekend},
+	}
+	for _, tt := range tests {
+		if got := c.stateAt(mustTime(t, tt.at)); got != tt.want {
+			t.Errorf("stateAt(%s) = %q, want %q", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestValidateScheduleOverride(t *testing.T) {
+	now := mustTime(t, "2026-01-05T10:00:00Z")
+	tests := []struct {
+		name       string
+		override   *dialplanv1.ScheduleOverride
+		wantFields []string
+		wantStart  string
+	}{
+		{
+			name:      "starts now by default",
+			override:  &dialplanv1.ScheduleOverride{ScheduleId: "s1", Mode: OverrideForcedClosed, Reason: "yangın tatbikatı", ExpiresAt: "2026-01-05T15:00:00+03:00"},
+			wantStart: "2026-01-05T10:00:00Z",
+		},
+		{
+			name:       "missing fields",
+			override:   &dialplanv1.ScheduleOverride{Mode: "closed", ExpiresAt: "yarın"},
+			wantFields: []string{"schedule_id", "mode", "reason", "expires_at"},
+		},
+		{
+			name:       "expires before start",
+			override:   &dialplanv1.ScheduleOverride{ScheduleId: "s1", Mode: OverrideForcedOpen, Reason: "kampanya", StartsAt: "2026-01-11T10:00:00Z", ExpiresAt: "2026-01-11T09:00:00Z"},
+			wantFields: []string{"expires_at"},
+		},
+		{
+			name:       "already expired",
+			override:   &dialplanv1.ScheduleOverride{ScheduleId: "s1", Mode: OverrideForcedOpen, Reason: "kampanya", StartsAt: "2026-01-04T10:00:00Z", ExpiresAt: "2026-01-04T12:00:00Z"},
+			wantFields: []string{"expires_at"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := violationFields(t, validateScheduleOverride(tt.override, now))
+			if !reflect.DeepEqual(got, tt.wantFields) {
+				t.Fatalf("violations = %v, want %v", got, tt.wantFields)
+			}
+			if tt.wantStart != "" && tt.override.StartsAt != tt.wantStart {
+				t.Errorf("StartsAt = %q, want %q", tt.override.StartsAt, tt.wantStart)
+			}
+		})
+	}
+}
+
+func TestGetScheduleStatusFixedClock(t *testing.T) {
+	repo := &scheduleRepo{
+		schedules: map[string]string{
+			"istanbul": `{"timezone":"Europe/Istanbul","days":{"mon":[{"start":"09:00","end":"18:00"}],"fri":[{"start":"09:00","end":"18:00"}]},"calendars":["tr"]}`,
+			"berlin":   `{"timezone":"Europe/Berlin","days":{"mon":[{"start":"09:00","end":"17:00"}]}}`,
+		},
+		calendars: map[string]string{
+			"tr": `{"rules":[{"name":"Yılbaşı","annual":"01-01"}]}`,
+		},
+		overrides: []*dialplanv1.ScheduleOverride{
+			{Id: "o1", ScheduleId: "istanbul", Mode: OverrideForcedClosed, StartsAt: "2026-01-09T12:00:00Z", ExpiresAt: "2026-01-09T13:00:00Z"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		scheduleID string
+		now        string
+		at         string
+		state      string
+		nextOpen   string
+		nextClose  string
+		holiday    string
+	}{
+		{
+			name: "open friday", scheduleID: "istanbul", now: "2026-01-02T14:00:00Z",
+			state: StateOpen, nextClose: "2026-01-02T18:00:00+03:00", nextOpen: "2026-01-05T09:00:00+03:00", holiday: "2027-01-01",
+		},
+		{
+			name: "forced closed override", scheduleID: "istanbul", now: "2026-01-09T12:30:00Z",
+			state: StateForcedClosed, nextOpen: "2026-01-09T16:00:00+03:00", nextClose: "2026-01-09T18:00:00+03:00", holiday: "2027-01-01",
+		},
+		{
+			name: "closed on shared calendar holiday", scheduleID: "istanbul", now: "2027-01-01T08:00:00Z",
+			state: StateHoliday, nextOpen: "2027-01-04T09:00:00+03:00", nextClose: "2027-01-04T18:00:00+03:00", holiday: "2027-01-01",
+		},
+		{
+			name: "request at overrides clock", scheduleID: "istanbul", now: "2026-01-02T14:00:00Z", at: "2026-01-05T07:00:00Z",
+			state: StateOpen, nextClose: "2026-01-05T18:00:00+03:00", nextOpen: "2026-01-09T09:00:00+03:00", holiday: "2027-01-01",
+		},
+		{
+			name: "next open across DST", scheduleID: "berlin", now: "2026-03-28T17:00:00Z",
+			state: StateWeekend, nextOpen: "2026-03-30T09:00:00+02:00", nextClose: "2026-03-30T17:00:00+02:00",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(repo, nil, nil, zerolog.Nop(), WithClock(fixedClock{mustTime(t, tt.now)}))
+			resp, err := svc.GetScheduleStatus(context.Background(), &dialplanv1.GetScheduleStatusRequest{ScheduleId: tt.scheduleID, At: tt.at})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.State != tt.state || resp.NextOpenAt != tt.nextOpen || resp.NextCloseAt != tt.nextClose || resp.UpcomingHolidayDate != tt.holiday {
+				t.Errorf("got state=%s open=%s close=%s holiday=%s; want state=%s open=%s close=%s holiday=%s",
+					resp.State, resp.NextOpenAt, resp.NextCloseAt, resp.UpcomingHolidayDate,
+					tt.state, tt.nextOpen, tt.nextClose, tt.holiday)
+			}
+		})
+	}
+}
+
+func TestLoadScheduleSkipsCacheWhenDegraded(t *testing.T) {
+	const js = `{"timezone":"UTC","days":{"mon":[{"start":"09:00","end":"17:00"}]},"calendars":["tr"]}`
+	now := mustTime(t, "2026-01-05T10:00:00Z")
+
+	tests := []struct {
+		name        string
+		overrideErr error
+		calendarErr error
+		wantHits    int
+	}{
+		{name: "healthy", wantHits: 1},
+		{name: "overrides unavailable", overrideErr: ErrDatabaseUnavailable, wantHits: 2},
+		{name: "calendar unavailable", calendarErr: ErrDatabaseUnavailable, wantHits: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &scheduleRepo{
+				schedules:   map[string]string{"s1": js},
+				calendars:   map[string]string{"tr": `{"rules":[]}`},
+				overrideErr: tt.overrideErr,
+				calendarErr: tt.calendarErr,
+			}
+			svc := NewService(repo, nil, nil, zerolog.Nop(), WithClock(fixedClock{now}))
+			for i := 0; i < 2; i++ {
+				if _, _, err := svc.loadSchedule(context.Background(), "s1", now, zerolog.Nop()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if repo.scheduleHits != tt.wantHits {
+				t.Errorf("GetSchedule çağrı sayısı = %d, want %d", repo.scheduleHits, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestLoadScheduleInvalidTimezoneFallsBackToUTC(t *testing.T) {
+	now := mustTime(t, "2026-01-05T10:00:00Z")
+	repo := &scheduleRepo{schedules: map[string]string{"s1": `{"timezone":"Mars/Olympus","days":{"mon":[{"start":"09:00","end":"17:00"}]}}`}}
+	svc := NewService(repo, nil, nil, zerolog.Nop(), WithClock(fixedClock{now}))
+
+	_, compiled, err := svc.loadSchedule(context.Background(), "s1", now, zerolog.Nop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compiled.loc != time.UTC || compiled.tzFallback != "Mars/Olympus" {
+		t.Errorf("loc = %s, tzFallback = %q; want UTC, Mars/Olympus", compiled.loc, compiled.tzFallback)
+	}
+	if got := compiled.stateAt(now); got != StateOpen {
+		t.Errorf("stateAt = %q, want %q", got, StateOpen)
+	}
+}
+
+func TestEvaluationTime(t *testing.T) {
+	now := mustTime(t, "2026-01-02T15:04:00Z")
+	at := mustTime(t, "2026-01-09T18:05:00+03:00")
+	svc := NewService(nil, nil, nil, zerolog.Nop(), WithClock(fixedClock{now}))
+
+	if got := svc.evaluationTime(ResolveOptions{}); !got.Equal(now) {
+		t.Errorf("evaluationTime() = %s, want clock %s", got, now)
+	}
+	opts := ResolveOptions{SimulateAt: at}
+	if got := svc.evaluationTime(opts); !got.Equal(at) {
+		t.Errorf("evaluationTime(simulate) = %s, want %s", got, at)
+	}
+	if !opts.sideEffectFree() {
+		t.Error("simülasyon yan etkisiz olmalı")
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		in            string
+		allowEndOfDay bool
+		want          int
+		wantErr       bool
+	}{
+		{"00:00", false, 0, false},
+		{"09:30", false, 570, false},
+		{"23:59", false, 1439, false},
+		{"24:00", true, minutesPerDay, false},
+		{"24:00", false, 0, true},
+		{"24:01", true, 0, true},
+		{"9:00", false, 0, true},
+		{"09:60", false, 0, true},
+		{"", false, 0, true},
+		{"ab:cd", false, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseClock(tt.in, tt.allowEndOfDay)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseClock(%q, %v) = %d, %v; want %d, err=%v", tt.in, tt.allowEndOfDay, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
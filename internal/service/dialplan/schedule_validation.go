@@ -0,0 +1,313 @@
+// sentiric-dialplan-service/internal/service/dialplan/schedule_validation.go
+package dialplan
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
+)
+
+// FieldViolation: Alan bazlı doğrulama hatası (errdetails.BadRequest_FieldViolation karşılığı).
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError: Bir veya daha fazla alan doğrulamasının başarısız olduğunu belirtir.
+// errors.Is(err, ErrInvalidArgument) true döner.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, fmt.Sprintf("%s: %s", v.Field, v.Description))
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidArgument, strings.Join(parts, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidArgument
+}
+
+// violations: Doğrulama hatalarını biriktiren yardımcı.
+type violations []FieldViolation
+
+func (v *violations) add(field, format string, args ...interface{}) {
+	*v = append(*v, FieldViolation{Field: field, Description: fmt.Sprintf(format, args...)})
+}
+
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v}
+}
+
+// Validate: Takvim tanımını yazma anında doğrular. Çağrı anında (IsWorkingHour)
+// keşfedilip "7/24 açık" davranışına düşen hataların önüne geçer.
+func (sched *ScheduleDefinition) Validate() []FieldViolation {
+	var v violations
+
+	if sched.Timezone == "" {
+		v.add("schedule_json.timezone", "timezone zorunludur (IANA, örn: Europe/Istanbul)")
+	} else if _, err := time.LoadLocation(sched.Timezone); err != nil {
+		v.add("schedule_json.timezone", "geçersiz IANA timezone %q", sched.Timezone)
+	}
+
+	days := make([]string, 0, len(sched.Days))
+	for day := range sched.Days {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	for _, day := range days {
+		ranges := sched.Days[day]
+		if !isWeekdayKey(day) {
+			v.add("schedule_json.days."+day, "geçersiz gün anahtarı (beklenen: %s)", strings.Join(weekdayKeys[:], ","))
+			continue
+		}
+		validateRanges("schedule_json.days."+day, ranges, &v)
+	}
+
+	for i, holiday := range sched.Holidays {
+		if _, err := time.Parse(holidayLayout, holiday); err != nil {
+			v.add(fmt.Sprintf("schedule_json.holidays[%d]", i), "geçersiz tarih %q (beklenen YYYY-MM-DD)", holiday)
+		}
+	}
+
+	for i, rule := range sched.HolidayRules {
+		rule.validate(fmt.Sprintf("schedule_json.holiday_rules[%d]", i), &v)
+	}
+
+	for i, id := range sched.Calendars {
+		if strings.TrimSpace(id) == "" {
+			v.add(fmt.Sprintf("schedule_json.calendars[%d]", i), "boş takvim referansı")
+		}
+	}
+
+	return v
+}
+
+// validateRanges: Zaman aralıklarını doğrular; field, ihlallerin raporlanacağı JSON yoludur.
+func validateRanges(field string, ranges []TimeRange, v *violations) {
+	for i, rng := range ranges {
+		f := fmt.Sprintf("%s[%d]", field, i)
+		if rng.State != "" && !isStateName(rng.State) {
+			v.add(f+".state", "geçersiz durum adı %q (beklenen: küçük harf, rakam ve '_')", rng.State)
+		}
+		if rng.AllDay {
+			if rng.Start != "" || rng.End != "" {
+				v.add(f, "all_day ile start/end birlikte kullanılamaz")
+			}
+			continue
+		}
+		// Gece yarısını aşan aralıklar (end < start) geçerlidir; start == end değildir.
+		if _, _, err := rng.minutes(); err != nil {
+			v.add(f, "%v", err)
+		}
+	}
+}
+
+// validateSchedule: Schedule kaydını doğrular ve timezone kolonu ile JSON içindeki
+// timezone'u uzlaştırır: biri boşsa diğerinden doldurulur, ikisi farklıysa reddedilir.
+// Doğrulanan tanım, referans kontrolleri için geri döner.
+// Bilinmeyen JSON alanları mevcut istemcileri kırmamak için reddedilmez; loglanır ve kanonik formda düşer.
+func validateSchedule(s *dialplanv1.Schedule, l zerolog.Logger) (*ScheduleDefinition, error) {
+	var v violations
+	if s == nil {
+		v.add("schedule", "schedule zorunludur")
+		return nil, v.err()
+	}
+	if strings.TrimSpace(s.Name) == "" {
+		v.add("name", "name zorunludur")
+	}
+
+	var sched ScheduleDefinition
+	if err := json.Unmarshal([]byte(s.ScheduleJson), &sched); err != nil {
+		v.add("schedule_json", "geçersiz JSON: %v", err)
+		return nil, v.err()
+	}
+	logUnknownFields(l, "schedule_json", []byte(s.ScheduleJson), &sched, s.Id)
+
+	switch {
+	case sched.Timezone == "" && s.Timezone != "":
+		sched.Timezone = s.Timezone
+	case s.Timezone == "":
+		s.Timezone = sched.Timezone
+	case s.Timezone != sched.Timezone:
+		v.add("timezone", "timezone kolonu (%q) ile schedule_json.timezone (%q) farklı", s.Timezone, sched.Timezone)
+	}
+
+	v = append(v, sched.Validate()...)
+	if len(v) > 0 {
+		return nil, v.err()
+	}
+
+	// JSON her zaman timezone'u içerecek şekilde kanonik forma yazılır.
+	normalized, err := json.Marshal(&sched)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArgument, err)
+	}
+	s.ScheduleJson = string(normalized)
+	return &sched, nil
+}
+
+// validateHolidayCalendar: Tatil takvimi kaydını doğrular ve calendar_json'u kanonik forma yazar.
+// Bilinmeyen alanlar validateSchedule'daki gibi loglanır.
+func validateHolidayCalendar(c *dialplanv1.HolidayCalendar, l zerolog.Logger) error {
+	var v violations
+	if c == nil {
+		v.add("calendar", "calendar zorunludur")
+		return v.err()
+	}
+	if strings.TrimSpace(c.Name) == "" {
+		v.add("name", "name zorunludur")
+	}
+
+	var def HolidayCalendarDefinition
+	if err := json.Unmarshal([]byte(c.CalendarJson), &def); err != nil {
+		v.add("calendar_json", "geçersiz JSON: %v", err)
+		return v.err()
+	}
+	logUnknownFields(l, "calendar_json", []byte(c.CalendarJson), &def, c.Id)
+
+	v = append(v, def.Validate()...)
+	if len(v) > 0 {
+		return v.err()
+	}
+
+	normalized, err := json.Marshal(&def)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArgument, err)
+	}
+	c.CalendarJson = string(normalized)
+	return nil
+}
+
+// logUnknownFields: data içinde def'in JSON karşılığı olmayan alanları (yazım hatası olabilir) uyarı olarak loglar.
+func logUnknownFields(l zerolog.Logger, field string, data []byte, def interface{}, id string) {
+	var unknown []string
+	collectUnknownFields(data, reflect.TypeOf(def), field, &unknown)
+	if len(unknown) == 0 {
+		return
+	}
+	l.Warn().
+		Str("event", logger.EventUnknownFieldsIgnored).
+		Str("id", id).
+		Strs("fields", unknown).
+		Msg("Tanımdaki bilinmeyen alanlar yok sayıldı.")
+}
+
+// collectUnknownFields: data'yı t tipine göre gezer; struct'ta json etiketi bulunmayan anahtarları
+// path ile (örn: "schedule_json.days.mon[0].stat") out'a ekler.
+func collectUnknownFields(data []byte, t reflect.Type, path string, out *[]string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(data, &obj) != nil {
+			return
+		}
+		fields := make(map[string]reflect.Type, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if name != "" && name != "-" {
+				fields[name] = t.Field(i).Type
+			}
+		}
+		for _, k := range sortedKeys(obj) {
+			ft, ok := fields[k]
+			if !ok {
+				*out = append(*out, path+"."+k)
+				continue
+			}
+			collectUnknownFields(obj[k], ft, path+"."+k, out)
+		}
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return
+		}
+		for i, item := range items {
+			collectUnknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), out)
+		}
+	case reflect.Map:
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(data, &obj) != nil {
+			return
+		}
+		for _, k := range sortedKeys(obj) {
+			collectUnknownFields(obj[k], t.Elem(), path+"."+k, out)
+		}
+	}
+}
+
+func sortedKeys(obj map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// validateRoute: route.state_dialplan_ids anahtarlarının geçerli durum adı, değerlerinin dolu olduğunu,
+// eşzamanlı çağrı limitinin negatif olmadığını ve kuyruk önceliğinin (boş olabilir) geçerli bir sınıf
+// olduğunu doğrular.
+func validateRoute(route *dialplanv1.InboundRoute) error {
+	var v violations
+	if route.MaxConcurrentCalls < 0 {
+		v.add("max_concurrent_calls", "negatif olamaz (0 => sınırsız)")
+	}
+	if route.QueuePriority != "" {
+		route.QueuePriority = validatePriority("queue_priority", route.QueuePriority, &v)
+	}
+	states := make([]string, 0, len(route.StateDialplanIds))
+	for state := range route.StateDialplanIds {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	for _, state := range states {
+		field := "state_dialplan_ids." + state
+		if !isStateName(state) {
+			v.add(field, "geçersiz durum adı (beklenen: küçük harf, rakam ve '_')")
+		}
+		if strings.TrimSpace(route.StateDialplanIds[state]) == "" {
+			v.add(field, "dialplan id boş olamaz")
+		}
+	}
+	return v.err()
+}
+
+// isStateName: Takvim durumu adları route.state_dialplan_ids anahtarlarıyla eşleşir (örn: lunch_break).
+func isStateName(state string) bool {
+	if state == "" || len(state) > 64 {
+		return false
+	}
+	for _, c := range state {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+func isWeekdayKey(day string) bool {
+	for _, k := range weekdayKeys {
+		if k == day {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package dialplan
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+)
+
+func violationFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var valErr *ValidationError
+	if !errors.As(err, &valErr) {
+		t.Fatalf("hata ValidationError değil: %v", err)
+	}
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("errors.Is(err, ErrInvalidArgument) = false")
+	}
+	fields := make([]string, 0, len(valErr.Violations))
+	for _, v := range valErr.Violations {
+		fields = append(fields, v.Field)
+	}
+	return fields
+}
+
+func TestValidateSchedule(t *testing.T) {
+	tests := []struct {
+		name       string
+		schedule   *dialplanv1.Schedule
+		wantFields []string
+	}{
+		{
+			name:     "valid",
+			schedule: &dialplanv1.Schedule{Name: "Ofis", ScheduleJson: `{"timezone":"Europe/Istanbul","days":{"mon":[{"start":"09:00","end":"18:00"}]}}`},
+		},
+		{
+			name:     "overnight and all day",
+			schedule: &dialplanv1.Schedule{Name: "Gece", ScheduleJson: `{"timezone":"UTC","days":{"fri":[{"start":"22:00","end":"06:00"}],"sat":[{"all_day":true}]}}`},
+		},
+		{
+			name:       "nil schedule",
+			wantFields: []string{"schedule"},
+		},
+		{
+			name:       "invalid json",
+			schedule:   &dialplanv1.Schedule{Name: "x", ScheduleJson: `{"timezone":`},
+			wantFields: []string{"schedule_json"},
+		},
+		{
+			name: "field violations",
+			schedule: &dialplanv1.Schedule{ScheduleJson: `{"timezone":"Mars/Olympus","days":{
+				"mon":[{"start":"9:00","end":"18:00"},{"start":"10:00","end":"10:00"}],
+				"sat":[{"all_day":true,"start":"09:00"}],
+				"xyz":[{"start":"09:00","end":"18:00"}]},
+				"holidays":["2026-13-01"]}`},
+			wantFields: []string{
+				"name",
+				"schedule_json.timezone",
+				"schedule_json.days.mon[0]",
+				"schedule_json.days.mon[1]",
+				"schedule_json.days.sat[0]",
+				"schedule_json.days.xyz",
+				"schedule_json.holidays[0]",
+			},
+		},
+		{
+			name: "holiday rules",
+			schedule: &dialplanv1.Schedule{Name: "x", ScheduleJson: `{"timezone":"UTC","days":{},"holiday_rules":[
+				{"annual":"02-29"},
+				{"annual":"13-01"},
+				{"date":"2026-01-01","annual":"01-01"},
+				{"nth_weekday":{"month":11,"weekday":"thu","n":6}}]}`},
+			wantFields: []string{"schedule_json.holiday_rules[1].annual", "schedule_json.holiday_rules[2]", "schedule_json.holiday_rules[3].nth_weekday.n"},
+		},
+		{
+			name:       "missing timezone",
+			schedule:   &dialplanv1.Schedule{Name: "x", ScheduleJson: `{"days":{}}`},
+			wantFields: []string{"schedule_json.timezone"},
+		},
+		{
+			name:     "unknown fields logged not rejected",
+			schedule: &dialplanv1.Schedule{Name: "x", ScheduleJson: `{"timezone":"UTC","note":"eski istemci","days":{"mon":[{"start":"09:00","end":"18:00","label":"sabah"}]}}`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateSchedule(tt.schedule, zerolog.Nop())
+			got := violationFields(t, err)
+			if !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("violations = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestValidateScheduleTimezone(t *testing.T) {
+	tests := []struct {
+		name       string
+		column     string
+		json       string
+		wantColumn string
+		wantFields []string
+	}{
+		{name: "column fills json", column: "Europe/Istanbul", json: `{"days":{}}`, wantColumn: "Europe/Istanbul"},
+		{name: "json fills column", column: "", json: `{"timezone":"UTC","days":{}}`, wantColumn: "UTC"},
+		{name: "same", column: "UTC", json: `{"timezone":"UTC","days":{}}`, wantColumn: "UTC"},
+		{name: "mismatch", column: "UTC", json: `{"timezone":"Europe/Istanbul","days":{}}`, wantFields: []string{"timezone"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &dialplanv1.Schedule{Name: "x", Timezone: tt.column, ScheduleJson: tt.json}
+			_, err := validateSchedule(s, zerolog.Nop())
+			got := violationFields(t, err)
+			if !reflect.DeepEqual(got, tt.wantFields) {
+				t.Fatalf("violations = %v, want %v", got, tt.wantFields)
+			}
+			if tt.wantFields != nil {
+				return
+			}
+			if s.Timezone != tt.wantColumn {
+				t.Errorf("Timezone = %q, want %q", s.Timezone, tt.wantColumn)
+			}
+			// Kanonik JSON timezone'u her zaman içerir.
+			var def ScheduleDefinition
+			if err := json.Unmarshal([]byte(s.ScheduleJson), &def); err != nil || def.Timezone != tt.wantColumn {
+				t.Errorf("ScheduleJson = %s, want timezone %q", s.ScheduleJson, tt.wantColumn)
+			}
+		})
+	}
+}
+
+func TestCollectUnknownFields(t *testing.T) {
+	const js = `{"timezone":"UTC","note":"x","days":{"mon":[{"start":"09:00","end":"18:00","label":"a"}],"tue":[{"all_day":true}]},"holidays":["2026-01-01"]}`
+	var got []string
+	collectUnknownFields([]byte(js), reflect.TypeOf(&ScheduleDefinition{}), "schedule_json", &got)
+	want := []string{"schedule_json.days.mon[0].label", "schedule_json.note"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unknown = %v, want %v", got, want)
+	}
+}
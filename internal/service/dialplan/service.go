@@ -5,6 +5,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
 	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
@@ -13,36 +17,133 @@ import (
 	grpchelper "github.com/sentiric/sentiric-dialplan-service/internal/grpc"
 	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
-	"google.golang.org/grpc/status"
 )
 
 const (
 	DialplanSystemFailsafe     = "DP_SYSTEM_FAILSAFE"
 	DialplanSystemWelcomeGuest = "DP_SYSTEM_AI_GUEST"
-	ActionPlayAnnouncement     = "PLAY_STATIC_ANNOUNCEMENT" // [ARCH-COMPLIANCE FIX]
-	AnnouncementSystemError    = "ANNOUNCE_SYSTEM_ERROR"
-	NilUUID                    = "00000000-0000-0000-0000-000000000000"
+	// DialplanSystemAnonymousReject: Route'ta rejection_dialplan_id tanımlı değilse gizli arayanlara uygulanır.
+	DialplanSystemAnonymousReject = "DP_SYSTEM_ANONYMOUS_REJECT"
+	// DialplanSystemCallerBlocked: Route'ta blocked_caller_dialplan_id tanımlı değilse engelli arayanlara uygulanır.
+	DialplanSystemCallerBlocked = "DP_SYSTEM_CALLER_BLOCKED"
+	// DialplanSystemThrottled: Rate limit aşımında, yapılandırmada başka bir dialplan verilmemişse uygulanır.
+	DialplanSystemThrottled = "DP_SYSTEM_THROTTLED"
+	// DialplanSystemLinesBusy: Route'ta lines_busy_dialplan_id tanımlı değilse kapasite dolduğunda uygulanır.
+	DialplanSystemLinesBusy       = "DP_SYSTEM_LINES_BUSY"
+	ActionPlayAnnouncement        = "PLAY_STATIC_ANNOUNCEMENT" // [ARCH-COMPLIANCE FIX]
+	AnnouncementSystemError       = "ANNOUNCE_SYSTEM_ERROR"
+	AnnouncementAnonymousRejected = "ANNOUNCE_ANONYMOUS_REJECTED"
+	AnnouncementCallerBlocked     = "ANNOUNCE_CALLER_BLOCKED"
+	AnnouncementThrottled         = "ANNOUNCE_SYSTEM_BUSY"
+	AnnouncementLinesBusy         = "ANNOUNCE_LINES_BUSY"
+	NilUUID                       = "00000000-0000-0000-0000-000000000000"
+)
+
+// Hata detaylarında (errdetails.ResourceInfo) kullanılan kaynak tipleri.
+const (
+	ResourceInboundRoute     = "inbound_route"
+	ResourceDialplan         = "dialplan"
+	ResourceQueue            = "queue"
+	ResourceQueueMember      = "queue_member"
+	ResourceAgentSkill       = "agent_skill"
+	ResourceQueueCall        = "queue_call"
+	ResourceSchedule         = "schedule"
+	ResourceHoliday          = "holiday_calendar"
+	ResourceScheduleOverride = "schedule_override"
+	ResourceCallerList       = "caller_list_entry"
+	ResourceTenantCallLimit  = "tenant_call_limit"
+	ResourceDecisionTrace    = "decision_trace"
 )
 
 type Service struct {
 	repo       Repository
 	userClient userv1.UserServiceClient
 	userCache  *cache.UserCache
+	numbering  *Numbering
+	clock      Clock
+	traceStore TraceStore
+	traceRate  float64
+	schedules  *scheduleCache
 	baseLog    zerolog.Logger
+
+	rateLimiter RateLimiter
+	rateLimits  RateLimits
+	callTracker CallTracker
+	callTTL     time.Duration
+	agentStates AgentStateStore
+
+	queueTracker QueueTracker
+	serviceLevel time.Duration
 }
 
-func NewService(repo Repository, userClient userv1.UserServiceClient, userCache *cache.UserCache, log zerolog.Logger) *Service {
-	return &Service{repo: repo, userClient: userClient, userCache: userCache, baseLog: log}
+// Option: Service için opsiyonel bağımlılıkları ayarlar.
+type Option func(*Service)
+
+// WithNumbering: Varsayılan (TR) yerine ülke/tenant bazlı numara normalizasyonu kullanır.
+func WithNumbering(n *Numbering) Option {
+	return func(s *Service) {
+		if n != nil {
+			s.numbering = n
+		}
+	}
 }
 
-func (s *Service) ResolveDialplan(ctx context.Context, caller, destination string) (*dialplanv1.ResolveDialplanResponse, error) {
+func NewService(repo Repository, userClient userv1.UserServiceClient, userCache *cache.UserCache, log zerolog.Logger, opts ...Option) *Service {
+	s := &Service{
+		repo:       repo,
+		userClient: userClient,
+		userCache:  userCache,
+		numbering:  &Numbering{defaultPlan: numberingPlans[DefaultCountry]},
+		clock:      systemClock{},
+		schedules:  newScheduleCache(DefaultScheduleCacheTTL),
+		baseLog:    log,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ResolveDialplan: Çağrı için uygulanacak dialplan'ı çözer ve karar izini (DecisionTrace) oluşturur.
+// opts.Trace verilmişse iz çağırana da döner; iz yalnızca opts.PersistTrace ile ya da örneklenirse saklanır.
+func (s *Service) ResolveDialplan(ctx context.Context, caller, destination string, opts ResolveOptions) (*dialplanv1.ResolveDialplanResponse, error) {
+	trace := opts.Trace
+	if trace == nil {
+		trace = &DecisionTrace{}
+	}
+	trace.TraceID = logger.ExtractTraceIDFromContext(ctx)
+	trace.Caller = caller
+	trace.Destination = destination
+	trace.EvaluatedAt = s.evaluationTime(opts)
+	trace.Simulated = opts.Simulated()
+	trace.DryRun = opts.DryRun
+
+	resp, err := s.resolve(ctx, caller, destination, opts, trace)
+	trace.finish(resp, err)
+	if !opts.sideEffectFree() && (opts.PersistTrace || s.sampleTrace()) {
+		s.storeTrace(ctx, trace)
+	}
+	return resp, err
+}
+
+func (s *Service) resolve(ctx context.Context, caller, destination string, opts ResolveOptions, trace *DecisionTrace) (*dialplanv1.ResolveDialplanResponse, error) {
 	l := logger.ContextLogger(ctx, s.baseLog)
-	traceID := logger.ExtractTraceIDFromContext(ctx)
+	if opts.Simulated() {
+		l = l.With().Bool("simulation", true).Time("simulate_at", opts.SimulateAt).Logger()
+	}
+	if opts.DryRun {
+		l = l.With().Bool("dry_run", true).Logger()
+	}
+	traceID := trace.TraceID
 
-	cleanDestination := normalizePhoneNumber(extractUserPart(destination))
-	cleanCaller := normalizePhoneNumber(extractUserPart(caller))
+	// Route bulunmadan tenant bilinmediği için hedef numara varsayılan planla normalize edilir.
+	// Ülke kuralı yanlış tahmin ederse ham rakamlar da denenir (örn: "4930123456" bir TR numarası değildir).
+	rawDestination := extractUserPart(destination)
+	rawCaller := extractUserPart(caller)
+	defaultPlan := s.numbering.Default()
+	cleanDestination := defaultPlan.Normalize(rawDestination)
+	cleanCaller := defaultPlan.Normalize(rawCaller)
 
 	contactType := "phone"
 	if len(cleanCaller) <= 5 && cleanCaller != "anonymous" {
@@ -57,79 +158,180 @@ func (s *Service) ResolveDialplan(ctx context.Context, caller, destination strin
 			Str("sip.destination", cleanDestination)).
 		Msg("📞 ResolveDialplan İsteği İşleniyor")
 
+	// Adım 0: Flood koruması. Hedef limiti DB ve User Service'e gitmeden uygulanır; arayan ve tenant
+	// limitleri tenant bilindikten sonra (route bulununca) uygulanır.
+	// Yan etkisiz modlarda (simülasyon / dry-run) sayaçlar artırılmaz.
+	if !opts.sideEffectFree() && s.rateLimited(ctx, RateLimitScopeDestination, cleanDestination, s.rateLimits.PerDestination, trace, l) {
+		throttledRoute := &dialplanv1.InboundRoute{
+			PhoneNumber: cleanDestination, TenantId: "system", DefaultLanguageCode: "tr",
+		}
+		return s.throttleResponse(ctx, l, trace, throttledRoute)
+	}
+
 	// Adım 1: Gelen numaraya (destination) göre uygun inbound route'u bulalım
 	// [ARCH-COMPLIANCE FIX] Veritabanı hatalarında sistemi ölü bırakmak (500 Error) YASAKTIR. Failsafe akışa yönlendirilecek şekilde hata yönetimi uygulanır.
-	route, err := s.repo.FindInboundRouteByPhone(ctx, cleanDestination)
+	route, matchedDestination, err := s.findInboundRoute(ctx, cleanDestination, strings.TrimPrefix(rawDestination, "+"))
 	if err != nil {
 		if errors.Is(err, ErrNotFound) {
+			trace.record(TraceStepRouteLookup, "not_found", "destination", cleanDestination)
 			l.Warn().Str("event", logger.EventRouteNotFound).Msg("🚫 Route bulunamadı. Misafir akışına yönlendiriliyor.")
 			guestRoute := &dialplanv1.InboundRoute{
 				PhoneNumber: cleanDestination, TenantId: "system", DefaultLanguageCode: "tr",
 			}
-			return s.buildFailsafeResponse(ctx, l, DialplanSystemWelcomeGuest, nil, nil, guestRoute)
+			return s.buildFailsafeResponse(ctx, l, trace, DialplanSystemWelcomeGuest, nil, nil, guestRoute)
 		}
 
 		// [ARCH-COMPLIANCE FIX] Veritabanı yoksa HATA VERME, FAILSAFE DÖN!
+		trace.record(TraceStepRouteLookup, "error", "destination", cleanDestination, "error", err.Error())
 		l.Error().Err(err).Str("event", logger.EventRouteQueryFailed).Msg("❌ DB Kapalı. Hardcoded Failsafe tetikleniyor.")
 		dummyRoute := &dialplanv1.InboundRoute{
 			PhoneNumber: cleanDestination, TenantId: "system", DefaultLanguageCode: "tr",
 		}
-		return s.buildFailsafeResponse(ctx, l, DialplanSystemFailsafe, nil, nil, dummyRoute)
+		return s.buildFailsafeResponse(ctx, l, trace, DialplanSystemFailsafe, nil, nil, dummyRoute)
+	}
+
+	trace.record(TraceStepRouteLookup, "matched",
+		"destination", matchedDestination, "route", route.PhoneNumber, "tenant_id", route.TenantId)
+	if !opts.sideEffectFree() && s.rateLimited(ctx, RateLimitScopeTenant, route.TenantId, s.rateLimits.PerTenant, trace, l) {
+		return s.throttleResponse(ctx, l, trace, route)
+	}
+	cleanDestination = matchedDestination
+	// Arayan numara, route'un ait olduğu tenant'ın ülke kurallarıyla yeniden normalize edilir.
+	callerPlan := s.numbering.ForTenant(route.TenantId)
+	cleanCaller = callerPlan.Normalize(rawCaller)
+
+	// Arayan sayacı tenant kapsamlıdır: aynı numaranın farklı tenant'ları araması birbirini sınırlamaz.
+	// Tüm gizli arayanlar tek bir sayacı paylaşmasın diye numarasız arayan sayılmaz.
+	if !opts.sideEffectFree() && cleanCaller != "" && cleanCaller != "anonymous" &&
+		s.rateLimited(ctx, RateLimitScopeCaller, route.TenantId+":"+cleanCaller, s.rateLimits.PerCaller, trace, l) {
+		return s.throttleResponse(ctx, l, trace, route)
 	}
 
-	if route.BlockAnonymous && (cleanCaller == "" || cleanCaller == "anonymous") {
-		l.Warn().Str("event", logger.EventAnonymousBlocked).Msg("🚫 Gizli numara engellendi.")
-		return nil, status.Errorf(codes.PermissionDenied, "Anonymous calls blocked")
+	// Gizli arayan: boş/"anonymous" numaranın yanında "restricted" gibi işaretler ve SIP Privacy başlığı.
+	// [ARCH-COMPLIANCE] Engellenen çağrı hata ile düşürülmez; ret anonsu çalan bir dialplan'a yönlendirilir.
+	withheld := cleanCaller == "" || cleanCaller == "anonymous" || isWithheldCaller(caller, opts.CallerPrivacy)
+	if route.BlockAnonymous && withheld {
+		rejectionID := DialplanSystemAnonymousReject
+		if id := safeString(route.RejectionDialplanId); id != "" {
+			rejectionID = id
+		}
+		trace.record(TraceStepAnonymousCheck, "rejected",
+			"caller", caller, "privacy", opts.CallerPrivacy, "rejection_dialplan_id", rejectionID)
+		l.Warn().
+			Str("event", logger.EventAnonymousBlocked).
+			Str("rejection_dialplan_id", rejectionID).
+			Msg("🚫 Gizli numara engellendi, ret akışına yönlendiriliyor.")
+		return s.buildFallbackResponse(ctx, l, trace, rejectionID, AnnouncementAnonymousRejected, nil, nil, route)
+	}
+	trace.record(TraceStepAnonymousCheck, "allowed",
+		"caller", cleanCaller, "withheld", strconv.FormatBool(withheld), "block_anonymous", strconv.FormatBool(route.BlockAnonymous))
+
+	// Arayan engelleme / izin listeleri kullanıcı sorgusundan önce uygulanır.
+	// Liste okunamazsa çağrı engellenmez (fail-open); karar izine hata olarak düşer.
+	// Gizli arayan da taranır: izin listesi olan bir hatta numarasız arayan izinli değildir.
+	screen, err := s.screenCaller(ctx, route, cleanCaller, s.evaluationTime(opts))
+	verdict, entry := screen.verdict, screen.entry
+	if err != nil {
+		trace.record(TraceStepCallerListCheck, "error", "caller", cleanCaller, "error", err.Error())
+		l.Warn().Err(err).
+			Str("event", logger.EventCallerListCheckFailed).
+			Msg("Arayan listeleri okunamadı, çağrı engellenmeden devam ediliyor.")
+	} else if verdict == callerListBlocked || verdict == callerListNotAllowed {
+		blockedID := DialplanSystemCallerBlocked
+		if id := safeString(route.BlockedCallerDialplanId); id != "" {
+			blockedID = id
+		}
+		trace.record(TraceStepCallerListCheck, verdict,
+			"caller", cleanCaller, "entry_id", entry.GetId(), "reason", entry.GetReason(), "blocked_caller_dialplan_id", blockedID)
+		l.Warn().
+			Str("event", logger.EventCallerBlocked).
+			Str("verdict", verdict).
+			Str("entry_id", entry.GetId()).
+			Str("blocked_caller_dialplan_id", blockedID).
+			Msg("🚫 Arayan listeye takıldı, engelleme akışına yönlendiriliyor.")
+		return s.buildFallbackResponse(ctx, l, trace, blockedID, AnnouncementCallerBlocked, nil, nil, route)
+	} else {
+		trace.record(TraceStepCallerListCheck, verdict, "caller", cleanCaller, "entry_id", entry.GetId())
 	}
 
 	if route.IsMaintenanceMode {
+		trace.record(TraceStepMaintenanceCheck, "active", "failsafe_dialplan_id", safeString(route.FailsafeDialplanId))
 		l.Warn().Str("event", logger.EventMaintenanceMode).Msg("🔧 Hat bakım modunda.")
-		return s.buildFailsafeResponse(ctx, l, safeString(route.FailsafeDialplanId), nil, nil, route)
+		return s.buildFailsafeResponse(ctx, l, trace, safeString(route.FailsafeDialplanId), nil, nil, route)
+	}
+	trace.record(TraceStepMaintenanceCheck, "inactive")
+
+	// Eşzamanlı çağrı kapasitesi: Çağrı burada sayılır; ReleaseCall veya TTL ile düşer.
+	if s.callTracker != nil && !opts.sideEffectFree() && s.overCapacity(ctx, route, callID(opts, traceID), trace, l) {
+		busyID := DialplanSystemLinesBusy
+		if id := safeString(route.LinesBusyDialplanId); id != "" {
+			busyID = id
+		}
+		return s.buildFallbackResponse(ctx, l, trace, busyID, AnnouncementLinesBusy, nil, nil, route)
 	}
 
 	targetDialplanID := route.ActiveDialplanId
 
 	if route.ScheduleId != nil && *route.ScheduleId != "" {
-		schedule, err := s.repo.GetSchedule(ctx, *route.ScheduleId)
+		evaluatedAt := s.evaluationTime(opts)
+		schedule, compiled, err := s.loadSchedule(ctx, *route.ScheduleId, evaluatedAt, l)
 		if err == nil {
-			isOpen := IsWorkingHour(schedule.ScheduleJson, l)
+			state := compiled.stateAt(evaluatedAt.In(compiled.loc))
+			targetDialplanID = dialplanForState(route, state)
 
-			if !isOpen {
+			if state != StateOpen {
 				l.Info().
 					Str("event", logger.EventOffHoursActive).
 					Str("schedule", schedule.Name).
+					Str("state", state).
 					Msg("🌙 Mesai dışı (Off-Hours) kuralı devrede.")
-				if route.OffHoursDialplanId != nil && *route.OffHoursDialplanId != "" {
-					targetDialplanID = route.OffHoursDialplanId
-				}
 			} else {
 				l.Debug().
 					Str("event", logger.EventWorkingHoursActive).
 					Str("schedule", schedule.Name).
 					Msg("☀️ Mesai içi (Working-Hours) kuralı devrede.")
 			}
+			details := []string{"schedule_id", schedule.Id, "evaluated_at", evaluatedAt.Format(time.RFC3339), "target_dialplan_id", safeString(targetDialplanID)}
+			if compiled.tzFallback != "" {
+				details = append(details, "timezone_fallback", compiled.tzFallback)
+			}
+			trace.record(TraceStepScheduleEvaluation, state, details...)
 		} else {
 			l.Warn().Err(err).
 				Str("event", logger.EventScheduleLoadFailed).
 				Str("schedule_id", *route.ScheduleId).
 				Msg("Zamanlama planı yüklenemedi, varsayılan akışa devam ediliyor.")
+			trace.record(TraceStepScheduleEvaluation, "load_failed", "schedule_id", *route.ScheduleId, "error", err.Error())
 		}
+	} else {
+		trace.record(TraceStepScheduleEvaluation, "skipped", "reason", "no_schedule")
 	}
 
 	var activePlan *dialplanv1.Dialplan
 	if targetDialplanID != nil {
 		if p, err := s.repo.FindDialplanByID(ctx, *targetDialplanID); err == nil {
 			activePlan = p
+			trace.record(TraceStepDialplanFetch, "found", "dialplan_id", *targetDialplanID)
+		} else {
+			trace.record(TraceStepDialplanFetch, "not_found", "dialplan_id", *targetDialplanID, "error", err.Error())
 		}
+	} else {
+		trace.record(TraceStepDialplanFetch, "skipped", "reason", "no_active_dialplan")
 	}
 
 	userReqCtx := metadata.AppendToOutgoingContext(ctx, "x-trace-id", traceID)
 	var matchedUser *userv1.User
 	var matchedContact *userv1.Contact
+	// returningCaller: Profili bu çağrıdan önce de var olan (önbellekte veya User Service'te bulunan) arayan.
+	var returningCaller bool
 
 	if s.userCache != nil {
 		matchedUser, _ = s.userCache.GetUser(ctx, cleanCaller, l)
 	}
+	if matchedUser != nil {
+		returningCaller = true
+		trace.record(TraceStepUserLookup, "cache_hit", "caller", cleanCaller, "user_id", matchedUser.Id)
+	}
 
 	if matchedUser == nil {
 		l.Debug().Str("event", logger.EventUserCacheMiss).Msg("Cache miss, User Service sorgulanıyor")
@@ -142,16 +344,45 @@ func (s *Service) ResolveDialplan(ctx context.Context, caller, destination strin
 		userRes, err := grpchelper.CallWithTimeout(userReqCtx, findUserFunc)
 		if err == nil && userRes.GetUser() != nil {
 			matchedUser = userRes.GetUser()
+			returningCaller = true
+			trace.record(TraceStepUserLookup, "found", "caller", cleanCaller, "contact_type", contactType, "user_id", matchedUser.Id)
 			for _, contact := range matchedUser.Contacts {
-				if normalizePhoneNumber(contact.ContactValue) == cleanCaller {
+				if callerPlan.Normalize(contact.ContactValue) == cleanCaller {
 					matchedContact = contact
 					break
 				}
 			}
-			if s.userCache != nil {
+			if s.userCache != nil && !opts.sideEffectFree() {
 				_ = s.userCache.SetUser(ctx, cleanCaller, matchedUser, l)
 			}
+		} else if opts.sideEffectFree() {
+			// Simülasyon / dry-run: User Service'e yazılmaz, oluşturulacak profilin önizlemesi döner.
+			preview := &ProvisionPreview{
+				TenantID:              route.TenantId,
+				UserType:              "guest",
+				Name:                  "Guest_" + cleanCaller,
+				ContactType:           contactType,
+				ContactValue:          cleanCaller,
+				PreferredLanguageCode: route.DefaultLanguageCode,
+			}
+			trace.WouldProvision = preview
+			trace.record(TraceStepUserLookup, "not_found", "caller", cleanCaller, "contact_type", contactType)
+			trace.record(TraceStepUserProvisioning, "would_provision", "tenant_id", route.TenantId, "name", preview.Name)
+			l.Info().
+				Str("event", logger.EventAutoProvisionSkipped).
+				Str("phone", cleanCaller).
+				Str("tenant", route.TenantId).
+				Msg("🧪 Yan etkisiz mod: otomatik profil oluşturma atlandı.")
+
+			matchedUser = &userv1.User{
+				Id:                    NilUUID,
+				Name:                  toPtr(preview.Name),
+				TenantId:              preview.TenantID,
+				UserType:              preview.UserType,
+				PreferredLanguageCode: toPtr(preview.PreferredLanguageCode),
+			}
 		} else {
+			trace.record(TraceStepUserLookup, "not_found", "caller", cleanCaller, "contact_type", contactType)
 			l.Info().
 				Str("event", logger.EventAutoProvisionStart).
 				Str("phone", cleanCaller).
@@ -175,13 +406,14 @@ func (s *Service) ResolveDialplan(ctx context.Context, caller, destination strin
 
 			if createErr == nil && createRes.GetUser() != nil {
 				matchedUser = createRes.GetUser()
+				trace.record(TraceStepUserProvisioning, "created", "tenant_id", route.TenantId, "user_id", matchedUser.Id)
 				l.Info().
 					Str("event", logger.EventAutoProvisionSuccess).
 					Str("user_id", matchedUser.Id).
 					Msg("✅ Otomatik profil başarıyla oluşturuldu.")
 
 				for _, contact := range matchedUser.Contacts {
-					if normalizePhoneNumber(contact.ContactValue) == cleanCaller {
+					if callerPlan.Normalize(contact.ContactValue) == cleanCaller {
 						matchedContact = contact
 						break
 					}
@@ -190,6 +422,7 @@ func (s *Service) ResolveDialplan(ctx context.Context, caller, destination strin
 					_ = s.userCache.SetUser(ctx, cleanCaller, matchedUser, l)
 				}
 			} else {
+				trace.record(TraceStepUserProvisioning, "failed_ghost_profile", "tenant_id", route.TenantId)
 				l.Error().Err(createErr).
 					Str("event", logger.EventAutoProvisionFail).
 					Msg("❌ Misafir kullanıcı DB'ye yazılamadı! Ghost profille devam ediliyor.")
@@ -204,7 +437,7 @@ func (s *Service) ResolveDialplan(ctx context.Context, caller, destination strin
 		}
 	} else {
 		for _, contact := range matchedUser.Contacts {
-			if normalizePhoneNumber(contact.ContactValue) == cleanCaller {
+			if callerPlan.Normalize(contact.ContactValue) == cleanCaller {
 				matchedContact = contact
 				break
 			}
@@ -218,25 +451,33 @@ func (s *Service) ResolveDialplan(ctx context.Context, caller, destination strin
 			Str("dialplan.action", activePlan.Action.Action).
 			Msg("✅ Dialplan başarıyla çözüldü")
 
+		action := s.applyQueueOverflow(ctx, activePlan.Action, trace, l)
+		action = s.applyQueuePriority(action, route, screen.matches, matchedUser, returningCaller, trace, l)
 		return &dialplanv1.ResolveDialplanResponse{
 			DialplanId:     activePlan.Id,
 			TenantId:       activePlan.TenantId,
-			Action:         activePlan.Action,
+			Action:         withQueueSkills(action, matchedUser, route),
 			MatchedUser:    matchedUser,
 			MatchedContact: matchedContact,
 			InboundRoute:   route,
 		}, nil
 	}
 
-	return s.buildFailsafeResponse(ctx, l, DialplanSystemWelcomeGuest, matchedUser, matchedContact, route)
+	return s.buildFailsafeResponse(ctx, l, trace, DialplanSystemWelcomeGuest, matchedUser, matchedContact, route)
 }
 
-func (s *Service) buildFailsafeResponse(ctx context.Context, l zerolog.Logger, planID string, user *userv1.User, contact *userv1.Contact, route *dialplanv1.InboundRoute) (*dialplanv1.ResolveDialplanResponse, error) {
+func (s *Service) buildFailsafeResponse(ctx context.Context, l zerolog.Logger, trace *DecisionTrace, planID string, user *userv1.User, contact *userv1.Contact, route *dialplanv1.InboundRoute) (*dialplanv1.ResolveDialplanResponse, error) {
 	if planID == "" {
 		planID = DialplanSystemFailsafe
 	}
+	return s.buildFallbackResponse(ctx, l, trace, planID, AnnouncementSystemError, user, contact, route)
+}
+
+// buildFallbackResponse: planID'yi yükler; DB'de yoksa announcementID'yi çalan koda gömülü bir aksiyon döner.
+func (s *Service) buildFallbackResponse(ctx context.Context, l zerolog.Logger, trace *DecisionTrace, planID, announcementID string, user *userv1.User, contact *userv1.Contact, route *dialplanv1.InboundRoute) (*dialplanv1.ResolveDialplanResponse, error) {
 	plan, err := s.repo.FindDialplanByID(ctx, planID)
 	if err != nil {
+		trace.record(TraceStepFailsafeFallback, "hardcoded", "requested_dialplan_id", planID, "error", err.Error())
 		// [ARCH-COMPLIANCE FIX] Veritabanı bozulduğunda veya seeder çalışmadığında
 		// sistemi ölü bırakmak (500 Error) YASAKTIR.
 		// Hardcoded bir acil durum anonsu (Action) üretilerek çağrı güvence altına alınır.
@@ -246,7 +487,7 @@ func (s *Service) buildFailsafeResponse(ctx context.Context, l zerolog.Logger, p
 			Action: ActionPlayAnnouncement,
 			Type:   dialplanv1.ActionType_ACTION_TYPE_PLAY_STATIC_ANNOUNCEMENT,
 			ActionData: map[string]string{
-				"announcement_id": AnnouncementSystemError,
+				"announcement_id": announcementID,
 				"record":          "true",
 			},
 		}
@@ -261,32 +502,107 @@ func (s *Service) buildFailsafeResponse(ctx context.Context, l zerolog.Logger, p
 		}, nil
 	}
 
+	trace.record(TraceStepFailsafeFallback, "dialplan", "dialplan_id", plan.Id)
 	return &dialplanv1.ResolveDialplanResponse{
 		DialplanId: plan.Id, TenantId: plan.TenantId, Action: plan.Action,
 		MatchedUser: user, MatchedContact: contact, InboundRoute: route,
 	}, nil
 }
 
+// findInboundRoute: Aday numaralar için önce birebir (exact) eşleşme, bulunamazsa numarayı
+// kapsayan prefix/range route'ları arasından en spesifik olanı (longest-prefix) döner.
+// Eşleşen route ile birlikte eşleşmeyi sağlayan numara da döner.
+func (s *Service) findInboundRoute(ctx context.Context, numbers ...string) (*dialplanv1.InboundRoute, string, error) {
+	numbers = uniqueNonEmpty(numbers)
+	for _, number := range numbers {
+		route, err := s.repo.FindInboundRouteByPhone(ctx, number)
+		if err == nil {
+			return route, number, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return nil, number, err
+		}
+	}
+
+	for _, number := range numbers {
+		candidates, err := s.repo.FindPatternRoutesForNumber(ctx, number)
+		if err != nil {
+			return nil, number, err
+		}
+		if best := selectMostSpecificRoute(number, candidates); best != nil {
+			return best, number, nil
+		}
+	}
+	return nil, "", ErrNotFound
+}
+
 // CRUD operasyonları
 
 func (s *Service) CreateInboundRoute(ctx context.Context, route *dialplanv1.InboundRoute) error {
-	route.PhoneNumber = normalizePhoneNumber(route.PhoneNumber)
-	return s.repo.CreateInboundRoute(ctx, route)
+	if err := validateRoute(route); err != nil {
+		return err
+	}
+	p, err := ParseRoutePattern(route.PhoneNumber, s.numbering.ForTenant(route.TenantId))
+	if err != nil {
+		return err
+	}
+	route.PhoneNumber = p.String()
+	return resourceErr(s.repo.CreateInboundRoute(ctx, route), ResourceInboundRoute, route.PhoneNumber)
 }
 
 func (s *Service) GetInboundRoute(ctx context.Context, phoneNumber string) (*dialplanv1.InboundRoute, error) {
-	return s.repo.FindInboundRouteByPhone(ctx, normalizePhoneNumber(phoneNumber))
+	keys, err := s.routeKeyCandidates(phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		route, err := s.repo.FindInboundRouteByPhone(ctx, key)
+		if err == nil || !errors.Is(err, ErrNotFound) {
+			return route, resourceErr(err, ResourceInboundRoute, key)
+		}
+	}
+	return nil, resourceErr(ErrNotFound, ResourceInboundRoute, keys[0])
 }
 
 func (s *Service) UpdateInboundRoute(ctx context.Context, route *dialplanv1.InboundRoute) error {
-	route.PhoneNumber = normalizePhoneNumber(route.PhoneNumber)
-	_, err := s.repo.UpdateInboundRoute(ctx, route)
-	return err
+	if err := validateRoute(route); err != nil {
+		return err
+	}
+	p, err := ParseRoutePattern(route.PhoneNumber, s.numbering.ForTenant(route.TenantId))
+	if err != nil {
+		return err
+	}
+	route.PhoneNumber = p.String()
+	rows, err := s.repo.UpdateInboundRoute(ctx, route)
+	return affected(rows, err, ResourceInboundRoute, route.PhoneNumber)
 }
 
 func (s *Service) DeleteInboundRoute(ctx context.Context, phoneNumber string) error {
-	_, err := s.repo.DeleteInboundRoute(ctx, normalizePhoneNumber(phoneNumber))
-	return err
+	keys, err := s.routeKeyCandidates(phoneNumber)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		rows, err := s.repo.DeleteInboundRoute(ctx, key)
+		if err != nil || rows > 0 {
+			return affected(rows, err, ResourceInboundRoute, key)
+		}
+	}
+	return resourceErr(ErrNotFound, ResourceInboundRoute, keys[0])
+}
+
+// routeKeyCandidates: Get/Delete isteklerinde tenant bilinmediği için anahtar hem varsayılan
+// planla normalize edilmiş hem de saklandığı kanonik haliyle denenir.
+func (s *Service) routeKeyCandidates(phoneNumber string) ([]string, error) {
+	p, err := ParseRoutePattern(phoneNumber, s.numbering.Default())
+	if err != nil {
+		return nil, err
+	}
+	keys := []string{p.String()}
+	if stored, err := parseStoredRoutePattern(strings.TrimPrefix(strings.TrimSpace(phoneNumber), "+")); err == nil {
+		keys = append(keys, stored.String())
+	}
+	return uniqueNonEmpty(keys), nil
 }
 
 func (s *Service) ListInboundRoutes(ctx context.Context, req *dialplanv1.ListInboundRoutesRequest) (*dialplanv1.ListInboundRoutesResponse, error) {
@@ -300,22 +616,23 @@ func (s *Service) ListInboundRoutes(ctx context.Context, req *dialplanv1.ListInb
 
 func (s *Service) CreateDialplan(ctx context.Context, req *dialplanv1.CreateDialplanRequest) error {
 	bytes, _ := json.Marshal(req.Dialplan.Action.ActionData)
-	return s.repo.CreateDialplan(ctx, req.Dialplan, bytes)
+	return resourceErr(s.repo.CreateDialplan(ctx, req.Dialplan, bytes), ResourceDialplan, req.Dialplan.Id)
 }
 
 func (s *Service) GetDialplan(ctx context.Context, id string) (*dialplanv1.Dialplan, error) {
-	return s.repo.FindDialplanByID(ctx, id)
+	dp, err := s.repo.FindDialplanByID(ctx, id)
+	return dp, resourceErr(err, ResourceDialplan, id)
 }
 
 func (s *Service) UpdateDialplan(ctx context.Context, req *dialplanv1.UpdateDialplanRequest) error {
 	bytes, _ := json.Marshal(req.Dialplan.Action.ActionData)
-	_, err := s.repo.UpdateDialplan(ctx, req.Dialplan, bytes)
-	return err
+	rows, err := s.repo.UpdateDialplan(ctx, req.Dialplan, bytes)
+	return affected(rows, err, ResourceDialplan, req.Dialplan.Id)
 }
 
 func (s *Service) DeleteDialplan(ctx context.Context, id string) error {
-	_, err := s.repo.DeleteDialplan(ctx, id)
-	return err
+	rows, err := s.repo.DeleteDialplan(ctx, id)
+	return affected(rows, err, ResourceDialplan, id)
 }
 
 func (s *Service) ListDialplans(ctx context.Context, req *dialplanv1.ListDialplansRequest) (*dialplanv1.ListDialplansResponse, error) {
@@ -328,21 +645,34 @@ func (s *Service) ListDialplans(ctx context.Context, req *dialplanv1.ListDialpla
 }
 
 func (s *Service) CreateQueue(ctx context.Context, req *dialplanv1.CreateQueueRequest) error {
-	return s.repo.CreateQueue(ctx, req.Queue)
+	if err := validateQueue(req.Queue); err != nil {
+		return err
+	}
+	if err := s.checkFallbackChain(ctx, req.Queue); err != nil {
+		return err
+	}
+	return resourceErr(s.repo.CreateQueue(ctx, req.Queue), ResourceQueue, req.Queue.Id)
 }
 
 func (s *Service) GetQueue(ctx context.Context, id string) (*dialplanv1.Queue, error) {
-	return s.repo.GetQueue(ctx, id)
+	q, err := s.repo.GetQueue(ctx, id)
+	return q, resourceErr(err, ResourceQueue, id)
 }
 
 func (s *Service) UpdateQueue(ctx context.Context, req *dialplanv1.UpdateQueueRequest) error {
-	_, err := s.repo.UpdateQueue(ctx, req.Queue)
-	return err
+	if err := validateQueue(req.Queue); err != nil {
+		return err
+	}
+	if err := s.checkFallbackChain(ctx, req.Queue); err != nil {
+		return err
+	}
+	rows, err := s.repo.UpdateQueue(ctx, req.Queue)
+	return affected(rows, err, ResourceQueue, req.Queue.Id)
 }
 
 func (s *Service) DeleteQueue(ctx context.Context, id string) error {
-	_, err := s.repo.DeleteQueue(ctx, id)
-	return err
+	rows, err := s.repo.DeleteQueue(ctx, id)
+	return affected(rows, err, ResourceQueue, id)
 }
 
 func (s *Service) ListQueues(ctx context.Context, req *dialplanv1.ListQueuesRequest) (*dialplanv1.ListQueuesResponse, error) {
@@ -355,9 +685,152 @@ func (s *Service) ListQueues(ctx context.Context, req *dialplanv1.ListQueuesRequ
 }
 
 func (s *Service) CreateSchedule(ctx context.Context, req *dialplanv1.CreateScheduleRequest) error {
-	return s.repo.CreateSchedule(ctx, req.Schedule)
+	sched, err := validateSchedule(req.Schedule, logger.ContextLogger(ctx, s.baseLog))
+	if err != nil {
+		return err
+	}
+	if err := s.checkCalendarRefs(ctx, sched); err != nil {
+		return err
+	}
+	return resourceErr(s.repo.CreateSchedule(ctx, req.Schedule), ResourceSchedule, req.Schedule.Id)
 }
 
 func (s *Service) GetSchedule(ctx context.Context, id string) (*dialplanv1.Schedule, error) {
-	return s.repo.GetSchedule(ctx, id)
+	sched, err := s.repo.GetSchedule(ctx, id)
+	return sched, resourceErr(err, ResourceSchedule, id)
+}
+
+func (s *Service) UpdateSchedule(ctx context.Context, req *dialplanv1.UpdateScheduleRequest) error {
+	sched, err := validateSchedule(req.Schedule, logger.ContextLogger(ctx, s.baseLog))
+	if err != nil {
+		return err
+	}
+	if err := s.checkCalendarRefs(ctx, sched); err != nil {
+		return err
+	}
+	rows, err := s.repo.UpdateSchedule(ctx, req.Schedule)
+	s.schedules.invalidate(req.Schedule.Id)
+	return affected(rows, err, ResourceSchedule, req.Schedule.Id)
+}
+
+// DeleteSchedule: Bir inbound route tarafından kullanılan takvimin silinmesini reddeder.
+func (s *Service) DeleteSchedule(ctx context.Context, id string) error {
+	inUse, err := s.repo.CountRoutesUsingSchedule(ctx, id)
+	if err != nil {
+		return resourceErr(err, ResourceSchedule, id)
+	}
+	if inUse > 0 {
+		return resourceErr(fmt.Errorf("%w: %d inbound route bu takvimi kullanıyor", ErrInUse, inUse), ResourceSchedule, id)
+	}
+	rows, err := s.repo.DeleteSchedule(ctx, id)
+	s.schedules.invalidate(id)
+	return affected(rows, err, ResourceSchedule, id)
+}
+
+func (s *Service) ListSchedules(ctx context.Context, req *dialplanv1.ListSchedulesRequest) (*dialplanv1.ListSchedulesResponse, error) {
+	list, err := s.repo.ListSchedules(ctx, req.TenantId, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	count, _ := s.repo.CountSchedules(ctx, req.TenantId)
+	return &dialplanv1.ListSchedulesResponse{Schedules: list, TotalCount: count}, nil
+}
+
+// dialplanForState: Takvim durumuna karşılık gelen dialplan'ı seçer.
+// Önce route.state_dialplan_ids eşlemesine bakılır; yoksa geriye dönük ikili davranış geçerlidir:
+// "open" => ActiveDialplanId, diğer durumlar => OffHoursDialplanId (tanımsızsa ActiveDialplanId).
+func dialplanForState(route *dialplanv1.InboundRoute, state string) *string {
+	if id, ok := route.StateDialplanIds[state]; ok && id != "" {
+		return &id
+	}
+	if state != StateOpen && route.OffHoursDialplanId != nil && *route.OffHoursDialplanId != "" {
+		return route.OffHoursDialplanId
+	}
+	return route.ActiveDialplanId
+}
+
+// checkCalendarRefs: Takvimin referans verdiği tatil takvimlerinin var olduğunu doğrular.
+func (s *Service) checkCalendarRefs(ctx context.Context, sched *ScheduleDefinition) error {
+	var v violations
+	for i, id := range sched.Calendars {
+		if _, err := s.repo.GetHolidayCalendar(ctx, id); err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				return resourceErr(err, ResourceHoliday, id)
+			}
+			v.add(fmt.Sprintf("schedule_json.calendars[%d]", i), "tatil takvimi bulunamadı: %s", id)
+		}
+	}
+	return v.err()
+}
+
+// loadCalendarRules: Takvimin referans verdiği tatil takvimlerinin kurallarını toplar.
+// Yüklenemeyen takvim loglanır ve atlanır; çağrı akışı durdurulmaz ancak ok false döner.
+func (s *Service) loadCalendarRules(ctx context.Context, ids []string, l zerolog.Logger) (rules []HolidayRule, ok bool) {
+	ok = true
+	for _, id := range ids {
+		c, err := s.repo.GetHolidayCalendar(ctx, id)
+		if err != nil {
+			l.Warn().Err(err).
+				Str("event", logger.EventHolidayCalendarLoadFailed).
+				Str("calendar_id", id).
+				Msg("Tatil takvimi yüklenemedi, atlanıyor.")
+			ok = false
+			continue
+		}
+		var def HolidayCalendarDefinition
+		if err := json.Unmarshal([]byte(c.CalendarJson), &def); err != nil {
+			l.Error().Err(err).
+				Str("event", logger.EventHolidayCalendarLoadFailed).
+				Str("calendar_id", id).
+				Msg("Tatil takvimi JSON parse hatası, atlanıyor.")
+			continue
+		}
+		rules = append(rules, def.Rules...)
+	}
+	return rules, ok
+}
+
+func (s *Service) CreateHolidayCalendar(ctx context.Context, req *dialplanv1.CreateHolidayCalendarRequest) error {
+	if err := validateHolidayCalendar(req.Calendar, logger.ContextLogger(ctx, s.baseLog)); err != nil {
+		return err
+	}
+	return resourceErr(s.repo.CreateHolidayCalendar(ctx, req.Calendar), ResourceHoliday, req.Calendar.Id)
+}
+
+func (s *Service) GetHolidayCalendar(ctx context.Context, id string) (*dialplanv1.HolidayCalendar, error) {
+	c, err := s.repo.GetHolidayCalendar(ctx, id)
+	return c, resourceErr(err, ResourceHoliday, id)
+}
+
+func (s *Service) UpdateHolidayCalendar(ctx context.Context, req *dialplanv1.UpdateHolidayCalendarRequest) error {
+	if err := validateHolidayCalendar(req.Calendar, logger.ContextLogger(ctx, s.baseLog)); err != nil {
+		return err
+	}
+	rows, err := s.repo.UpdateHolidayCalendar(ctx, req.Calendar)
+	// Tatil takvimi birden çok takvim tarafından paylaşıldığından tüm önbellek düşürülür.
+	s.schedules.purge()
+	return affected(rows, err, ResourceHoliday, req.Calendar.Id)
+}
+
+// DeleteHolidayCalendar: Bir takvim tarafından referans verilen tatil takviminin silinmesini reddeder.
+func (s *Service) DeleteHolidayCalendar(ctx context.Context, id string) error {
+	inUse, err := s.repo.CountSchedulesUsingCalendar(ctx, id)
+	if err != nil {
+		return resourceErr(err, ResourceHoliday, id)
+	}
+	if inUse > 0 {
+		return resourceErr(fmt.Errorf("%w: %d takvim bu tatil takvimini kullanıyor", ErrInUse, inUse), ResourceHoliday, id)
+	}
+	rows, err := s.repo.DeleteHolidayCalendar(ctx, id)
+	s.schedules.purge()
+	return affected(rows, err, ResourceHoliday, id)
+}
+
+func (s *Service) ListHolidayCalendars(ctx context.Context, req *dialplanv1.ListHolidayCalendarsRequest) (*dialplanv1.ListHolidayCalendarsResponse, error) {
+	list, err := s.repo.ListHolidayCalendars(ctx, req.TenantId, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	count, _ := s.repo.CountHolidayCalendars(ctx, req.TenantId)
+	return &dialplanv1.ListHolidayCalendarsResponse{Calendars: list, TotalCount: count}, nil
 }
@@ -0,0 +1,155 @@
+// sentiric-dialplan-service/internal/service/dialplan/trace.go
+package dialplan
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand/v2"
+	"time"
+
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+	"github.com/sentiric/sentiric-dialplan-service/internal/logger"
+)
+
+// ResolveDialplan karar adımları.
+const (
+	TraceStepRateLimit          = "rate_limit"
+	TraceStepRouteLookup        = "route_lookup"
+	TraceStepAnonymousCheck     = "anonymous_check"
+	TraceStepCallerListCheck    = "caller_list_check"
+	TraceStepMaintenanceCheck   = "maintenance_check"
+	TraceStepCallCapacity       = "call_capacity"
+	TraceStepScheduleEvaluation = "schedule_evaluation"
+	TraceStepDialplanFetch      = "dialplan_fetch"
+	TraceStepQueueCheck         = "queue_check"
+	TraceStepUserLookup         = "user_lookup"
+	TraceStepUserProvisioning   = "user_provisioning"
+	TraceStepQueuePriority      = "queue_priority"
+	TraceStepFailsafeFallback   = "failsafe_fallback"
+)
+
+// TraceStep: Karar zincirindeki tek bir adım, girdileri ve sonucu.
+type TraceStep struct {
+	Step    string            `json:"step"`
+	Outcome string            `json:"outcome"`
+	Inputs  map[string]string `json:"inputs,omitempty"`
+}
+
+// ProvisionPreview: Yan etkisiz modda oluşturulmuş OLACAK misafir profilinin özeti.
+type ProvisionPreview struct {
+	TenantID              string `json:"tenant_id"`
+	UserType              string `json:"user_type"`
+	Name                  string `json:"name"`
+	ContactType           string `json:"contact_type"`
+	ContactValue          string `json:"contact_value"`
+	PreferredLanguageCode string `json:"preferred_language_code"`
+}
+
+// DecisionTrace: Bir ResolveDialplan çağrısının yapılandırılmış karar izi ("explain").
+type DecisionTrace struct {
+	TraceID        string            `json:"trace_id"`
+	Caller         string            `json:"caller"`
+	Destination    string            `json:"destination"`
+	EvaluatedAt    time.Time         `json:"evaluated_at"`
+	Simulated      bool              `json:"simulated"`
+	DryRun         bool              `json:"dry_run"`
+	Steps          []TraceStep       `json:"steps"`
+	DialplanID     string            `json:"dialplan_id,omitempty"`
+	WouldProvision *ProvisionPreview `json:"would_provision,omitempty"`
+	Error          string            `json:"error,omitempty"`
+}
+
+// record: Bir adım ekler. kv, "anahtar", "değer" çiftleri halinde girdilerdir. nil trace güvenlidir.
+func (t *DecisionTrace) record(step, outcome string, kv ...string) {
+	if t == nil {
+		return
+	}
+	ts := TraceStep{Step: step, Outcome: outcome}
+	if len(kv) > 1 {
+		ts.Inputs = make(map[string]string, len(kv)/2)
+		for i := 0; i+1 < len(kv); i += 2 {
+			ts.Inputs[kv[i]] = kv[i+1]
+		}
+	}
+	t.Steps = append(t.Steps, ts)
+}
+
+func (t *DecisionTrace) finish(resp *dialplanv1.ResolveDialplanResponse, err error) {
+	if err != nil {
+		t.Error = err.Error()
+		return
+	}
+	t.DialplanID = resp.GetDialplanId()
+}
+
+// TraceStore: Karar izlerinin (trace_id bazında) sonradan "explain" için saklandığı yer.
+// Uygulaması Redis tabanlıdır (cache.TraceCache); tüm replikalar aynı izi görür.
+type TraceStore interface {
+	SaveTrace(ctx context.Context, traceID string, data []byte) error
+	GetTrace(ctx context.Context, traceID string) ([]byte, error)
+}
+
+// WithTraceStore: Karar izlerinin saklanmasını etkinleştirir. İzler yalnızca istek bazında
+// (ResolveOptions.PersistTrace) ya da WithTraceSampleRate ile örneklenerek yazılır.
+func WithTraceStore(store TraceStore) Option {
+	return func(s *Service) {
+		s.traceStore = store
+	}
+}
+
+// WithTraceSampleRate: İstenmemiş olsa da saklanacak çağrıların oranı (0 => kapalı, 1 => tümü).
+func WithTraceSampleRate(rate float64) Option {
+	return func(s *Service) {
+		s.traceRate = min(max(rate, 0), 1)
+	}
+}
+
+// sampleTrace: Bu çağrının izi örneklemeye girdi mi?
+func (s *Service) sampleTrace() bool {
+	return s.traceRate > 0 && rand.Float64() < s.traceRate
+}
+
+// storeTrace: İzi trace_id ile saklar. Hata çağrıyı etkilemez (best-effort).
+func (s *Service) storeTrace(ctx context.Context, t *DecisionTrace) {
+	if s.traceStore == nil || t.TraceID == "" || t.TraceID == "unknown" {
+		return
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return
+	}
+	if err := s.traceStore.SaveTrace(ctx, t.TraceID, data); err != nil {
+		l := logger.ContextLogger(ctx, s.baseLog)
+		l.Warn().Err(err).Str("event", logger.EventTraceStoreFailed).Msg("Karar izi saklanamadı.")
+	}
+}
+
+// GetDecisionTrace: Daha önce çözülmüş bir çağrının karar izini trace_id ile döner.
+func (s *Service) GetDecisionTrace(ctx context.Context, traceID string) (*DecisionTrace, error) {
+	if s.traceStore == nil {
+		return nil, resourceErr(ErrNotFound, ResourceDecisionTrace, traceID)
+	}
+	data, err := s.traceStore.GetTrace(ctx, traceID)
+	if err != nil {
+		return nil, resourceErr(err, ResourceDecisionTrace, traceID)
+	}
+	if data == nil {
+		return nil, resourceErr(ErrNotFound, ResourceDecisionTrace, traceID)
+	}
+	var t DecisionTrace
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, resourceErr(err, ResourceDecisionTrace, traceID)
+	}
+	return &t, nil
+}
+
+// ExplainDialplan: Verilen çağrının at anında nasıl yönleneceğini yan etkisiz olarak
+// değerlendirir ve karar izini döner. at sıfırsa servis saatinin şimdiki anı kullanılır.
+func (s *Service) ExplainDialplan(ctx context.Context, caller, destination string, at time.Time) (*dialplanv1.ResolveDialplanResponse, *DecisionTrace, error) {
+	if at.IsZero() {
+		at = s.clock.Now()
+	}
+	trace := &DecisionTrace{}
+	resp, err := s.ResolveDialplan(ctx, caller, destination, ResolveOptions{SimulateAt: at, Trace: trace})
+	return resp, trace, err
+}
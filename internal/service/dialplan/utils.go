@@ -19,6 +19,23 @@ func toPtr(s string) *string {
 	return &s
 }
 
+// uniqueNonEmpty: Sırayı koruyarak boş ve tekrar eden değerleri ayıklar.
+func uniqueNonEmpty(values []string) []string {
+	out := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	return out
+}
+
 // MapStringToActionType: Veritabanındaki string aksiyonu Protobuf Enum'a çevirir.
 func MapStringToActionType(action string) dialplanv1.ActionType {
 	switch strings.ToUpper(action) {
@@ -30,6 +47,10 @@ func MapStringToActionType(action string) dialplanv1.ActionType {
 		return dialplanv1.ActionType_ACTION_TYPE_ECHO_TEST
 	case "PLAY_ANNOUNCEMENT", "PLAY_STATIC_ANNOUNCEMENT":
 		return dialplanv1.ActionType_ACTION_TYPE_PLAY_STATIC_ANNOUNCEMENT
+	case ActionRouteToQueue:
+		return dialplanv1.ActionType_ACTION_TYPE_ROUTE_TO_QUEUE
+	case ActionVoicemail:
+		return dialplanv1.ActionType_ACTION_TYPE_VOICEMAIL
 	default:
 		return dialplanv1.ActionType_ACTION_TYPE_UNSPECIFIED
 	}
@@ -58,7 +79,11 @@ func extractUserPart(raw string) string {
 		s = s[:semiIndex]
 	}
 
+	// E.164 '+' işareti korunur; ülke kuralları NumberingPlan.Normalize içinde uygulanır.
 	var sb strings.Builder
+	if strings.HasPrefix(strings.TrimSpace(s), "+") {
+		sb.WriteByte('+')
+	}
 	for _, r := range s {
 		if unicode.IsDigit(r) {
 			sb.WriteRune(r)
@@ -66,31 +91,3 @@ func extractUserPart(raw string) string {
 	}
 	return sb.String()
 }
-
-// normalizePhoneNumber, telefon numarasını standart E.164 (90...) formatına getirir.
-func normalizePhoneNumber(phone string) string {
-	if phone == "anonymous" {
-		return phone
-	}
-	var sb strings.Builder
-	for _, ch := range phone {
-		if unicode.IsDigit(ch) {
-			sb.WriteRune(ch)
-		}
-	}
-	cleaned := sb.String()
-
-	if cleaned == "" {
-		return phone
-	}
-	if len(cleaned) == 12 && strings.HasPrefix(cleaned, "90") {
-		return cleaned
-	}
-	if len(cleaned) == 11 && strings.HasPrefix(cleaned, "0") {
-		return "90" + cleaned[1:]
-	}
-	if len(cleaned) == 10 {
-		return "90" + cleaned
-	}
-	return cleaned
-}
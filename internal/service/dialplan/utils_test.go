@@ -0,0 +1,25 @@
+package dialplan
+
+import (
+	"testing"
+
+	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
+)
+
+func TestMapStringToActionType(t *testing.T) {
+	tests := map[string]dialplanv1.ActionType{
+		"START_AI_CONVERSATION": dialplanv1.ActionType_ACTION_TYPE_START_AI_CONVERSATION,
+		"bridge_call":           dialplanv1.ActionType_ACTION_TYPE_BRIDGE_CALL,
+		"ECHO":                  dialplanv1.ActionType_ACTION_TYPE_ECHO_TEST,
+		"PLAY_ANNOUNCEMENT":     dialplanv1.ActionType_ACTION_TYPE_PLAY_STATIC_ANNOUNCEMENT,
+		ActionRouteToQueue:      dialplanv1.ActionType_ACTION_TYPE_ROUTE_TO_QUEUE,
+		"route_to_queue":        dialplanv1.ActionType_ACTION_TYPE_ROUTE_TO_QUEUE,
+		ActionVoicemail:         dialplanv1.ActionType_ACTION_TYPE_VOICEMAIL,
+		"SOMETHING_ELSE":        dialplanv1.ActionType_ACTION_TYPE_UNSPECIFIED,
+	}
+	for action, want := range tests {
+		if got := MapStringToActionType(action); got != want {
+			t.Errorf("MapStringToActionType(%q) = %v, want %v", action, got, want)
+		}
+	}
+}
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_inbound_routes_patterns;
@@ -0,0 +1,9 @@
+-- sentiric-dialplan-service: Prefix ve range route anahtarları için kısmi indeks.
+-- Mevcut tablolar (inbound_routes, dialplans, queues, schedules) platform şemasından gelir.
+
+-- Prefix ("90212555*") ve range ("902125550000-902125559999") route'ları exact numaralara göre azdır;
+-- FindPatternRoutesForNumber ve çakışma kontrolü yalnızca bu kısmi indeksi tarar.
+-- Çakışma kontrolü route yazımlarını pg_advisory_xact_lock ile sıraladığından ayrıca exclusion constraint gerekmez.
+CREATE INDEX IF NOT EXISTS idx_inbound_routes_patterns
+    ON inbound_routes (phone_number)
+    WHERE phone_number LIKE '%*' OR phone_number LIKE '%-%';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_schedules_calendars;
+DROP TABLE IF EXISTS holiday_calendars;
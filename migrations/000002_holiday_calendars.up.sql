@@ -0,0 +1,12 @@
+-- Birden fazla takvimin "calendars" alanından referans verdiği paylaşımlı tatil takvimleri.
+
+CREATE TABLE IF NOT EXISTS holiday_calendars (
+    id            TEXT PRIMARY KEY,
+    tenant_id     TEXT NOT NULL,
+    name          TEXT NOT NULL,
+    calendar_data JSONB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_holiday_calendars_tenant ON holiday_calendars (tenant_id);
+
+-- CountSchedulesUsingCalendar: schedule_data->'calendars' ? $1
+CREATE INDEX IF NOT EXISTS idx_schedules_calendars ON schedules USING GIN ((schedule_data -> 'calendars'));
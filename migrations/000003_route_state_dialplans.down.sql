@@ -0,0 +1 @@
+ALTER TABLE inbound_routes DROP COLUMN IF EXISTS state_dialplans;
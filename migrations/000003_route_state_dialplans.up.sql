@@ -0,0 +1,2 @@
+-- Takvim durumu -> dialplan eşlemesi (örn: {"lunch_break": "DP_LUNCH"}); NULL => ikili açık/kapalı davranış.
+ALTER TABLE inbound_routes ADD COLUMN IF NOT EXISTS state_dialplans JSONB;
@@ -0,0 +1,14 @@
+-- Süreli takvim istisnaları (zorla açık / zorla kapalı). Süresi dolanlar PurgeExpiredOverrides ile silinir.
+
+CREATE TABLE IF NOT EXISTS schedule_overrides (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    schedule_id TEXT NOT NULL REFERENCES schedules (id) ON DELETE CASCADE,
+    mode        TEXT NOT NULL CHECK (mode IN ('forced_open', 'forced_closed')),
+    reason      TEXT NOT NULL DEFAULT '',
+    starts_at   TIMESTAMPTZ NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (expires_at > starts_at)
+);
+CREATE INDEX IF NOT EXISTS idx_schedule_overrides_schedule ON schedule_overrides (schedule_id, expires_at);
+CREATE INDEX IF NOT EXISTS idx_schedule_overrides_expires ON schedule_overrides (expires_at);
@@ -0,0 +1 @@
+ALTER TABLE schedule_overrides DROP CONSTRAINT IF EXISTS uq_schedule_overrides_window;
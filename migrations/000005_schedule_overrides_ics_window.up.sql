@@ -0,0 +1,4 @@
+-- ICS ithalatı aynı aralık ve açıklamadaki istisnayı tekrar eklemez; ImportSchedule'ın NOT EXISTS denetimini eşzamanlı ithalatlara karşı bu kısıt tamamlar.
+
+ALTER TABLE schedule_overrides
+    ADD CONSTRAINT uq_schedule_overrides_window UNIQUE (schedule_id, starts_at, expires_at, reason);
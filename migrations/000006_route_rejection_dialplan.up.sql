@@ -0,0 +1,2 @@
+-- Gizli arayanlar (block_anonymous) için route'a özel ret dialplan'ı; NULL => DP_SYSTEM_ANONYMOUS_REJECT.
+ALTER TABLE inbound_routes ADD COLUMN IF NOT EXISTS rejection_dialplan_id TEXT REFERENCES dialplans (id);
@@ -0,0 +1,2 @@
+ALTER TABLE inbound_routes DROP COLUMN IF EXISTS blocked_caller_dialplan_id;
+DROP TABLE IF EXISTS caller_list_entries;
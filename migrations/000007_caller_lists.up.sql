@@ -0,0 +1,24 @@
+-- Arayan engelleme / izin listeleri ve listeye takılan arayanlar için route'a özel dialplan.
+
+CREATE TABLE IF NOT EXISTS caller_list_entries (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id          TEXT NOT NULL,
+    -- NULL => tenant geneli; dolu => yalnızca bu route
+    route_phone_number TEXT REFERENCES inbound_routes (phone_number) ON DELETE CASCADE ON UPDATE CASCADE,
+    list_type          TEXT NOT NULL CHECK (list_type IN ('block', 'allow')),
+    -- E.164 (öneksiz) numara veya sonda '*' ile prefix ("90850*")
+    number             TEXT NOT NULL,
+    reason             TEXT NOT NULL DEFAULT '',
+    expires_at         TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Aynı kapsamda aynı numara tek kayıttır; ImportCallerListEntries'in ON CONFLICT DO NOTHING'i bu indekse dayanır.
+-- Tenant geneli kayıtlar (route_phone_number NULL) da tekil olsun diye COALESCE kullanılır.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_caller_list_entries_scope
+    ON caller_list_entries (tenant_id, COALESCE(route_phone_number, ''), list_type, number);
+-- MatchCallerList / HasActiveAllowList
+CREATE INDEX IF NOT EXISTS idx_caller_list_entries_lookup ON caller_list_entries (tenant_id, list_type, number);
+
+-- NULL => DP_SYSTEM_CALLER_BLOCKED
+ALTER TABLE inbound_routes ADD COLUMN IF NOT EXISTS blocked_caller_dialplan_id TEXT REFERENCES dialplans (id);
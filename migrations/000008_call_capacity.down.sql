@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS tenant_call_limits;
+ALTER TABLE inbound_routes
+    DROP COLUMN IF EXISTS max_concurrent_calls,
+    DROP COLUMN IF EXISTS lines_busy_dialplan_id;
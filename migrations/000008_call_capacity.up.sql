@@ -0,0 +1,10 @@
+-- Eşzamanlı çağrı limitleri: route bazında (0 => sınırsız) ve tenant bazında; kapasite dolduğunda uygulanacak dialplan.
+
+ALTER TABLE inbound_routes
+    ADD COLUMN IF NOT EXISTS lines_busy_dialplan_id TEXT REFERENCES dialplans (id),
+    ADD COLUMN IF NOT EXISTS max_concurrent_calls   INTEGER NOT NULL DEFAULT 0 CHECK (max_concurrent_calls >= 0);
+
+CREATE TABLE IF NOT EXISTS tenant_call_limits (
+    tenant_id            TEXT PRIMARY KEY,
+    max_concurrent_calls INTEGER NOT NULL CHECK (max_concurrent_calls >= 0)
+);
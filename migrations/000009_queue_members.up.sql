@@ -0,0 +1,8 @@
+-- Kuyruk üyeleri; ajan durumları veritabanında değil Redis'te tutulur.
+
+CREATE TABLE IF NOT EXISTS queue_members (
+    queue_id TEXT NOT NULL REFERENCES queues (id) ON DELETE CASCADE,
+    agent_id TEXT NOT NULL,
+    PRIMARY KEY (queue_id, agent_id)
+);
+CREATE INDEX IF NOT EXISTS idx_queue_members_agent ON queue_members (agent_id);
@@ -0,0 +1,4 @@
+ALTER TABLE queues
+    DROP COLUMN IF EXISTS skill_relax_seconds,
+    DROP COLUMN IF EXISTS skill_requirements;
+DROP TABLE IF EXISTS agent_skills;
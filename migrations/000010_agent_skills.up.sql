@@ -0,0 +1,15 @@
+-- Ajan yetenekleri ve kuyrukların yetenek gereksinimleri (skill-based routing).
+
+CREATE TABLE IF NOT EXISTS agent_skills (
+    agent_id TEXT NOT NULL,
+    -- "lang:tr", "billing" ...
+    skill    TEXT NOT NULL,
+    level    INTEGER NOT NULL CHECK (level BETWEEN 1 AND 10),
+    PRIMARY KEY (agent_id, skill)
+);
+
+-- skill_requirements: [{"skill": "lang:tr", "min_level": 3}] ; NULL => yetenek filtresi yok.
+-- skill_relax_seconds: Her bu kadar saniyelik bekleme gereksinim seviyelerini bir düşürür (0 => gevşetme yok).
+ALTER TABLE queues
+    ADD COLUMN IF NOT EXISTS skill_requirements  JSONB,
+    ADD COLUMN IF NOT EXISTS skill_relax_seconds INTEGER NOT NULL DEFAULT 0 CHECK (skill_relax_seconds >= 0);
@@ -0,0 +1,8 @@
+-- Öncelik kayıtları eski CHECK'i ihlal eder; kısıt geri yüklenmeden önce silinir.
+DELETE FROM caller_list_entries WHERE list_type = 'priority';
+ALTER TABLE caller_list_entries
+    DROP CONSTRAINT IF EXISTS caller_list_entries_list_type_check,
+    DROP COLUMN IF EXISTS priority;
+ALTER TABLE caller_list_entries
+    ADD CONSTRAINT caller_list_entries_list_type_check CHECK (list_type IN ('block', 'allow'));
+ALTER TABLE inbound_routes DROP COLUMN IF EXISTS queue_priority;
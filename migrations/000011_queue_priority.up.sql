@@ -0,0 +1,12 @@
+-- Kuyruk öncelik sınıfları (normal, returning, high, vip): route'un varsayılan sınıfı ve arayana özel
+-- "priority" listesi kayıtları.
+
+-- Boş => normal
+ALTER TABLE inbound_routes ADD COLUMN IF NOT EXISTS queue_priority TEXT NOT NULL DEFAULT '';
+
+-- list_type'ın satır içi CHECK'i Postgres tarafından caller_list_entries_list_type_check olarak adlandırılır.
+ALTER TABLE caller_list_entries DROP CONSTRAINT IF EXISTS caller_list_entries_list_type_check;
+ALTER TABLE caller_list_entries
+    ADD CONSTRAINT caller_list_entries_list_type_check CHECK (list_type IN ('block', 'allow', 'priority')),
+    -- Yalnızca list_type = 'priority' kayıtlarında dolu
+    ADD COLUMN IF NOT EXISTS priority TEXT NOT NULL DEFAULT '';